@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -61,6 +62,7 @@ type Cache interface {
 	SetProductStats(c *gin.Context, stats *ProductStats) error
 	GetRatingStats(c *gin.Context, productID uint) (*RatingStats, error)
 	SetRatingStats(c *gin.Context, productID uint, stats *RatingStats) error
+	DeleteRatingStats(ctx context.Context, productID uint) error
 }
 
 // RedisCache Redis缓存实现
@@ -280,12 +282,24 @@ type MostReviewedProduct struct {
 }
 
 type RatingStats struct {
-	ProductID     uint           `json:"productId"`
-	ProductName   string         `json:"productName"`
-	AverageRating float64        `json:"averageRating"`
-	TotalRatings  int64          `json:"totalRatings"`
-	RatingCounts  map[int]int64  `json:"ratingCounts"`
-	RecentReviews []RecentReview `json:"recentReviews"`
+	ProductID     uint            `json:"productId"`
+	VariantID     string          `json:"variantId,omitempty"` // 产品版本ID，为空表示整个产品
+	ProductName   string          `json:"productName"`
+	AverageRating float64         `json:"averageRating"`
+	TotalRatings  int64           `json:"totalRatings"`
+	RatingCounts  map[int]int64   `json:"ratingCounts"`
+	RecentReviews []RecentReview  `json:"recentReviews"`
+	Dimensions    []DimensionStat `json:"dimensions"` // 维度评分雷达图数据
+}
+
+// DimensionStat 产品在单个维度上的评分汇总
+type DimensionStat struct {
+	DimensionID string  `json:"dimensionId"`
+	Key         string  `json:"key"`
+	Name        string  `json:"name"`
+	Average     float64 `json:"average"` // 已发布测评的平均分
+	Count       int64   `json:"count"`   // 参与评分的测评数
+	Max         float64 `json:"max"`     // 满分，便于绘制雷达图
 }
 
 type RecentReview struct {
@@ -383,6 +397,12 @@ func (c *RedisCache) SetRatingStats(ctx *gin.Context, productID uint, stats *Rat
 	return c.client.Set(ctx, key, data, StatsExpiration).Err()
 }
 
+// DeleteRatingStats 删除评分统计数据缓存，后台任务没有请求上下文，因此接收 context.Context
+func (c *RedisCache) DeleteRatingStats(ctx context.Context, productID uint) error {
+	key := RatingStatsPrefix + strconv.FormatUint(uint64(productID), 10)
+	return c.client.Del(ctx, key).Err()
+}
+
 // BuildListKey 构建列表缓存键
 func BuildListKey(params map[string]interface{}) string {
 	key := ""
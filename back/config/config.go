@@ -10,14 +10,21 @@ import (
 )
 
 type Config struct {
-	Server    ServerConfig    `yaml:"server"`
-	Database  DatabaseConfig  `yaml:"database"`
-	Redis     RedisConfig     `yaml:"redis"`
-	Email     EmailConfig     `yaml:"email"`
-	JWT       JWTConfig       `yaml:"jwt"`
-	Turnstile TurnstileConfig `yaml:"turnstile"`
-	RateLimit RateLimitConfig `yaml:"rateLimit"`
-	Storage   StorageConfig   `yaml:"storage"`
+	Server       ServerConfig       `yaml:"server"`
+	Database     DatabaseConfig     `yaml:"database"`
+	Redis        RedisConfig        `yaml:"redis"`
+	Email        EmailConfig        `yaml:"email"`
+	JWT          JWTConfig          `yaml:"jwt"`
+	Turnstile    TurnstileConfig    `yaml:"turnstile"`
+	RateLimit    RateLimitConfig    `yaml:"rateLimit"`
+	Storage      StorageConfig      `yaml:"storage"`
+	Trash        TrashConfig        `yaml:"trash"`
+	Review       ReviewConfig       `yaml:"review"`
+	Views        ViewsConfig        `yaml:"views"`
+	Moderation   ModerationConfig   `yaml:"moderation"`
+	Notification NotificationConfig `yaml:"notification"`
+	Realtime     RealtimeConfig     `yaml:"realtime"`
+	Account      AccountConfig      `yaml:"account"`
 }
 
 type ServerConfig struct {
@@ -110,6 +117,80 @@ type StorageConfig struct {
 	BaseURL string `yaml:"baseUrl"`
 }
 
+// TrashConfig 回收站配置
+type TrashConfig struct {
+	// 软删除记录的保留天数，超过后自动彻底删除
+	RetentionDays int `yaml:"retentionDays"`
+	// 自动清理的执行间隔
+	PurgeInterval time.Duration `yaml:"purgeInterval"`
+}
+
+// ReviewConfig 测评流程配置
+type ReviewConfig struct {
+	// 定时发布的检查间隔
+	PublishInterval time.Duration `yaml:"publishInterval"`
+	// 每个用户每天可投稿的测评数量
+	SubmissionDailyQuota int `yaml:"submissionDailyQuota"`
+	// 每个用户同时处于待审核状态的投稿上限
+	MaxPendingSubmissions int `yaml:"maxPendingSubmissions"`
+}
+
+// ViewsConfig 浏览量统计配置
+type ViewsConfig struct {
+	// 同一访客重复访问不重复计数的时间窗口
+	DedupWindow time.Duration `yaml:"dedupWindow"`
+	// 缓冲的浏览量写入数据库的间隔
+	FlushInterval time.Duration `yaml:"flushInterval"`
+	// 额外需要过滤的爬虫 User-Agent 关键字
+	BotUserAgents []string `yaml:"botUserAgents"`
+}
+
+// ModerationConfig 评论自动审核配置
+type ModerationConfig struct {
+	// 敏感词词典文件，每行一个词，# 开头为注释；以 ",review" 结尾的词转人工审核，其余直接拒绝
+	SensitiveWordsFile string `yaml:"sensitiveWordsFile"`
+	// 单条评论允许的最多链接数，超过后转人工审核
+	MaxLinks int `yaml:"maxLinks"`
+	// 同一用户在该时间窗口内重复发布相同内容视为刷屏
+	DuplicateWindow time.Duration `yaml:"duplicateWindow"`
+	// 新用户累计通过的评论数达到该值后自动升级为可信用户
+	TrustedApprovedComments int `yaml:"trustedApprovedComments"`
+	// 被该数量的不同用户举报后自动隐藏内容，等待管理员处理
+	ReportHideThreshold int `yaml:"reportHideThreshold"`
+}
+
+// NotificationConfig 通知配置
+type NotificationConfig struct {
+	// 未读通知邮件摘要的发送间隔
+	DigestInterval time.Duration `yaml:"digestInterval"`
+	// 每封摘要邮件最多包含的通知数
+	DigestMaxItems int `yaml:"digestMaxItems"`
+}
+
+// RealtimeConfig 实时推送（SSE）配置
+type RealtimeConfig struct {
+	// 连接空闲时发送心跳的间隔，避免被代理断开
+	HeartbeatInterval time.Duration `yaml:"heartbeatInterval"`
+	// 每个连接缓冲的事件数，客户端消费过慢时丢弃新事件
+	ClientBuffer int `yaml:"clientBuffer"`
+	// 每个连接最多订阅的主题数
+	MaxTopics int `yaml:"maxTopics"`
+}
+
+// AccountConfig 个人数据导出和账号注销配置
+type AccountConfig struct {
+	// 导出文件的存放目录，位于存储根路径下且不对外提供静态访问
+	ExportDir string `yaml:"exportDir"`
+	// 导出文件的保留时长，过期后删除
+	ExportRetention time.Duration `yaml:"exportRetention"`
+	// 两次导出之间的最短间隔
+	ExportCooldown time.Duration `yaml:"exportCooldown"`
+	// 注销申请的冷静期天数，期间可以撤销
+	DeletionGraceDays int `yaml:"deletionGraceDays"`
+	// 处理导出任务和到期注销的执行间隔
+	WorkerInterval time.Duration `yaml:"workerInterval"`
+}
+
 // LoadConfig 从文件加载配置
 func LoadConfig(configPath string) (*Config, error) {
 	data, err := os.ReadFile(configPath)
@@ -142,6 +223,69 @@ func LoadConfig(configPath string) (*Config, error) {
 	if config.Storage.MaxVideoSize == 0 {
 		config.Storage.MaxVideoSize = 500 * 1024 * 1024 // 默认 500MB
 	}
+	if config.Trash.RetentionDays == 0 {
+		config.Trash.RetentionDays = 30 // 默认保留 30 天
+	}
+	if config.Trash.PurgeInterval == 0 {
+		config.Trash.PurgeInterval = time.Hour // 默认每小时清理一次
+	}
+	if config.Review.PublishInterval == 0 {
+		config.Review.PublishInterval = time.Minute // 默认每分钟检查一次定时发布
+	}
+	if config.Review.SubmissionDailyQuota == 0 {
+		config.Review.SubmissionDailyQuota = 3 // 默认每天 3 篇
+	}
+	if config.Review.MaxPendingSubmissions == 0 {
+		config.Review.MaxPendingSubmissions = 5 // 默认最多 5 篇待审核
+	}
+	if config.Views.DedupWindow == 0 {
+		config.Views.DedupWindow = 30 * time.Minute // 默认 30 分钟内只计一次
+	}
+	if config.Views.FlushInterval == 0 {
+		config.Views.FlushInterval = time.Minute // 默认每分钟写入一次
+	}
+	if config.Moderation.MaxLinks == 0 {
+		config.Moderation.MaxLinks = 2 // 默认最多 2 个链接
+	}
+	if config.Moderation.DuplicateWindow == 0 {
+		config.Moderation.DuplicateWindow = 10 * time.Minute // 默认 10 分钟内不允许重复内容
+	}
+	if config.Moderation.TrustedApprovedComments == 0 {
+		config.Moderation.TrustedApprovedComments = 5 // 默认通过 5 条评论后升级
+	}
+	if config.Moderation.ReportHideThreshold == 0 {
+		config.Moderation.ReportHideThreshold = 3 // 默认 3 人举报后隐藏
+	}
+	if config.Notification.DigestInterval == 0 {
+		config.Notification.DigestInterval = 24 * time.Hour // 默认每天发送一次
+	}
+	if config.Notification.DigestMaxItems == 0 {
+		config.Notification.DigestMaxItems = 20 // 默认最多 20 条
+	}
+	if config.Realtime.HeartbeatInterval == 0 {
+		config.Realtime.HeartbeatInterval = 25 * time.Second // 默认 25 秒
+	}
+	if config.Realtime.ClientBuffer == 0 {
+		config.Realtime.ClientBuffer = 32 // 默认缓冲 32 条
+	}
+	if config.Realtime.MaxTopics == 0 {
+		config.Realtime.MaxTopics = 20 // 默认最多 20 个主题
+	}
+	if config.Account.ExportDir == "" {
+		config.Account.ExportDir = "exports"
+	}
+	if config.Account.ExportRetention == 0 {
+		config.Account.ExportRetention = 7 * 24 * time.Hour // 默认保留 7 天
+	}
+	if config.Account.ExportCooldown == 0 {
+		config.Account.ExportCooldown = 24 * time.Hour // 默认每天导出一次
+	}
+	if config.Account.DeletionGraceDays == 0 {
+		config.Account.DeletionGraceDays = 14 // 默认冷静期 14 天
+	}
+	if config.Account.WorkerInterval == 0 {
+		config.Account.WorkerInterval = time.Minute // 默认每分钟处理一次
+	}
 
 	return &config, nil
 }
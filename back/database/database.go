@@ -60,10 +60,28 @@ func InitDB(cfg *config.Config) error {
 
 	DB = db
 
+	if err := MigrateReviewSlugs(db); err != nil {
+		return fmt.Errorf("迁移测评 slug 失败: %w", err)
+	}
+	if err := MigrateUserFavoriteProductID(db); err != nil {
+		return fmt.Errorf("迁移收藏产品ID失败: %w", err)
+	}
+	if err := MigrateFileMD5Index(db); err != nil {
+		return fmt.Errorf("迁移文件MD5索引失败: %w", err)
+	}
 	// 自动迁移
 	if err := autoMigrate(db); err != nil {
 		return fmt.Errorf("自动迁移失败: %w", err)
 	}
+	if err := MigrateReviewStatus(db); err != nil {
+		return fmt.Errorf("迁移测评状态失败: %w", err)
+	}
+	if err := MigrateCommentStatus(db); err != nil {
+		return fmt.Errorf("迁移评论状态失败: %w", err)
+	}
+	if err := MigrateUserEmailVerified(db); err != nil {
+		return fmt.Errorf("迁移用户邮箱验证状态失败: %w", err)
+	}
 	if err := SeedData(db); err != nil {
 		return fmt.Errorf("无法播种数据: %w", err)
 	}
@@ -102,9 +120,17 @@ func autoMigrate(db *gorm.DB) error {
 	return db.AutoMigrate(
 		&model.User{},
 		&model.UserFavorite{},
+		&model.Collection{},
+		&model.CollectionItem{},
+		&model.DataExport{},
+		&model.AccountDeletion{},
 		&model.Brand{},
 		&model.Product{},
+		&model.ProductVariant{},
+		&model.ProductPriceChange{},
 		&model.Review{},
+		&model.ReviewRevision{},
+		&model.ReviewStatusLog{},
 		&model.Rating{},
 		&model.Tag{},
 		&model.ProductTag{},
@@ -113,8 +139,16 @@ func autoMigrate(db *gorm.DB) error {
 		&model.ChannelType{},
 		&model.MaterialType{},
 		&model.UtilityType{},
+		&model.ScoreDimension{},
+		&model.ReviewScore{},
 		&model.File{},
 		&model.Folder{},
-		
+		&model.SlugHistory{},
+		&model.Reaction{},
+		&model.ReportCase{},
+		&model.Report{},
+		&model.Notification{},
+		&model.NotificationSetting{},
+		&model.Follow{},
 	)
 }
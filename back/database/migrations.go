@@ -1,8 +1,11 @@
 package database
 
 import (
+	"strings"
+	"time"
+
 	"beicun/back/model"
-	"github.com/gosimple/slug"
+	"beicun/back/utils"
 	"gorm.io/gorm"
 )
 
@@ -21,20 +24,14 @@ func RunMigrations(db *gorm.DB) error {
 
 	for _, brand := range brands {
 		if brand.Slug == "" {
-			// 生成 slug
-			brandSlug := slug.Make(brand.Name)
-			
-			// 检查 slug 是否已存在
-			var count int64
-			db.Model(&model.Brand{}).Where("slug = ? AND id != ?", brandSlug, brand.ID).Count(&count)
-			
-			// 如果存在，添加数字后缀
-			counter := 1
-			finalSlug := brandSlug
-			for count > 0 {
-				finalSlug = slug.Make(brandSlug + "-" + string(counter))
-				db.Model(&model.Brand{}).Where("slug = ? AND id != ?", finalSlug, brand.ID).Count(&count)
-				counter++
+			// 生成 slug，如果已存在则添加数字后缀
+			finalSlug, err := utils.UniqueSlug(brand.Name, "brand", func(candidate string) (bool, error) {
+				var count int64
+				err := db.Model(&model.Brand{}).Where("slug = ? AND id != ?", candidate, brand.ID).Count(&count).Error
+				return count > 0, err
+			})
+			if err != nil {
+				return err
 			}
 
 			// 更新记录
@@ -54,3 +51,150 @@ func RunMigrations(db *gorm.DB) error {
 
 	return nil
 }
+
+// MigrateReviewStatus 将旧的测评状态迁移到编辑流程状态
+func MigrateReviewStatus(db *gorm.DB) error {
+	// 旧版本的 PENDING 表示未发布，统一转为草稿
+	if err := db.Model(&model.Review{}).
+		Where("status = ?", "PENDING").
+		Update("status", model.ReviewStatusDraft).Error; err != nil {
+		return err
+	}
+
+	// 旧版本发布时未记录发布时间，使用创建时间补齐
+	return db.Model(&model.Review{}).
+		Where("status = ? AND published_at IS NULL", model.ReviewStatusPublished).
+		Update("published_at", gorm.Expr("created_at")).Error
+}
+
+// MigrateCommentStatus 统一评论状态取值
+func MigrateCommentStatus(db *gorm.DB) error {
+	// 旧版本管理接口将通过的评论写为 PUBLISHED，且可能存在小写状态
+	if err := db.Model(&model.Comment{}).
+		Where("UPPER(status) IN ? AND status != ?", []string{"PUBLISHED", "APPROVED"}, model.CommentStatusApproved).
+		UpdateColumn("status", model.CommentStatusApproved).Error; err != nil {
+		return err
+	}
+	if err := db.Model(&model.Comment{}).
+		Where("UPPER(status) = ? AND status != ?", "REJECTED", model.CommentStatusRejected).
+		UpdateColumn("status", model.CommentStatusRejected).Error; err != nil {
+		return err
+	}
+
+	// 其余无法识别的状态重新进入待审核队列
+	return db.Model(&model.Comment{}).
+		Where("status IS NULL OR status NOT IN ?", []model.CommentStatus{
+			model.CommentStatusPending, model.CommentStatusApproved, model.CommentStatusRejected,
+		}).
+		UpdateColumn("status", model.CommentStatusPending).Error
+}
+
+// schemaMigration 已执行的一次性数据迁移
+type schemaMigration struct {
+	Name      string    `gorm:"type:varchar(100);primaryKey"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// runOnce 执行一次性数据迁移，迁移与标记在同一事务中写入，已标记的迁移不再执行
+func runOnce(db *gorm.DB, name string, migrate func(tx *gorm.DB) error) error {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&schemaMigration{}).Where("name = ?", name).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+		if err := migrate(tx); err != nil {
+			return err
+		}
+		return tx.Create(&schemaMigration{Name: name, AppliedAt: time.Now()}).Error
+	})
+}
+
+// MigrateUserEmailVerified 标记历史用户的邮箱验证状态
+func MigrateUserEmailVerified(db *gorm.DB) error {
+	// 注册流程一直要求邮箱验证码，但此前版本注册的用户未写入验证标记，首次升级时统一补齐
+	return runOnce(db, "backfill_user_email_verified", func(tx *gorm.DB) error {
+		return tx.Model(&model.User{}).
+			Where("is_email_verified = ?", false).
+			Update("is_email_verified", true).Error
+	})
+}
+
+// MigrateReviewSlugs 为重复或为空的测评 slug 重新生成唯一值，需在唯一索引建立前执行
+func MigrateReviewSlugs(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&model.Review{}) {
+		return nil
+	}
+
+	// 同一 slug 保留最早创建的测评，其余测评重新生成
+	var reviews []model.Review
+	if err := db.Unscoped().
+		Select("id", "title", "slug").
+		Where(`slug = '' OR slug IS NULL OR id NOT IN (
+			SELECT DISTINCT ON (slug) id FROM reviews ORDER BY slug, created_at ASC
+		)`).
+		Find(&reviews).Error; err != nil {
+		return err
+	}
+
+	for _, review := range reviews {
+		finalSlug, err := utils.UniqueSlug(review.Title, "review", func(candidate string) (bool, error) {
+			var count int64
+			err := db.Unscoped().Model(&model.Review{}).Where("slug = ?", candidate).Count(&count).Error
+			return count > 0, err
+		})
+		if err != nil {
+			return err
+		}
+		if err := db.Unscoped().Model(&review).Update("slug", finalSlug).Error; err != nil {
+			return err
+		}
+	}
+
+	// 旧版本的普通索引与唯一索引重复
+	if db.Migrator().HasIndex(&model.Review{}, "idx_reviews_slug") {
+		return db.Migrator().DropIndex(&model.Review{}, "idx_reviews_slug")
+	}
+	return nil
+}
+
+// MigrateFileMD5Index 删除旧的文件MD5唯一索引，由自动迁移重建为只约束未删除文件的部分索引，
+// 回收站中的文件保留记录和物理文件以便恢复
+func MigrateFileMD5Index(db *gorm.DB) error {
+	return db.Exec(`DROP INDEX IF EXISTS idx_files_md5`).Error
+}
+
+// MigrateUserFavoriteProductID 将收藏表的产品ID由 uuid 改为与产品主键一致的整数，需在自动迁移前执行
+func MigrateUserFavoriteProductID(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&model.UserFavorite{}) {
+		return nil
+	}
+	columnTypes, err := db.Migrator().ColumnTypes(&model.UserFavorite{})
+	if err != nil {
+		return err
+	}
+	for _, column := range columnTypes {
+		if column.Name() != "product_id" || !strings.EqualFold(column.DatabaseTypeName(), "uuid") {
+			continue
+		}
+
+		// 产品主键为整数，旧版本的 uuid 列无法保存任何有效收藏，也无法换算为产品ID
+		return db.Transaction(func(tx *gorm.DB) error {
+			if tx.Migrator().HasConstraint(&model.UserFavorite{}, "Product") {
+				if err := tx.Migrator().DropConstraint(&model.UserFavorite{}, "Product"); err != nil {
+					return err
+				}
+			}
+			if err := tx.Exec(`DELETE FROM user_favorites`).Error; err != nil {
+				return err
+			}
+			return tx.Exec(`ALTER TABLE user_favorites ALTER COLUMN product_id TYPE bigint USING NULL`).Error
+		})
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"beicun/back/service"
+	"beicun/back/utils"
+)
+
+type AccountHandler struct {
+	accountService *service.AccountService
+}
+
+func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// RequestExport 申请导出个人数据
+// @Summary 申请导出个人数据
+// @Description 后台将个人资料、测评、评论、评分、收藏和上传的文件打包为 ZIP，完成后发送邮件通知
+// @Tags 账号
+// @Produce json
+// @Success 200 {object} utils.Response{data=model.DataExport}
+// @Failure 409,429 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/exports [post]
+func (h *AccountHandler) RequestExport(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	export, err := h.accountService.RequestExport(c, userID)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已开始导出，完成后将通过邮件通知", export)
+}
+
+// ListExports 获取导出任务列表
+// @Summary 获取最近的导出任务
+// @Tags 账号
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]model.DataExport}
+// @Security BearerAuth
+// @Router /user/me/exports [get]
+func (h *AccountHandler) ListExports(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	exports, err := h.accountService.ListExports(c, userID)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	utils.Success(c, exports)
+}
+
+// DownloadExport 下载导出文件
+// @Summary 下载导出文件
+// @Tags 账号
+// @Produce application/zip
+// @Param id path string true "导出任务ID"
+// @Success 200 {file} file
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/exports/{id}/download [get]
+func (h *AccountHandler) DownloadExport(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	export, err := h.accountService.GetExportFile(c, userID, c.Param("id"))
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	c.FileAttachment(export.FilePath, "beicun-data-"+export.CompletedAt.Format("20060102")+".zip")
+}
+
+// RequestDeletion 申请注销账号
+// @Summary 申请注销账号
+// @Description 冷静期结束后删除个人信息，发布过的测评和评论保留并显示为已注销用户；冷静期内可以撤销
+// @Tags 账号
+// @Accept json
+// @Produce json
+// @Param request body service.RequestAccountDeletionRequest true "确认密码和注销原因"
+// @Success 200 {object} utils.Response{data=model.AccountDeletion}
+// @Failure 400,409 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/deletion [post]
+func (h *AccountHandler) RequestDeletion(c *gin.Context) {
+	var req service.RequestAccountDeletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(c)
+	deletion, err := h.accountService.RequestDeletion(c, userID, &req)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "注销申请已提交", deletion)
+}
+
+// GetDeletion 获取注销申请
+// @Summary 获取待处理的注销申请
+// @Tags 账号
+// @Produce json
+// @Success 200 {object} utils.Response{data=model.AccountDeletion}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/deletion [get]
+func (h *AccountHandler) GetDeletion(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	deletion, err := h.accountService.GetDeletion(c, userID)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	utils.Success(c, deletion)
+}
+
+// CancelDeletion 撤销注销申请
+// @Summary 撤销注销申请
+// @Tags 账号
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/deletion [delete]
+func (h *AccountHandler) CancelDeletion(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if err := h.accountService.CancelDeletion(c, userID); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "注销申请已撤销", nil)
+}
+
+func respondAccountError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrExportInProgress, service.ErrDeletionPending:
+		utils.ConflictError(c, err.Error())
+	case service.ErrExportTooFrequent:
+		utils.TooManyRequests(c, err.Error())
+	case service.ErrExportNotFound, service.ErrExportNotReady, service.ErrDeletionNotFound:
+		utils.NotFoundError(c, err.Error())
+	case service.ErrInvalidPassword:
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	case service.ErrUserNotFound:
+		utils.NotFoundError(c, "用户不存在")
+	default:
+		utils.InternalError(c, err)
+	}
+}
@@ -41,7 +41,7 @@ func (h *BrandHandler) RegisterRoutes(r *gin.RouterGroup) {
 // @Produce json
 // @Param request body service.CreateBrandRequest true "品牌信息"
 // @Success 200 {object} utils.Response{data=service.BrandResponse}
-// @Failure 400,409 {object} utils.Response
+// @Failure 400,404,409 {object} utils.Response
 // @Security BearerAuth
 // @Router /brands [post]
 func (h *BrandHandler) CreateBrand(c *gin.Context) {
@@ -53,11 +53,7 @@ func (h *BrandHandler) CreateBrand(c *gin.Context) {
 
 	brand, err := h.brandService.CreateBrand(c, &req)
 	if err != nil {
-		if err == service.ErrBrandExists {
-			utils.ConflictError(c, err.Error())
-			return
-		}
-		utils.InternalError(c, err)
+		respondBrandError(c, err)
 		return
 	}
 
@@ -87,14 +83,7 @@ func (h *BrandHandler) UpdateBrand(c *gin.Context) {
 
 	brand, err := h.brandService.UpdateBrand(c, id, &req)
 	if err != nil {
-		switch err {
-		case service.ErrBrandNotFound:
-			utils.NotFoundError(c, err.Error())
-		case service.ErrBrandExists:
-			utils.ConflictError(c, err.Error())
-		default:
-			utils.InternalError(c, err)
-		}
+		respondBrandError(c, err)
 		return
 	}
 
@@ -108,18 +97,14 @@ func (h *BrandHandler) UpdateBrand(c *gin.Context) {
 // @Produce json
 // @Param id path string true "品牌ID"
 // @Success 200 {object} utils.Response
-// @Failure 400,404 {object} utils.Response
+// @Failure 400,404,409 {object} utils.Response
 // @Security BearerAuth
 // @Router /brands/{id} [delete]
 func (h *BrandHandler) DeleteBrand(c *gin.Context) {
 	id := c.Param("id")
 
 	if err := h.brandService.DeleteBrand(c, id); err != nil {
-		if err == service.ErrBrandNotFound {
-			utils.NotFoundError(c, err.Error())
-			return
-		}
-		utils.InternalError(c, err)
+		respondBrandError(c, err)
 		return
 	}
 
@@ -193,17 +178,21 @@ func (h *BrandHandler) ListBrands(c *gin.Context) {
 
 // GetBrandBySlug 通过 slug 获取品牌详情
 // @Summary 通过 slug 获取品牌详情
-// @Description 通过品牌的 slug 获取品牌的详细信息
+// @Description 通过品牌的 slug 获取品牌的详细信息，包含母品牌、子品牌以及产品数、平均评分、测评数和价格区间
 // @Tags 品牌管理
 // @Produce json
 // @Param slug path string true "品牌 slug"
-// @Success 200 {object} utils.Response{data=model.Brand}
+// @Success 200 {object} utils.Response{data=service.BrandDetailResponse}
+// @Success 301 {object} utils.Response{data=service.SlugRedirect} "slug 已变更"
 // @Failure 404 {object} utils.Response "品牌不存在"
 // @Router /brands/slug/{slug} [get]
 func (h *BrandHandler) GetBrandBySlug(c *gin.Context) {
 	slug := c.Param("slug")
-	brand, err := h.brandService.GetBrandBySlug(c, slug)
+	brand, err := h.brandService.GetBrandDetailBySlug(c, slug)
 	if err != nil {
+		if respondSlugMoved(c, err) {
+			return
+		}
 		if err == service.ErrBrandNotFound {
 			utils.NotFoundError(c, err.Error())
 			return
@@ -224,6 +213,7 @@ func (h *BrandHandler) GetBrandBySlug(c *gin.Context) {
 // @Param page query int false "页码" default(1) minimum(1)
 // @Param pageSize query int false "每页数量" default(10) minimum(1)
 // @Success 200 {object} utils.Response{data=utils.PageData{list=[]model.Product}}
+// @Success 301 {object} utils.Response{data=service.SlugRedirect} "slug 已变更"
 // @Failure 404 {object} utils.Response "品牌不存在"
 // @Router /brands/slug/{slug}/products [get]
 func (h *BrandHandler) GetBrandProducts(c *gin.Context) {
@@ -232,6 +222,9 @@ func (h *BrandHandler) GetBrandProducts(c *gin.Context) {
 
 	products, total, err := h.brandService.GetBrandProducts(c, slug, page, pageSize)
 	if err != nil {
+		if respondSlugMoved(c, err) {
+			return
+		}
 		if err == service.ErrBrandNotFound {
 			utils.NotFoundError(c, err.Error())
 			return
@@ -242,3 +235,17 @@ func (h *BrandHandler) GetBrandProducts(c *gin.Context) {
 
 	utils.PageSuccess(c, products, total, page, pageSize)
 }
+
+// respondBrandError 将品牌创建、更新、删除的业务错误转换为响应
+func respondBrandError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrBrandNotFound, service.ErrBrandParentNotFound:
+		utils.NotFoundError(c, err.Error())
+	case service.ErrBrandExists, service.ErrBrandHasProducts, service.ErrBrandHasSubBrands:
+		utils.ConflictError(c, err.Error())
+	case service.ErrBrandParentCycle, service.ErrInvalidFoundedYear, service.ErrInvalidSocialPlatform:
+		utils.ValidationError(c, err.Error())
+	default:
+		utils.InternalError(c, err)
+	}
+}
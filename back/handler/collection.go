@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"beicun/back/service"
+	"beicun/back/utils"
+)
+
+type CollectionHandler struct {
+	collectionService *service.CollectionService
+}
+
+func NewCollectionHandler(collectionService *service.CollectionService) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+	}
+}
+
+// CreateCollection 创建收藏夹
+// @Summary 创建收藏夹
+// @Tags 收藏夹
+// @Accept json
+// @Produce json
+// @Param request body service.CreateCollectionRequest true "收藏夹信息"
+// @Success 200 {object} utils.Response{data=service.CollectionResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/collections [post]
+func (h *CollectionHandler) CreateCollection(c *gin.Context) {
+	var req service.CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(c)
+	collection, err := h.collectionService.CreateCollection(c, userID, &req)
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	utils.Success(c, collection)
+}
+
+// UpdateCollection 更新收藏夹
+// @Summary 更新收藏夹
+// @Description 只更新提交的字段
+// @Tags 收藏夹
+// @Accept json
+// @Produce json
+// @Param id path string true "收藏夹ID"
+// @Param request body service.UpdateCollectionRequest true "收藏夹信息"
+// @Success 200 {object} utils.Response{data=service.CollectionResponse}
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/collections/{id} [put]
+func (h *CollectionHandler) UpdateCollection(c *gin.Context) {
+	var req service.UpdateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(c)
+	collection, err := h.collectionService.UpdateCollection(c, userID, c.Param("id"), &req)
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	utils.Success(c, collection)
+}
+
+// DeleteCollection 删除收藏夹
+// @Summary 删除收藏夹
+// @Tags 收藏夹
+// @Produce json
+// @Param id path string true "收藏夹ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/collections/{id} [delete]
+func (h *CollectionHandler) DeleteCollection(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if err := h.collectionService.DeleteCollection(c, userID, c.Param("id")); err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// ListMyCollections 获取我的收藏夹
+// @Summary 获取我的收藏夹
+// @Description 包含私有收藏夹，按更新时间倒序
+// @Tags 收藏夹
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Success 200 {object} utils.Response{data=utils.PageData{list=[]service.CollectionResponse}}
+// @Security BearerAuth
+// @Router /user/me/collections [get]
+func (h *CollectionHandler) ListMyCollections(c *gin.Context) {
+	page, pageSize := utils.GetPageInfo(c)
+	userID := utils.GetUserIDFromContext(c)
+
+	collections, total, err := h.collectionService.ListMyCollections(c, userID, page, pageSize)
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	utils.PageSuccess(c, collections, total, page, pageSize)
+}
+
+// ListUserCollections 获取用户的公开收藏夹
+// @Summary 获取用户的公开收藏夹
+// @Description 本人和管理员可以看到私有收藏夹
+// @Tags 收藏夹
+// @Produce json
+// @Param id path string true "用户ID"
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Success 200 {object} utils.Response{data=utils.PageData{list=[]service.CollectionResponse}}
+// @Failure 404 {object} utils.Response
+// @Router /users/{id}/collections [get]
+func (h *CollectionHandler) ListUserCollections(c *gin.Context) {
+	page, pageSize := utils.GetPageInfo(c)
+	viewerID := utils.GetUserIDFromContext(c)
+	viewerRole := utils.GetUserRoleFromContext(c)
+
+	collections, total, err := h.collectionService.ListUserCollections(c, c.Param("id"), viewerID, viewerRole, page, pageSize)
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	utils.PageSuccess(c, collections, total, page, pageSize)
+}
+
+// GetCollection 获取收藏夹详情
+// @Summary 获取收藏夹详情
+// @Description 私有收藏夹仅本人和管理员可见，其他用户需通过分享链接访问
+// @Tags 收藏夹
+// @Produce json
+// @Param id path string true "收藏夹ID"
+// @Success 200 {object} utils.Response{data=service.CollectionDetailResponse}
+// @Failure 404 {object} utils.Response
+// @Router /collections/{id} [get]
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+	viewerID := utils.GetUserIDFromContext(c)
+	viewerRole := utils.GetUserRoleFromContext(c)
+
+	collection, err := h.collectionService.GetCollection(c, c.Param("id"), viewerID, viewerRole)
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	utils.Success(c, collection)
+}
+
+// GetSharedCollection 通过分享链接获取收藏夹
+// @Summary 通过分享链接获取收藏夹
+// @Tags 收藏夹
+// @Produce json
+// @Param token path string true "分享令牌"
+// @Success 200 {object} utils.Response{data=service.CollectionDetailResponse}
+// @Failure 404 {object} utils.Response
+// @Router /collections/shared/{token} [get]
+func (h *CollectionHandler) GetSharedCollection(c *gin.Context) {
+	collection, err := h.collectionService.GetSharedCollection(c, c.Param("token"))
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	utils.Success(c, collection)
+}
+
+// AddItem 向收藏夹添加产品
+// @Summary 向收藏夹添加产品
+// @Description 新产品排在收藏夹末尾
+// @Tags 收藏夹
+// @Accept json
+// @Produce json
+// @Param id path string true "收藏夹ID"
+// @Param request body service.AddCollectionItemRequest true "产品和备注"
+// @Success 200 {object} utils.Response{data=service.CollectionDetailResponse}
+// @Failure 400,404,409 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/collections/{id}/items [post]
+func (h *CollectionHandler) AddItem(c *gin.Context) {
+	var req service.AddCollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(c)
+	collection, err := h.collectionService.AddItem(c, userID, c.Param("id"), &req)
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	utils.Success(c, collection)
+}
+
+// UpdateItem 更新收藏夹产品备注
+// @Summary 更新收藏夹产品备注
+// @Tags 收藏夹
+// @Accept json
+// @Produce json
+// @Param id path string true "收藏夹ID"
+// @Param productId path int true "产品ID"
+// @Param request body service.UpdateCollectionItemRequest true "备注"
+// @Success 200 {object} utils.Response{data=service.CollectionDetailResponse}
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/collections/{id}/items/{productId} [put]
+func (h *CollectionHandler) UpdateItem(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
+	if err != nil {
+		utils.ParamError(c, "无效的产品ID")
+		return
+	}
+
+	var req service.UpdateCollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(c)
+	collection, err := h.collectionService.UpdateItem(c, userID, c.Param("id"), uint(productID), &req)
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	utils.Success(c, collection)
+}
+
+// RemoveItem 从收藏夹移除产品
+// @Summary 从收藏夹移除产品
+// @Tags 收藏夹
+// @Produce json
+// @Param id path string true "收藏夹ID"
+// @Param productId path int true "产品ID"
+// @Success 200 {object} utils.Response
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/collections/{id}/items/{productId} [delete]
+func (h *CollectionHandler) RemoveItem(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
+	if err != nil {
+		utils.ParamError(c, "无效的产品ID")
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(c)
+	if err := h.collectionService.RemoveItem(c, userID, c.Param("id"), uint(productID)); err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "移除成功", nil)
+}
+
+// ReorderItems 调整收藏夹产品顺序
+// @Summary 调整收藏夹产品顺序
+// @Description 提交收藏夹中全部产品ID，按提交顺序排列
+// @Tags 收藏夹
+// @Accept json
+// @Produce json
+// @Param id path string true "收藏夹ID"
+// @Param request body service.ReorderCollectionItemsRequest true "产品顺序"
+// @Success 200 {object} utils.Response{data=service.CollectionDetailResponse}
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/collections/{id}/order [put]
+func (h *CollectionHandler) ReorderItems(c *gin.Context) {
+	var req service.ReorderCollectionItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(c)
+	collection, err := h.collectionService.ReorderItems(c, userID, c.Param("id"), &req)
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	utils.Success(c, collection)
+}
+
+// CreateShareLink 生成分享链接
+// @Summary 生成收藏夹分享链接
+// @Description 生成新的分享令牌，旧链接随之失效；持有链接的用户可以查看私有收藏夹
+// @Tags 收藏夹
+// @Produce json
+// @Param id path string true "收藏夹ID"
+// @Success 200 {object} utils.Response{data=service.CollectionResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/collections/{id}/share [post]
+func (h *CollectionHandler) CreateShareLink(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	collection, err := h.collectionService.CreateShareLink(c, userID, c.Param("id"))
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	utils.Success(c, collection)
+}
+
+// RevokeShareLink 关闭分享链接
+// @Summary 关闭收藏夹分享链接
+// @Tags 收藏夹
+// @Produce json
+// @Param id path string true "收藏夹ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/collections/{id}/share [delete]
+func (h *CollectionHandler) RevokeShareLink(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if err := h.collectionService.RevokeShareLink(c, userID, c.Param("id")); err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "分享链接已关闭", nil)
+}
+
+func respondCollectionError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrInvalidCollectionVisibility, service.ErrInvalidCollectionItemsOrder, service.ErrCollectionFull:
+		utils.ValidationError(c, err.Error())
+	case service.ErrCollectionNotFound, service.ErrCollectionItemNotFound, service.ErrProductNotFound:
+		utils.NotFoundError(c, err.Error())
+	case service.ErrUserNotFound:
+		utils.NotFoundError(c, "用户不存在")
+	case service.ErrCollectionItemExists:
+		utils.ConflictError(c, err.Error())
+	default:
+		utils.InternalError(c, err)
+	}
+}
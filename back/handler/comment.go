@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"strconv"
+
 	"beicun/back/service"
 	"beicun/back/utils"
 
@@ -170,7 +172,7 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 }
 
 // @Summary 获取评论详情
-// @Description 获取单个评论的详细信息
+// @Description 获取单个评论的详细信息，只返回已通过的评论和当前用户自己待审核的评论，管理员不受限制
 // @Tags 评论
 // @Produce json
 // @Param id path string true "评论ID"
@@ -180,7 +182,8 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 // @Router /comments/{id} [get]
 func (h *CommentHandler) GetComment(c *gin.Context) {
 	id := c.Param("id")
-	comment, err := h.commentService.GetComment(c, id)
+	viewerID := utils.GetUserIDFromContext(c)
+	comment, err := h.commentService.GetComment(c, id, viewerID)
 	if err != nil {
 		switch err {
 		case service.ErrCommentNotFound:
@@ -196,14 +199,14 @@ func (h *CommentHandler) GetComment(c *gin.Context) {
 
 // ListComments 获取评论列表
 // @Summary 获取评论列表
-// @Description 获取指定测评的评论列表
+// @Description 获取指定测评的评论列表，只返回已通过的评论，登录用户还会看到自己待审核的评论
 // @Tags 评论管理
 // @Accept json
 // @Produce json
+// @Param Authorization header string false "Bearer 用户令牌"
 // @Param review_slug query string true "测评 Slug"
 // @Param page query int false "页码，默认为1"
 // @Param pageSize query int false "每页数量，默认为10"
-// @Param status query string false "评论状态"
 // @Success 200 {object} utils.Response{data=[]service.CommentResponse}
 // @Failure 400,404,500 {object} utils.Response
 // @Router /comments [get]
@@ -215,9 +218,9 @@ func (h *CommentHandler) ListComments(c *gin.Context) {
 	}
 
 	page, pageSize := utils.GetPageInfo(c)
-	status := c.Query("status")
+	viewerID := utils.GetUserIDFromContext(c)
 
-	comments, total, err := h.commentService.ListComments(c, reviewSlug, page, pageSize, status)
+	comments, total, err := h.commentService.ListComments(c, reviewSlug, viewerID, page, pageSize)
 	if err != nil {
 		switch err {
 		case service.ErrReviewNotFound:
@@ -231,6 +234,38 @@ func (h *CommentHandler) ListComments(c *gin.Context) {
 	utils.PageSuccess(c, comments, total, page, pageSize)
 }
 
+// ListReplies 加载更多回复
+// @Summary 加载评论回复
+// @Description 按游标分页加载评论的回复，每条回复预加载其下的部分回复
+// @Tags 评论管理
+// @Produce json
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param id path string true "评论ID"
+// @Param cursor query string false "上一页返回的游标"
+// @Param limit query int false "数量，默认为10，最大50"
+// @Success 200 {object} utils.Response{data=service.CommentRepliesPage}
+// @Failure 400,404,500 {object} utils.Response
+// @Router /comments/{id}/replies [get]
+func (h *CommentHandler) ListReplies(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	viewerID := utils.GetUserIDFromContext(c)
+
+	replies, err := h.commentService.ListReplies(c, c.Param("id"), viewerID, c.Query("cursor"), limit)
+	if err != nil {
+		switch err {
+		case service.ErrCommentNotFound:
+			utils.NotFoundError(c, err.Error())
+		case service.ErrInvalidCursor:
+			utils.ParamError(c, err.Error())
+		default:
+			utils.InternalError(c, err)
+		}
+		return
+	}
+
+	utils.Success(c, replies)
+}
+
 // @Summary 获取所有评论列表（管理员）
 // @Description 管理员获取所有评论列表
 // @Tags 评论
@@ -238,7 +273,7 @@ func (h *CommentHandler) ListComments(c *gin.Context) {
 // @Param Authorization header string true "Bearer 管理员令牌"
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
-// @Param status query string false "状态过滤" Enums(PENDING, PUBLISHED, REJECTED)
+// @Param status query string false "状态过滤" Enums(PENDING, APPROVED, REJECTED)
 // @Success 200 {object} utils.Response{data=[]service.CommentResponse}
 // @Failure 401 {object} utils.Response
 // @Failure 500 {object} utils.Response
@@ -255,3 +290,31 @@ func (h *CommentHandler) ListAllComments(c *gin.Context) {
 
 	utils.PageSuccess(c, comments, total, page, pageSize)
 }
+
+// @Summary 批量审核评论（管理员）
+// @Description 批量通过或拒绝评论，单次最多 100 条
+// @Tags 评论
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer 管理员令牌"
+// @Param request body service.BulkUpdateCommentStatusRequest true "评论ID列表和目标状态"
+// @Success 200 {object} utils.Response{data=service.BulkUpdateCommentStatusResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/comments/status [put]
+func (h *CommentHandler) BulkUpdateCommentStatus(c *gin.Context) {
+	var req service.BulkUpdateCommentStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	result, err := h.commentService.BulkUpdateCommentStatus(c, &req)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, result)
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"beicun/back/service"
+	"beicun/back/utils"
+)
+
+type ScoreDimensionHandler struct {
+	dimensionService *service.ScoreDimensionService
+}
+
+func NewScoreDimensionHandler(dimensionService *service.ScoreDimensionService) *ScoreDimensionHandler {
+	return &ScoreDimensionHandler{
+		dimensionService: dimensionService,
+	}
+}
+
+// ListDimensions 获取评分维度列表
+// @Summary 获取评分维度列表
+// @Description 获取器具类型配置的评分维度
+// @Tags 评分维度
+// @Produce json
+// @Param id path string true "器具类型ID"
+// @Success 200 {object} utils.Response{data=[]model.ScoreDimension}
+// @Failure 404 {object} utils.Response
+// @Router /utility-types/{id}/dimensions [get]
+func (h *ScoreDimensionHandler) ListDimensions(c *gin.Context) {
+	dimensions, err := h.dimensionService.ListDimensions(c, c.Param("id"))
+	if err != nil {
+		if err == service.ErrTypeNotFound {
+			utils.NotFoundError(c, "器具类型不存在")
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, dimensions)
+}
+
+// CreateDimension 创建评分维度
+// @Summary 创建评分维度
+// @Description 为器具类型添加评分维度
+// @Tags 评分维度
+// @Accept json
+// @Produce json
+// @Param id path string true "器具类型ID"
+// @Param request body service.CreateDimensionRequest true "维度信息"
+// @Success 200 {object} utils.Response{data=model.ScoreDimension}
+// @Failure 400,404,409 {object} utils.Response
+// @Security BearerAuth
+// @Router /utility-types/{id}/dimensions [post]
+func (h *ScoreDimensionHandler) CreateDimension(c *gin.Context) {
+	var req service.CreateDimensionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	dimension, err := h.dimensionService.CreateDimension(c, c.Param("id"), &req)
+	if err != nil {
+		switch err {
+		case service.ErrTypeNotFound:
+			utils.NotFoundError(c, "器具类型不存在")
+		case service.ErrDimensionExists:
+			utils.ConflictError(c, err.Error())
+		default:
+			utils.InternalError(c, err)
+		}
+		return
+	}
+
+	utils.SuccessWithMessage(c, "创建评分维度成功", dimension)
+}
+
+// UpdateDimension 更新评分维度
+// @Summary 更新评分维度
+// @Description 更新评分维度的名称、说明和排序
+// @Tags 评分维度
+// @Accept json
+// @Produce json
+// @Param id path string true "器具类型ID"
+// @Param dimensionId path string true "维度ID"
+// @Param request body service.UpdateDimensionRequest true "维度信息"
+// @Success 200 {object} utils.Response{data=model.ScoreDimension}
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /utility-types/{id}/dimensions/{dimensionId} [put]
+func (h *ScoreDimensionHandler) UpdateDimension(c *gin.Context) {
+	var req service.UpdateDimensionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	dimension, err := h.dimensionService.UpdateDimension(c, c.Param("id"), c.Param("dimensionId"), &req)
+	if err != nil {
+		if err == service.ErrDimensionNotFound {
+			utils.NotFoundError(c, err.Error())
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "更新评分维度成功", dimension)
+}
+
+// DeleteDimension 删除评分维度
+// @Summary 删除评分维度
+// @Description 删除评分维度，测评中该维度的评分会一并删除
+// @Tags 评分维度
+// @Produce json
+// @Param id path string true "器具类型ID"
+// @Param dimensionId path string true "维度ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /utility-types/{id}/dimensions/{dimensionId} [delete]
+func (h *ScoreDimensionHandler) DeleteDimension(c *gin.Context) {
+	if err := h.dimensionService.DeleteDimension(c, c.Param("id"), c.Param("dimensionId")); err != nil {
+		if err == service.ErrDimensionNotFound {
+			utils.NotFoundError(c, err.Error())
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除评分维度成功", nil)
+}
+
+// GetProductRadar 获取产品维度评分雷达图数据
+// @Summary 获取产品维度评分雷达图数据
+// @Description 汇总已发布测评在各维度上的平均分
+// @Tags 评分维度
+// @Produce json
+// @Param id path uint true "产品ID"
+// @Success 200 {object} utils.Response{data=service.ProductRadar}
+// @Failure 400,404 {object} utils.Response
+// @Router /products/{id}/radar [get]
+func (h *ScoreDimensionHandler) GetProductRadar(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ParamError(c, "无效的产品ID")
+		return
+	}
+
+	radar, err := h.dimensionService.GetProductRadar(c, uint(productID))
+	if err != nil {
+		if err == service.ErrProductNotFound {
+			utils.NotFoundError(c, err.Error())
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, radar)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"beicun/back/model"
+	"beicun/back/service"
+	"beicun/back/utils"
+)
+
+type FollowHandler struct {
+	followService *service.FollowService
+}
+
+func NewFollowHandler(followService *service.FollowService) *FollowHandler {
+	return &FollowHandler{
+		followService: followService,
+	}
+}
+
+// Follow 关注
+// @Summary 关注品牌、作者或产品
+// @Description 关注后对象的新动态会出现在个性化动态中，重复关注不报错
+// @Tags 关注
+// @Produce json
+// @Param targetType path string true "对象类型" Enums(brand, author, product)
+// @Param targetId path string true "对象ID"
+// @Success 200 {object} utils.Response{data=service.FollowResponse}
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/follows/{targetType}/{targetId} [put]
+func (h *FollowHandler) Follow(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	follow, err := h.followService.Follow(c, userID, model.FollowTargetType(c.Param("targetType")), c.Param("targetId"))
+	if err != nil {
+		respondFollowError(c, err)
+		return
+	}
+
+	utils.Success(c, follow)
+}
+
+// Unfollow 取消关注
+// @Summary 取消关注
+// @Tags 关注
+// @Produce json
+// @Param targetType path string true "对象类型" Enums(brand, author, product)
+// @Param targetId path string true "对象ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/follows/{targetType}/{targetId} [delete]
+func (h *FollowHandler) Unfollow(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	if err := h.followService.Unfollow(c, userID, model.FollowTargetType(c.Param("targetType")), c.Param("targetId")); err != nil {
+		respondFollowError(c, err)
+		return
+	}
+
+	utils.Success(c, nil)
+}
+
+// ListFollows 获取关注列表
+// @Summary 获取关注列表
+// @Tags 关注
+// @Produce json
+// @Param targetType query string false "对象类型" Enums(brand, author, product)
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Success 200 {object} utils.Response{data=utils.PageData{list=[]service.FollowResponse}}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/me/follows [get]
+func (h *FollowHandler) ListFollows(c *gin.Context) {
+	page, pageSize := utils.GetPageInfo(c)
+	userID := utils.GetUserIDFromContext(c)
+
+	follows, total, err := h.followService.ListFollows(c, userID, c.Query("targetType"), page, pageSize)
+	if err != nil {
+		respondFollowError(c, err)
+		return
+	}
+
+	utils.PageSuccess(c, follows, total, page, pageSize)
+}
+
+// GetFeed 获取个性化动态
+// @Summary 获取个性化动态
+// @Description 合并关注的作者或产品的新测评、关注的品牌的新产品、关注的产品的价格变动，按时间倒序
+// @Tags 关注
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Success 200 {object} utils.Response{data=utils.PageData{list=[]service.FeedItem}}
+// @Security BearerAuth
+// @Router /user/feed [get]
+func (h *FollowHandler) GetFeed(c *gin.Context) {
+	page, pageSize := utils.GetPageInfo(c)
+	userID := utils.GetUserIDFromContext(c)
+
+	items, total, err := h.followService.GetFeed(c, userID, page, pageSize)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.PageSuccess(c, items, total, page, pageSize)
+}
+
+func respondFollowError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrInvalidFollowTarget, service.ErrFollowSelf:
+		utils.ValidationError(c, err.Error())
+	case service.ErrFollowTargetMissing:
+		utils.NotFoundError(c, err.Error())
+	default:
+		utils.InternalError(c, err)
+	}
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"beicun/back/service"
+	"beicun/back/utils"
+)
+
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// ListNotifications 获取通知列表
+// @Summary 获取通知列表
+// @Description 获取当前用户的站内通知，最新的在前
+// @Tags 通知
+// @Produce json
+// @Param type query string false "通知类型" Enums(REPLY, MENTION, REVIEW_APPROVED, PRICE_DROP, MODERATION_RESULT)
+// @Param unread query bool false "只看未读"
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Success 200 {object} utils.Response{data=utils.PageData{list=[]service.NotificationResponse}}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	page, pageSize := utils.GetPageInfo(c)
+	userID := utils.GetUserIDFromContext(c)
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, total, err := h.notificationService.ListNotifications(c, userID, c.Query("type"), unreadOnly, page, pageSize)
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+
+	utils.PageSuccess(c, notifications, total, page, pageSize)
+}
+
+// GetUnreadCount 获取未读通知数
+// @Summary 获取未读通知数
+// @Description 获取未读通知总数及各类型的未读数
+// @Tags 通知
+// @Produce json
+// @Success 200 {object} utils.Response{data=service.UnreadCountResponse}
+// @Security BearerAuth
+// @Router /user/notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	count, err := h.notificationService.GetUnreadCount(c, utils.GetUserIDFromContext(c))
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, count)
+}
+
+// MarkRead 标记通知已读
+// @Summary 标记通知已读
+// @Tags 通知
+// @Produce json
+// @Param id path string true "通知ID"
+// @Success 200 {object} utils.Response{data=service.NotificationResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/notifications/{id}/read [put]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	notification, err := h.notificationService.MarkRead(c, utils.GetUserIDFromContext(c), c.Param("id"))
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+
+	utils.Success(c, notification)
+}
+
+// MarkAllRead 全部标记已读
+// @Summary 全部标记已读
+// @Description 将未读通知全部标记为已读，指定类型时只处理该类型
+// @Tags 通知
+// @Produce json
+// @Param type query string false "通知类型" Enums(REPLY, MENTION, REVIEW_APPROVED, PRICE_DROP, MODERATION_RESULT)
+// @Success 200 {object} utils.Response{data=service.MarkReadResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/notifications/read-all [put]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	result, err := h.notificationService.MarkAllRead(c, utils.GetUserIDFromContext(c), c.Query("type"))
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// GetSettings 获取通知设置
+// @Summary 获取通知设置
+// @Tags 通知
+// @Produce json
+// @Success 200 {object} utils.Response{data=service.NotificationSettingsResponse}
+// @Security BearerAuth
+// @Router /user/notifications/settings [get]
+func (h *NotificationHandler) GetSettings(c *gin.Context) {
+	settings, err := h.notificationService.GetSettings(c, utils.GetUserIDFromContext(c))
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, settings)
+}
+
+// UpdateSettings 更新通知设置
+// @Summary 更新通知设置
+// @Description 按通知类型设置投递方式：IN_APP 仅站内通知，EMAIL 同时发送邮件（回复和提及汇总为定期摘要），OFF 不接收
+// @Tags 通知
+// @Accept json
+// @Produce json
+// @Param request body service.NotificationSettingsRequest true "通知设置"
+// @Success 200 {object} utils.Response{data=service.NotificationSettingsResponse}
+// @Failure 400 {object} utils.Response
+// @Security BearerAuth
+// @Router /user/notifications/settings [put]
+func (h *NotificationHandler) UpdateSettings(c *gin.Context) {
+	var req service.NotificationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	settings, err := h.notificationService.UpdateSettings(c, utils.GetUserIDFromContext(c), &req)
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+
+	utils.Success(c, settings)
+}
+
+func respondNotificationError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrInvalidNotificationType, service.ErrInvalidNotificationChannel:
+		utils.ValidationError(c, err.Error())
+	case service.ErrNotificationNotFound:
+		utils.NotFoundError(c, err.Error())
+	default:
+		utils.InternalError(c, err)
+	}
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"beicun/back/model"
 	"beicun/back/service"
 	"beicun/back/utils"
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type ProductHandler struct {
@@ -38,6 +41,10 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 
 	product, err := h.productService.CreateProduct(c, &req)
 	if err != nil {
+		if isDimensionError(err) {
+			utils.ValidationError(c, err.Error())
+			return
+		}
 		utils.InternalError(c, err)
 		return
 	}
@@ -47,7 +54,7 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 
 // UpdateProduct 更新产品
 // @Summary 更新产品信息
-// @Description 更新指定产品的信息
+// @Description 更新指定产品的信息，修改单位时未提供的尺寸和重量会换算为新单位
 // @Tags 产品管理
 // @Accept json
 // @Produce json
@@ -76,6 +83,10 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 			utils.NotFoundError(c, "产品不存在")
 			return
 		}
+		if isDimensionError(err) {
+			utils.ValidationError(c, err.Error())
+			return
+		}
 		utils.InternalError(c, err)
 		return
 	}
@@ -118,6 +129,7 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 // @Tags 产品管理
 // @Produce json
 // @Param id path int true "产品ID"
+// @Param units query string false "单位制，也可通过 Accept-Units 请求头指定" Enums(metric,imperial)
 // @Success 200 {object} utils.Response{data=service.ProductResponse}
 // @Failure 400,404 {object} utils.Response
 // @Router /products/{id} [get]
@@ -138,6 +150,7 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		return
 	}
 
+	product.ConvertUnits(utils.GetUnitSystem(c))
 	utils.Success(c, product)
 }
 
@@ -147,13 +160,18 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 // @Tags 产品管理
 // @Produce json
 // @Param slug path string true "产品Slug"
+// @Param units query string false "单位制，也可通过 Accept-Units 请求头指定" Enums(metric,imperial)
 // @Success 200 {object} utils.Response{data=service.ProductResponse}
+// @Success 301 {object} utils.Response{data=service.SlugRedirect} "slug 已变更"
 // @Failure 400,404 {object} utils.Response
 // @Router /products/slug/{slug} [get]
 func (h *ProductHandler) GetProductBySlug(c *gin.Context) {
 	slug := c.Param("slug")
 	product, err := h.productService.GetProductBySlug(c, slug)
 	if err != nil {
+		if respondSlugMoved(c, err) {
+			return
+		}
 		if err == service.ErrProductNotFound {
 			utils.NotFoundError(c, "产品不存在")
 			return
@@ -162,6 +180,7 @@ func (h *ProductHandler) GetProductBySlug(c *gin.Context) {
 		return
 	}
 
+	product.ConvertUnits(utils.GetUnitSystem(c))
 	utils.Success(c, product)
 }
 
@@ -179,6 +198,7 @@ func (h *ProductHandler) GetProductBySlug(c *gin.Context) {
 // @Param minPrice query number false "最低价格"
 // @Param maxPrice query number false "最高价格"
 // @Param search query string false "搜索关键词"
+// @Param units query string false "单位制，也可通过 Accept-Units 请求头指定" Enums(metric,imperial)
 // @Success 200 {object} utils.Response{data=utils.PageData{list=[]service.ProductResponse}}
 // @Failure 400 {object} utils.Response
 // @Router /products [get]
@@ -215,5 +235,183 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 		return
 	}
 
+	system := utils.GetUnitSystem(c)
+	for _, product := range products {
+		product.ConvertUnits(system)
+	}
+
 	utils.PageSuccess(c, products, total, page, pageSize)
 }
+
+// ListVariants 获取产品版本列表
+// @Summary 获取产品版本列表
+// @Description 获取指定产品的所有版本
+// @Tags 产品管理
+// @Produce json
+// @Param id path int true "产品ID"
+// @Param units query string false "单位制，也可通过 Accept-Units 请求头指定" Enums(metric,imperial)
+// @Success 200 {object} utils.Response{data=[]service.ProductVariantResponse}
+// @Failure 400,404 {object} utils.Response
+// @Router /products/{id}/variants [get]
+func (h *ProductHandler) ListVariants(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ParamError(c, "无效的产品ID")
+		return
+	}
+
+	variants, err := h.productService.ListVariants(c, uint(id), utils.GetUnitSystem(c))
+	if err != nil {
+		if err == service.ErrProductNotFound {
+			utils.NotFoundError(c, "产品不存在")
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, variants)
+}
+
+// CreateVariant 创建产品版本
+// @Summary 创建产品版本
+// @Description 为指定产品创建一个新版本
+// @Tags 产品管理
+// @Accept json
+// @Produce json
+// @Param id path int true "产品ID"
+// @Param request body service.CreateVariantRequest true "版本信息"
+// @Success 200 {object} utils.Response{data=service.ProductVariantResponse}
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /products/{id}/variants [post]
+func (h *ProductHandler) CreateVariant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ParamError(c, "无效的产品ID")
+		return
+	}
+
+	var req service.CreateVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	variant, err := h.productService.CreateVariant(c, uint(id), &req)
+	if err != nil {
+		if err == service.ErrProductNotFound {
+			utils.NotFoundError(c, "产品不存在")
+			return
+		}
+		if isVariantError(err) {
+			utils.ParamError(c, err.Error())
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "创建产品版本成功", variant)
+}
+
+// UpdateVariant 更新产品版本
+// @Summary 更新产品版本
+// @Description 更新指定产品版本的信息，clear 中列出的字段将清除覆盖值并沿用产品的值
+// @Tags 产品管理
+// @Accept json
+// @Produce json
+// @Param id path int true "产品ID"
+// @Param variantId path string true "版本ID"
+// @Param request body service.UpdateVariantRequest true "版本更新信息"
+// @Success 200 {object} utils.Response{data=service.ProductVariantResponse}
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /products/{id}/variants/{variantId} [put]
+func (h *ProductHandler) UpdateVariant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ParamError(c, "无效的产品ID")
+		return
+	}
+
+	variantID := c.Param("variantId")
+	if _, err := uuid.Parse(variantID); err != nil {
+		utils.ParamError(c, "无效的版本ID")
+		return
+	}
+
+	var req service.UpdateVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	variant, err := h.productService.UpdateVariant(c, uint(id), variantID, &req)
+	if err != nil {
+		if err == service.ErrVariantNotFound {
+			utils.NotFoundError(c, "产品版本不存在")
+			return
+		}
+		if isVariantError(err) {
+			utils.ParamError(c, err.Error())
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "更新产品版本成功", variant)
+}
+
+// DeleteVariant 删除产品版本
+// @Summary 删除产品版本
+// @Description 删除指定的产品版本，关联的测评和评分将不再指向该版本
+// @Tags 产品管理
+// @Produce json
+// @Param id path int true "产品ID"
+// @Param variantId path string true "版本ID"
+// @Success 200 {object} utils.Response
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /products/{id}/variants/{variantId} [delete]
+func (h *ProductHandler) DeleteVariant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ParamError(c, "无效的产品ID")
+		return
+	}
+
+	variantID := c.Param("variantId")
+	if _, err := uuid.Parse(variantID); err != nil {
+		utils.ParamError(c, "无效的版本ID")
+		return
+	}
+
+	if err := h.productService.DeleteVariant(c, uint(id), variantID); err != nil {
+		if err == service.ErrVariantNotFound {
+			utils.NotFoundError(c, "产品版本不存在")
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除产品版本成功", nil)
+}
+
+// isDimensionError 是否为尺寸/单位校验错误
+func isDimensionError(err error) bool {
+	return errors.Is(err, model.ErrInvalidDimension) ||
+		errors.Is(err, model.ErrInvalidUnit) ||
+		errors.Is(err, model.ErrDimensionRange) ||
+		errors.Is(err, model.ErrWeightRange)
+}
+
+// isVariantError 是否为产品版本字段校验错误
+func isVariantError(err error) bool {
+	return errors.Is(err, model.ErrRequired) ||
+		errors.Is(err, model.ErrInvalidPrice) ||
+		errors.Is(err, model.ErrInvalidSortOrder) ||
+		isDimensionError(err)
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"beicun/back/model"
+	"beicun/back/service"
+	"beicun/back/utils"
+)
+
+type ReactionHandler struct {
+	reactionService *service.ReactionService
+}
+
+func NewReactionHandler(reactionService *service.ReactionService) *ReactionHandler {
+	return &ReactionHandler{
+		reactionService: reactionService,
+	}
+}
+
+// ReactToReview 对测评进行反馈
+// @Summary 对测评进行反馈
+// @Description 标记测评有帮助或没帮助，每个用户只保留最后一次选择
+// @Tags 反馈
+// @Accept json
+// @Produce json
+// @Param id path string true "测评ID"
+// @Param request body service.ReactRequest true "反馈类型"
+// @Success 200 {object} utils.Response{data=service.ReactionSummary}
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /reviews/{id}/reaction [put]
+func (h *ReactionHandler) ReactToReview(c *gin.Context) {
+	h.react(c, model.ReactionTargetReview)
+}
+
+// RemoveReviewReaction 取消测评反馈
+// @Summary 取消测评反馈
+// @Tags 反馈
+// @Produce json
+// @Param id path string true "测评ID"
+// @Success 200 {object} utils.Response{data=service.ReactionSummary}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /reviews/{id}/reaction [delete]
+func (h *ReactionHandler) RemoveReviewReaction(c *gin.Context) {
+	h.removeReaction(c, model.ReactionTargetReview)
+}
+
+// ReactToComment 对评论进行反馈
+// @Summary 对评论进行反馈
+// @Description 标记评论有帮助或没帮助，每个用户只保留最后一次选择
+// @Tags 反馈
+// @Accept json
+// @Produce json
+// @Param id path string true "评论ID"
+// @Param request body service.ReactRequest true "反馈类型"
+// @Success 200 {object} utils.Response{data=service.ReactionSummary}
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /comments/{id}/reaction [put]
+func (h *ReactionHandler) ReactToComment(c *gin.Context) {
+	h.react(c, model.ReactionTargetComment)
+}
+
+// RemoveCommentReaction 取消评论反馈
+// @Summary 取消评论反馈
+// @Tags 反馈
+// @Produce json
+// @Param id path string true "评论ID"
+// @Success 200 {object} utils.Response{data=service.ReactionSummary}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /comments/{id}/reaction [delete]
+func (h *ReactionHandler) RemoveCommentReaction(c *gin.Context) {
+	h.removeReaction(c, model.ReactionTargetComment)
+}
+
+func (h *ReactionHandler) react(c *gin.Context, targetType model.ReactionTargetType) {
+	var req service.ReactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(c)
+	summary, err := h.reactionService.React(c, userID, targetType, c.Param("id"), req.Type)
+	if err != nil {
+		respondReactionError(c, err)
+		return
+	}
+
+	utils.Success(c, summary)
+}
+
+func (h *ReactionHandler) removeReaction(c *gin.Context, targetType model.ReactionTargetType) {
+	userID := utils.GetUserIDFromContext(c)
+	summary, err := h.reactionService.RemoveReaction(c, userID, targetType, c.Param("id"))
+	if err != nil {
+		respondReactionError(c, err)
+		return
+	}
+
+	utils.Success(c, summary)
+}
+
+func respondReactionError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrInvalidReaction, service.ErrReactToOwn:
+		utils.ValidationError(c, err.Error())
+	case service.ErrReviewNotFound, service.ErrCommentNotFound:
+		utils.NotFoundError(c, err.Error())
+	default:
+		utils.InternalError(c, err)
+	}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"beicun/back/config"
+	"beicun/back/service"
+	"beicun/back/utils"
+)
+
+type RealtimeHandler struct {
+	realtimeService *service.RealtimeService
+	cfg             *config.Config
+}
+
+func NewRealtimeHandler(realtimeService *service.RealtimeService, cfg *config.Config) *RealtimeHandler {
+	return &RealtimeHandler{
+		realtimeService: realtimeService,
+		cfg:             cfg,
+	}
+}
+
+// Stream 实时事件推送
+// @Summary 实时事件推送（SSE）
+// @Description 以 Server-Sent Events 推送实时事件，始终包含当前用户的通知事件（主题 user:{userId}）。
+// @Description 可额外订阅 review:{reviewId}（测评新评论）和 upload:{fileId}（上传进度，仅管理员），多个主题以逗号分隔。
+// @Description 浏览器 EventSource 无法设置请求头，可通过 access_token 查询参数传递令牌。
+// @Tags 实时推送
+// @Produce text/event-stream
+// @Param topics query string false "订阅主题，多个以逗号分隔"
+// @Param access_token query string false "访问令牌，未设置 Authorization 请求头时使用"
+// @Success 200 {object} service.RealtimeEvent
+// @Failure 400,403 {object} utils.Response
+// @Security BearerAuth
+// @Router /events [get]
+func (h *RealtimeHandler) Stream(c *gin.Context) {
+	var topics []string
+	if raw := c.Query("topics"); raw != "" {
+		topics = strings.Split(raw, ",")
+	}
+
+	sub, err := h.realtimeService.Subscribe(c, utils.GetUserIDFromContext(c), utils.GetUserRoleFromContext(c), topics)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidTopic, service.ErrTooManyTopics:
+			utils.ParamError(c, err.Error())
+		case service.ErrTopicForbidden:
+			utils.Error(c, http.StatusForbidden, err.Error())
+		default:
+			utils.InternalError(c, err)
+		}
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 关闭 Nginx 缓冲
+
+	// 连接建立后先告知客户端实际订阅的主题
+	c.SSEvent("ready", gin.H{"topics": sub.Topics()})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.cfg.Realtime.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event := <-sub.Events():
+			c.SSEvent(event.Type, event)
+			return true
+		case <-heartbeat.C:
+			// 注释行不会触发客户端事件，仅用于保持连接
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return false
+			}
+			return true
+		}
+	})
+}
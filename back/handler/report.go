@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"beicun/back/model"
+	"beicun/back/service"
+	"beicun/back/utils"
+)
+
+type ReportHandler struct {
+	reportService *service.ReportService
+}
+
+func NewReportHandler(reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// ReportReview 举报测评
+// @Summary 举报测评
+// @Description 举报违规测评，每个用户对同一测评只能举报一次，举报人数达到阈值后自动隐藏
+// @Tags 举报
+// @Accept json
+// @Produce json
+// @Param id path string true "测评ID"
+// @Param request body service.CreateReportRequest true "举报原因"
+// @Success 200 {object} utils.Response{data=service.ReportResponse}
+// @Failure 400,404,409 {object} utils.Response
+// @Security BearerAuth
+// @Router /reviews/{id}/report [post]
+func (h *ReportHandler) ReportReview(c *gin.Context) {
+	h.report(c, model.ReportTargetReview)
+}
+
+// ReportComment 举报评论
+// @Summary 举报评论
+// @Description 举报违规评论，每个用户对同一评论只能举报一次，举报人数达到阈值后自动隐藏
+// @Tags 举报
+// @Accept json
+// @Produce json
+// @Param id path string true "评论ID"
+// @Param request body service.CreateReportRequest true "举报原因"
+// @Success 200 {object} utils.Response{data=service.ReportResponse}
+// @Failure 400,404,409 {object} utils.Response
+// @Security BearerAuth
+// @Router /comments/{id}/report [post]
+func (h *ReportHandler) ReportComment(c *gin.Context) {
+	h.report(c, model.ReportTargetComment)
+}
+
+func (h *ReportHandler) report(c *gin.Context, targetType model.ReportTargetType) {
+	var req service.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(c)
+	report, err := h.reportService.CreateReport(c, userID, targetType, c.Param("id"), &req)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidReportReason, service.ErrReportOwn:
+			utils.ValidationError(c, err.Error())
+		case service.ErrAlreadyReported:
+			utils.ConflictError(c, err.Error())
+		case service.ErrReviewNotFound, service.ErrCommentNotFound:
+			utils.NotFoundError(c, err.Error())
+		default:
+			utils.InternalError(c, err)
+		}
+		return
+	}
+
+	utils.Success(c, report)
+}
+
+// ListMyReports 获取我的举报
+// @Summary 获取我的举报
+// @Description 获取当前用户提交的举报及处理结果
+// @Tags 举报
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Success 200 {object} utils.Response{data=utils.PageData{list=[]service.ReportResponse}}
+// @Security BearerAuth
+// @Router /user/me/reports [get]
+func (h *ReportHandler) ListMyReports(c *gin.Context) {
+	page, pageSize := utils.GetPageInfo(c)
+	userID := utils.GetUserIDFromContext(c)
+
+	reports, total, err := h.reportService.ListMyReports(c, userID, page, pageSize)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.PageSuccess(c, reports, total, page, pageSize)
+}
+
+// ListReportCases 获取举报处理队列（管理员）
+// @Summary 获取举报处理队列
+// @Description 按举报人数从多到少列出举报事项
+// @Tags 举报
+// @Produce json
+// @Param status query string false "处理状态" Enums(PENDING, RESOLVED, DISMISSED)
+// @Param targetType query string false "对象类型" Enums(review, comment)
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Success 200 {object} utils.Response{data=utils.PageData{list=[]service.ReportCaseResponse}}
+// @Security BearerAuth
+// @Router /admin/reports [get]
+func (h *ReportHandler) ListReportCases(c *gin.Context) {
+	page, pageSize := utils.GetPageInfo(c)
+
+	cases, total, err := h.reportService.ListReportCases(c, c.Query("status"), c.Query("targetType"), page, pageSize)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.PageSuccess(c, cases, total, page, pageSize)
+}
+
+// GetReportCase 获取举报详情（管理员）
+// @Summary 获取举报详情
+// @Tags 举报
+// @Produce json
+// @Param id path string true "举报事项ID"
+// @Success 200 {object} utils.Response{data=service.ReportCaseResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /admin/reports/{id} [get]
+func (h *ReportHandler) GetReportCase(c *gin.Context) {
+	reportCase, err := h.reportService.GetReportCase(c, c.Param("id"))
+	if err != nil {
+		respondReportCaseError(c, err)
+		return
+	}
+
+	utils.Success(c, reportCase)
+}
+
+// ResolveReport 举报成立（管理员）
+// @Summary 举报成立
+// @Description 下架被举报的内容，处理说明会反馈给举报人
+// @Tags 举报
+// @Accept json
+// @Produce json
+// @Param id path string true "举报事项ID"
+// @Param request body service.HandleReportRequest false "处理说明"
+// @Success 200 {object} utils.Response{data=service.ReportCaseResponse}
+// @Failure 404,409 {object} utils.Response
+// @Security BearerAuth
+// @Router /admin/reports/{id}/resolve [post]
+func (h *ReportHandler) ResolveReport(c *gin.Context) {
+	h.handle(c, h.reportService.ResolveReport)
+}
+
+// DismissReport 驳回举报（管理员）
+// @Summary 驳回举报
+// @Description 恢复被自动隐藏的内容，处理说明会反馈给举报人
+// @Tags 举报
+// @Accept json
+// @Produce json
+// @Param id path string true "举报事项ID"
+// @Param request body service.HandleReportRequest false "处理说明"
+// @Success 200 {object} utils.Response{data=service.ReportCaseResponse}
+// @Failure 404,409 {object} utils.Response
+// @Security BearerAuth
+// @Router /admin/reports/{id}/dismiss [post]
+func (h *ReportHandler) DismissReport(c *gin.Context) {
+	h.handle(c, h.reportService.DismissReport)
+}
+
+func (h *ReportHandler) handle(c *gin.Context, action func(*gin.Context, string, string, *service.HandleReportRequest) (*service.ReportCaseResponse, error)) {
+	var req service.HandleReportRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ParamError(c, "无效的请求参数")
+			return
+		}
+	}
+
+	adminID := utils.GetUserIDFromContext(c)
+	reportCase, err := action(c, c.Param("id"), adminID, &req)
+	if err != nil {
+		respondReportCaseError(c, err)
+		return
+	}
+
+	utils.Success(c, reportCase)
+}
+
+func respondReportCaseError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrReportCaseNotFound:
+		utils.NotFoundError(c, err.Error())
+	case service.ErrReportCaseHandled:
+		utils.ConflictError(c, err.Error())
+	default:
+		utils.InternalError(c, err)
+	}
+}
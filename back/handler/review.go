@@ -3,9 +3,11 @@ package handler
 import (
 	"beicun/back/service"
 	"beicun/back/utils"
+	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type ReviewHandler struct {
@@ -45,10 +47,14 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 
 	review, err := h.reviewService.CreateReview(c, userID, &req)
 	if err != nil {
-		if err == service.ErrProductNotFound {
+		if err == service.ErrProductNotFound || err == service.ErrVariantNotFound || err == service.ErrDimensionNotFound {
 			utils.NotFoundError(c, err.Error())
 			return
 		}
+		if err == service.ErrInvalidScore {
+			utils.ValidationError(c, err.Error())
+			return
+		}
 		utils.InternalError(c, err)
 		return
 	}
@@ -56,6 +62,74 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 	utils.SuccessWithMessage(c, "创建测评成功", review)
 }
 
+// SubmitReview 用户投稿测评
+// @Summary 用户投稿测评
+// @Description 已验证邮箱的用户投稿测评，投稿进入审核队列，受每日投稿数量和待审核数量限制
+// @Tags 测评管理
+// @Accept json
+// @Produce json
+// @Param request body service.CreateReviewRequest true "测评信息"
+// @Success 200 {object} utils.Response{data=service.ReviewResponse}
+// @Failure 400,403,404,429 {object} utils.Response
+// @Security BearerAuth
+// @Router /reviews/submissions [post]
+func (h *ReviewHandler) SubmitReview(c *gin.Context) {
+	var req service.CreateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	review, err := h.reviewService.SubmitReview(c, userID, &req)
+	if err != nil {
+		switch err {
+		case service.ErrProductNotFound, service.ErrVariantNotFound, service.ErrDimensionNotFound:
+			utils.NotFoundError(c, err.Error())
+		case service.ErrInvalidScore:
+			utils.ValidationError(c, err.Error())
+		case service.ErrUserNotFound:
+			utils.UnauthorizedError(c)
+		case service.ErrEmailNotVerified, service.ErrUserBlocked:
+			utils.Error(c, http.StatusForbidden, err.Error())
+		case service.ErrSubmissionQuotaExceeded, service.ErrTooManyPendingSubmissions:
+			utils.TooManyRequests(c, err.Error())
+		default:
+			utils.InternalError(c, err)
+		}
+		return
+	}
+
+	utils.SuccessWithMessage(c, "投稿成功，请等待审核", review)
+}
+
+// ListModerationQueue 获取投稿审核队列
+// @Summary 获取投稿审核队列
+// @Description 编辑和管理员获取待审核的用户投稿，按提交时间先后排列
+// @Tags 测评管理
+// @Produce json
+// @Param page query int false "页码" default(1) minimum(1)
+// @Param pageSize query int false "每页数量" default(10) minimum(1)
+// @Success 200 {object} utils.Response{data=utils.PageData{list=[]service.ReviewResponse}}
+// @Security BearerAuth
+// @Router /reviews/moderation [get]
+func (h *ReviewHandler) ListModerationQueue(c *gin.Context) {
+	page, pageSize := utils.GetPageInfo(c)
+
+	reviews, total, err := h.reviewService.ListModerationQueue(c, page, pageSize)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.PageSuccess(c, reviews, total, page, pageSize)
+}
+
 // UpdateReview 更新测评
 // @Summary 更新测评信息
 // @Description 更新指定测评的信息
@@ -77,12 +151,30 @@ func (h *ReviewHandler) UpdateReview(c *gin.Context) {
 		return
 	}
 
-	review, err := h.reviewService.UpdateReview(c, id, &req)
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	review, err := h.reviewService.UpdateReview(c, id, userID, &req)
 	if err != nil {
-		if err == service.ErrReviewNotFound {
+		if err == service.ErrReviewNotFound || err == service.ErrVariantNotFound || err == service.ErrDimensionNotFound {
 			utils.NotFoundError(c, err.Error())
 			return
 		}
+		if err == service.ErrInvalidScore {
+			utils.ValidationError(c, err.Error())
+			return
+		}
+		if err == service.ErrReviewForbidden {
+			utils.ForbiddenError(c)
+			return
+		}
+		if err == service.ErrReviewNotEditable {
+			utils.ConflictError(c, err.Error())
+			return
+		}
 		utils.InternalError(c, err)
 		return
 	}
@@ -147,6 +239,7 @@ func (h *ReviewHandler) GetReview(c *gin.Context) {
 // @Produce json
 // @Param slug path string true "测评 Slug"
 // @Success 200 {object} utils.Response{data=service.ReviewResponse}
+// @Success 301 {object} utils.Response{data=service.SlugRedirect} "slug 已变更"
 // @Failure 400,404 {object} utils.Response
 // @Router /reviews/{slug} [get]
 func (h *ReviewHandler) GetReviewBySlug(c *gin.Context) {
@@ -158,6 +251,9 @@ func (h *ReviewHandler) GetReviewBySlug(c *gin.Context) {
 
 	review, err := h.reviewService.GetReviewBySlug(c, slug)
 	if err != nil {
+		if respondSlugMoved(c, err) {
+			return
+		}
 		switch err {
 		case service.ErrReviewNotFound:
 			utils.NotFoundError(c, err.Error())
@@ -178,6 +274,7 @@ func (h *ReviewHandler) GetReviewBySlug(c *gin.Context) {
 // @Produce json
 // @Param slug path string true "测评 Slug"
 // @Success 200 {object} utils.Response{data=service.ProductResponse}
+// @Success 301 {object} utils.Response{data=service.SlugRedirect} "slug 已变更"
 // @Failure 400,404,500 {object} utils.Response
 // @Router /reviews/{slug}/product [get]
 func (h *ReviewHandler) GetProductByReviewSlug(c *gin.Context) {
@@ -189,6 +286,9 @@ func (h *ReviewHandler) GetProductByReviewSlug(c *gin.Context) {
 
 	product, err := h.reviewService.GetProductByReviewSlug(c, slug)
 	if err != nil {
+		if respondSlugMoved(c, err) {
+			return
+		}
 		switch err {
 		case service.ErrReviewNotFound:
 			utils.NotFoundError(c, "测评不存在")
@@ -200,24 +300,26 @@ func (h *ReviewHandler) GetProductByReviewSlug(c *gin.Context) {
 		return
 	}
 
+	product.ConvertUnits(utils.GetUnitSystem(c))
 	utils.Success(c, product)
 }
 
 // ListReviews 获取测评列表
 // @Summary 获取测评列表
-// @Description 获取测评列表，支持分页和状态筛选，默认只返回已发布的测评
+// @Description 获取测评列表，支持分页，只返回已发布的测评；编辑和管理员可按状态筛选
 // @Tags 测评管理
 // @Produce json
 // @Param page query int false "页码" default(1) minimum(1)
 // @Param pageSize query int false "每页数量" default(10) minimum(1)
-// @Param status query string false "状态筛选" Enums(pending,published,rejected)
+// @Param status query string false "状态筛选，仅编辑和管理员有效" Enums(DRAFT,IN_REVIEW,CHANGES_REQUESTED,SCHEDULED,PUBLISHED,ARCHIVED)
+// @Param sort query string false "排序方式，默认推荐优先" Enums(latest,helpful)
 // @Success 200 {object} utils.Response{data=utils.PageData{list=[]service.ReviewResponse}}
 // @Router /reviews [get]
 func (h *ReviewHandler) ListReviews(c *gin.Context) {
 	page, pageSize := utils.GetPageInfo(c)
 	status := c.Query("status")
 
-	reviews, total, err := h.reviewService.ListReviews(c, page, pageSize, status)
+	reviews, total, err := h.reviewService.ListReviews(c, page, pageSize, status, c.Query("sort"))
 	if err != nil {
 		utils.InternalError(c, err)
 		return
@@ -232,6 +334,8 @@ func (h *ReviewHandler) ListReviews(c *gin.Context) {
 // @Tags 测评管理
 // @Produce json
 // @Param productId path uint true "产品ID"
+// @Param variantId query string false "产品版本ID"
+// @Param sort query string false "排序方式，默认推荐优先" Enums(latest,helpful)
 // @Param page query int false "页码" default(1) minimum(1)
 // @Param pageSize query int false "每页数量" default(10) minimum(1)
 // @Success 200 {object} utils.Response{data=utils.PageData{list=[]service.ReviewResponse}}
@@ -245,8 +349,15 @@ func (h *ReviewHandler) ListProductReviews(c *gin.Context) {
 	}
 
 	page, pageSize := utils.GetPageInfo(c)
+	variantID := c.Query("variantId")
+	if variantID != "" {
+		if _, err := uuid.Parse(variantID); err != nil {
+			utils.ParamError(c, "无效的版本ID")
+			return
+		}
+	}
 
-	reviews, total, err := h.reviewService.ListProductReviews(c, uint(productID), page, pageSize)
+	reviews, total, err := h.reviewService.ListProductReviews(c, uint(productID), variantID, c.Query("sort"), page, pageSize)
 	if err != nil {
 		if err.Error() == "产品不存在" {
 			utils.NotFoundError(c, err.Error())
@@ -258,3 +369,312 @@ func (h *ReviewHandler) ListProductReviews(c *gin.Context) {
 
 	utils.PageSuccess(c, reviews, total, page, pageSize)
 }
+
+// GetRelatedReviews 获取相关测评
+// @Summary 获取相关测评
+// @Description 获取同产品、同品牌、同器具类型的已发布测评，按关联程度、发布时间和浏览量排序
+// @Tags 测评管理
+// @Produce json
+// @Param id path string true "测评ID"
+// @Param limit query int false "数量" default(6) maximum(20)
+// @Success 200 {object} utils.Response{data=[]service.RelatedReview}
+// @Failure 404 {object} utils.Response "测评不存在"
+// @Router /reviews/{id}/related [get]
+func (h *ReviewHandler) GetRelatedReviews(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.DefaultRelatedReviews)))
+
+	reviews, err := h.reviewService.GetRelatedReviews(c, c.Param("id"), limit)
+	if err != nil {
+		if err == service.ErrReviewNotFound {
+			utils.NotFoundError(c, err.Error())
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, reviews)
+}
+
+// GetAuthorPage 获取作者主页
+// @Summary 获取作者主页
+// @Description 获取作者简介、统计和已发布的测评列表，简介和注册时间按作者的主页可见性设置返回，本人和管理员可见全部
+// @Tags 测评管理
+// @Produce json
+// @Param id path string true "用户ID"
+// @Param page query int false "页码" default(1) minimum(1)
+// @Param pageSize query int false "每页数量" default(10) minimum(1)
+// @Success 200 {object} utils.Response{data=service.AuthorPage}
+// @Failure 404 {object} utils.Response "用户不存在"
+// @Router /users/{id}/reviews [get]
+func (h *ReviewHandler) GetAuthorPage(c *gin.Context) {
+	page, pageSize := utils.GetPageInfo(c)
+	viewerID := utils.GetUserIDFromContext(c)
+	viewerRole := utils.GetUserRoleFromContext(c)
+
+	authorPage, err := h.reviewService.GetAuthorPage(c, c.Param("id"), viewerID, viewerRole, page, pageSize)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			utils.NotFoundError(c, "用户不存在")
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, authorPage)
+}
+
+// ListRevisions 获取测评修订历史
+// @Summary 获取测评修订历史
+// @Description 获取指定测评的所有修订版本，按版本号倒序
+// @Tags 测评管理
+// @Produce json
+// @Param id path string true "测评ID"
+// @Success 200 {object} utils.Response{data=[]service.ReviewRevisionSummary}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /reviews/{id}/revisions [get]
+func (h *ReviewHandler) ListRevisions(c *gin.Context) {
+	id := c.Param("id")
+
+	revisions, err := h.reviewService.ListRevisions(c, id)
+	if err != nil {
+		if err == service.ErrReviewNotFound {
+			utils.NotFoundError(c, err.Error())
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, revisions)
+}
+
+// GetRevision 获取测评修订版本详情
+// @Summary 获取测评修订版本详情
+// @Description 获取指定测评某一版本的完整内容
+// @Tags 测评管理
+// @Produce json
+// @Param id path string true "测评ID"
+// @Param version path int true "版本号"
+// @Success 200 {object} utils.Response{data=service.ReviewRevisionResponse}
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /reviews/{id}/revisions/{version} [get]
+func (h *ReviewHandler) GetRevision(c *gin.Context) {
+	id := c.Param("id")
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		utils.ParamError(c, "无效的版本号")
+		return
+	}
+
+	revision, err := h.reviewService.GetRevision(c, id, version)
+	if err != nil {
+		if err == service.ErrRevisionNotFound {
+			utils.NotFoundError(c, err.Error())
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, revision)
+}
+
+// DiffRevisions 比较测评修订版本
+// @Summary 比较测评修订版本
+// @Description 返回两个版本之间标题、正文、优缺点和总结的行级差异
+// @Tags 测评管理
+// @Produce json
+// @Param id path string true "测评ID"
+// @Param from query int true "起始版本号"
+// @Param to query int true "目标版本号"
+// @Success 200 {object} utils.Response{data=service.ReviewRevisionDiff}
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /reviews/{id}/revisions/diff [get]
+func (h *ReviewHandler) DiffRevisions(c *gin.Context) {
+	id := c.Param("id")
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		utils.ParamError(c, "无效的起始版本号")
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		utils.ParamError(c, "无效的目标版本号")
+		return
+	}
+
+	diff, err := h.reviewService.DiffRevisions(c, id, from, to)
+	if err != nil {
+		if err == service.ErrRevisionNotFound {
+			utils.NotFoundError(c, err.Error())
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, diff)
+}
+
+// RollbackReview 回滚测评到指定版本
+// @Summary 回滚测评到指定版本
+// @Description 使用指定版本的内容覆盖当前测评，并生成新的修订版本
+// @Tags 测评管理
+// @Produce json
+// @Param id path string true "测评ID"
+// @Param version path int true "版本号"
+// @Success 200 {object} utils.Response{data=service.ReviewResponse}
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /reviews/{id}/revisions/{version}/rollback [post]
+func (h *ReviewHandler) RollbackReview(c *gin.Context) {
+	id := c.Param("id")
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		utils.ParamError(c, "无效的版本号")
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	review, err := h.reviewService.RollbackReview(c, id, version, userID)
+	if err != nil {
+		if err == service.ErrReviewNotFound || err == service.ErrRevisionNotFound {
+			utils.NotFoundError(c, err.Error())
+			return
+		}
+		if err == service.ErrReviewForbidden {
+			utils.ForbiddenError(c)
+			return
+		}
+		if err == service.ErrReviewNotEditable {
+			utils.ConflictError(c, err.Error())
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "回滚测评成功", review)
+}
+
+// TransitionReview 变更测评状态
+// @Summary 变更测评状态
+// @Description 按编辑流程变更测评状态：作者可提交审核、撤回或重新编辑，审核人可要求修改、定时发布、发布或归档
+// @Tags 测评管理
+// @Accept json
+// @Produce json
+// @Param id path string true "测评ID"
+// @Param request body service.TransitionReviewRequest true "目标状态"
+// @Success 200 {object} utils.Response{data=service.ReviewResponse}
+// @Failure 400,403,404,429 {object} utils.Response
+// @Security BearerAuth
+// @Router /reviews/{id}/transition [post]
+func (h *ReviewHandler) TransitionReview(c *gin.Context) {
+	id := c.Param("id")
+
+	var req service.TransitionReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(c)
+	if userID == "" {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	review, err := h.reviewService.TransitionReview(c, id, userID, &req)
+	if err != nil {
+		switch err {
+		case service.ErrReviewNotFound:
+			utils.NotFoundError(c, err.Error())
+		case service.ErrInvalidTransition, service.ErrInvalidSchedule:
+			utils.ParamError(c, err.Error())
+		case service.ErrReviewForbidden:
+			utils.ForbiddenError(c)
+		case service.ErrSubmissionQuotaExceeded, service.ErrTooManyPendingSubmissions:
+			utils.TooManyRequests(c, err.Error())
+		default:
+			utils.InternalError(c, err)
+		}
+		return
+	}
+
+	utils.SuccessWithMessage(c, "变更测评状态成功", review)
+}
+
+// AssignReviewer 指派测评审核人
+// @Summary 指派测评审核人
+// @Description 为测评指派审核人，审核人必须是编辑或管理员，传空表示取消指派
+// @Tags 测评管理
+// @Accept json
+// @Produce json
+// @Param id path string true "测评ID"
+// @Param request body service.AssignReviewerRequest true "审核人"
+// @Success 200 {object} utils.Response{data=service.ReviewResponse}
+// @Failure 400,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /reviews/{id}/reviewer [put]
+func (h *ReviewHandler) AssignReviewer(c *gin.Context) {
+	id := c.Param("id")
+
+	var req service.AssignReviewerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	review, err := h.reviewService.AssignReviewer(c, id, &req)
+	if err != nil {
+		switch err {
+		case service.ErrReviewNotFound:
+			utils.NotFoundError(c, err.Error())
+		case service.ErrUserNotFound:
+			utils.NotFoundError(c, "用户不存在")
+		case service.ErrInvalidReviewer:
+			utils.ParamError(c, err.Error())
+		default:
+			utils.InternalError(c, err)
+		}
+		return
+	}
+
+	utils.SuccessWithMessage(c, "指派审核人成功", review)
+}
+
+// ListStatusLogs 获取测评状态变更记录
+// @Summary 获取测评状态变更记录
+// @Description 获取测评在编辑流程中的状态变更记录及编辑备注
+// @Tags 测评管理
+// @Produce json
+// @Param id path string true "测评ID"
+// @Success 200 {object} utils.Response{data=[]service.ReviewStatusLogResponse}
+// @Failure 404 {object} utils.Response
+// @Security BearerAuth
+// @Router /reviews/{id}/status-logs [get]
+func (h *ReviewHandler) ListStatusLogs(c *gin.Context) {
+	id := c.Param("id")
+
+	logs, err := h.reviewService.ListStatusLogs(c, id)
+	if err != nil {
+		if err == service.ErrReviewNotFound {
+			utils.NotFoundError(c, err.Error())
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, logs)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"github.com/gin-gonic/gin"
 
+	"beicun/back/model"
 	"beicun/back/service"
 	"beicun/back/utils"
 )
@@ -24,7 +25,6 @@ func (h *SearchHandler) RegisterRoutes(r *gin.Engine) {
 		search.GET("/products", h.SearchProducts)
 		search.GET("/reviews", h.SearchReviews)
 		search.GET("/brands", h.SearchBrands)
-		search.GET("/users", h.SearchUsers)
 	}
 }
 
@@ -107,16 +107,15 @@ func (h *SearchHandler) SearchBrands(c *gin.Context) {
 }
 
 // @Summary 搜索用户
-// @Description 根据关键词搜索用户（仅管理员）
+// @Description 根据关键词搜索用户，管理员可按邮箱搜索并获取完整信息，其他登录用户只能按名称搜索并获取公开信息
 // @Tags 搜索
 // @Accept json
 // @Produce json
 // @Param q query string true "搜索关键词"
-// @Success 200 {array} model.User
-// @Failure 400 {object} utils.Response
-// @Failure 401 {object} utils.Response
-// @Failure 403 {object} utils.Response
+// @Success 200 {array} service.UserBrief
+// @Failure 400,401 {object} utils.Response
 // @Failure 500 {object} utils.Response
+// @Security BearerAuth
 // @Router /api/search/users [get]
 func (h *SearchHandler) SearchUsers(c *gin.Context) {
 	query := c.Query("q")
@@ -125,7 +124,17 @@ func (h *SearchHandler) SearchUsers(c *gin.Context) {
 		return
 	}
 
-	users, err := h.searchService.SearchUsers(query)
+	if utils.GetUserRoleFromContext(c) == model.UserRoleAdmin {
+		users, err := h.searchService.SearchUsers(query)
+		if err != nil {
+			utils.InternalError(c, err)
+			return
+		}
+		utils.Success(c, users)
+		return
+	}
+
+	users, err := h.searchService.SearchPublicUsers(query)
 	if err != nil {
 		utils.InternalError(c, err)
 		return
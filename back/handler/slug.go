@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"beicun/back/service"
+	"beicun/back/utils"
+)
+
+// respondSlugMoved 旧 slug 返回 301 重定向信息，返回是否已处理
+func respondSlugMoved(c *gin.Context, err error) bool {
+	var moved *service.SlugMovedError
+	if !errors.As(err, &moved) {
+		return false
+	}
+
+	location := strings.Replace(c.FullPath(), ":slug", url.PathEscape(moved.Slug), 1)
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+
+	utils.MovedPermanently(c, location, &service.SlugRedirect{
+		Redirect:   true,
+		EntityType: moved.EntityType,
+		Slug:       moved.Slug,
+	})
+	return true
+}
@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
 	"beicun/back/service"
 	"beicun/back/utils"
@@ -43,12 +45,14 @@ func (h *StatsHandler) GetProductStats(c *gin.Context) {
 
 // GetRatingStats 获取产品评分统计数据
 // @Summary 获取产品评分统计数据
-// @Description 获取产品评分相关的统计数据
+// @Description 获取产品评分相关的统计数据，指定版本时只统计针对该版本的评分
 // @Tags 统计
 // @Accept json
 // @Produce json
 // @Param id path uint true "产品ID"
+// @Param variantId query string false "产品版本ID"
 // @Success 200 {object} cache.RatingStats
+// @Failure 400,404 {object} utils.Response
 // @Router /api/stats/products/{id}/ratings [get]
 func (h *StatsHandler) GetRatingStats(c *gin.Context) {
 	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -57,8 +61,20 @@ func (h *StatsHandler) GetRatingStats(c *gin.Context) {
 		return
 	}
 
-	stats, err := h.statsService.GetRatingStats(c, uint(productID))
+	variantID := c.Query("variantId")
+	if variantID != "" {
+		if _, err := uuid.Parse(variantID); err != nil {
+			utils.ParamError(c, "无效的版本ID")
+			return
+		}
+	}
+
+	stats, err := h.statsService.GetRatingStats(c, uint(productID), variantID)
 	if err != nil {
+		if errors.Is(err, service.ErrVariantNotFound) {
+			utils.NotFoundError(c, err.Error())
+			return
+		}
 		utils.InternalError(c, err)
 		return
 	}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"beicun/back/service"
+	"beicun/back/utils"
+)
+
+type TrashHandler struct {
+	trashService *service.TrashService
+}
+
+func NewTrashHandler(trashService *service.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// ListTrash 获取回收站列表
+// @Summary 获取回收站列表
+// @Description 管理员查看已删除的产品、用户、测评、评论、品牌和文件
+// @Tags 回收站
+// @Produce json
+// @Param type query string false "记录类型" Enums(product, user, review, comment, brand, file)
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Success 200 {object} utils.Response{data=utils.PageData{list=[]service.TrashItem}}
+// @Failure 400,401,403 {object} utils.Response
+// @Security BearerAuth
+// @Router /admin/trash [get]
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	page, pageSize := utils.GetPageInfo(c)
+	trashType := service.TrashType(c.Query("type"))
+
+	items, total, err := h.trashService.ListTrash(c, trashType, page, pageSize)
+	if err != nil {
+		if err == service.ErrInvalidTrashType {
+			utils.ParamError(c, err.Error())
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.PageSuccess(c, items, total, page, pageSize)
+}
+
+// RestoreTrash 恢复回收站记录
+// @Summary 恢复回收站记录
+// @Description 将已删除的记录恢复，所属的品牌、产品或测评仍在回收站中时需先恢复上级记录
+// @Tags 回收站
+// @Produce json
+// @Param type path string true "记录类型" Enums(product, user, review, comment, brand, file)
+// @Param id path string true "记录ID"
+// @Success 200 {object} utils.Response
+// @Failure 400,401,403,404,409 {object} utils.Response
+// @Security BearerAuth
+// @Router /admin/trash/{type}/{id}/restore [post]
+func (h *TrashHandler) RestoreTrash(c *gin.Context) {
+	trashType := service.TrashType(c.Param("type"))
+	id := c.Param("id")
+
+	if err := h.trashService.Restore(c, trashType, id); err != nil {
+		switch err {
+		case service.ErrInvalidTrashType:
+			utils.ParamError(c, err.Error())
+		case service.ErrTrashItemNotFound:
+			utils.NotFoundError(c, err.Error())
+		case service.ErrTrashFileConflict, service.ErrTrashParentInTrash:
+			utils.ConflictError(c, err.Error())
+		default:
+			utils.InternalError(c, err)
+		}
+		return
+	}
+
+	utils.SuccessWithMessage(c, "恢复成功", nil)
+}
+
+// PurgeTrash 彻底删除回收站记录
+// @Summary 彻底删除回收站记录
+// @Description 彻底删除回收站中的记录，文件会同时删除物理文件，仍有产品或子品牌的品牌无法删除，操作不可恢复
+// @Tags 回收站
+// @Produce json
+// @Param type path string true "记录类型" Enums(product, user, review, comment, brand, file)
+// @Param id path string true "记录ID"
+// @Success 200 {object} utils.Response
+// @Failure 400,401,403,404,409 {object} utils.Response
+// @Security BearerAuth
+// @Router /admin/trash/{type}/{id} [delete]
+func (h *TrashHandler) PurgeTrash(c *gin.Context) {
+	trashType := service.TrashType(c.Param("type"))
+	id := c.Param("id")
+
+	if err := h.trashService.Purge(c, trashType, id); err != nil {
+		switch err {
+		case service.ErrInvalidTrashType:
+			utils.ParamError(c, err.Error())
+		case service.ErrTrashItemNotFound:
+			utils.NotFoundError(c, err.Error())
+		case service.ErrTrashBrandInUse:
+			utils.ConflictError(c, err.Error())
+		default:
+			utils.InternalError(c, err)
+		}
+		return
+	}
+
+	utils.SuccessWithMessage(c, "彻底删除成功", nil)
+}
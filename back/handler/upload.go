@@ -225,3 +225,79 @@ func (h *UploadHandler) InitUpload(c *gin.Context) {
 		},
 	})
 }
+
+// UploadAvatar 上传头像
+// @Summary 上传头像
+// @Description 修正 EXIF 方向后居中裁剪为正方形，生成 512、256、128、64 像素多个尺寸并替换当前头像
+// @Tags 用户
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "头像图片，支持 JPG、PNG、GIF，不超过 5MB"
+// @Success 200 {object} utils.Response{data=service.AvatarResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/user/avatar [post]
+func (h *UploadHandler) UploadAvatar(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.ParamError(c, "未选择要上传的图片")
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(c)
+	avatar, err := h.uploadService.UploadAvatar(c, userID, file)
+	if err != nil {
+		switch err {
+		case service.ErrAvatarTooLarge, service.ErrAvatarInvalid, service.ErrAvatarTooSmall:
+			utils.ParamError(c, err.Error())
+		case service.ErrUserNotFound:
+			utils.NotFoundError(c, "用户不存在")
+		default:
+			h.logger.Error("上传头像失败", zap.String("userID", userID), zap.Error(err))
+			utils.ServerError(c, "上传头像失败")
+		}
+		return
+	}
+
+	utils.SuccessWithMessage(c, "头像已更新", avatar)
+}
+
+// UploadBrandLogo 上传品牌LOGO
+// @Summary 上传品牌LOGO
+// @Description 等比缩放后居中放入透明背景的正方形画布，生成 512、256、128 像素的 PNG 和 WebP 并替换品牌当前 LOGO
+// @Tags 品牌管理
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "品牌ID"
+// @Param file formData file true "LOGO 图片，支持 JPG、PNG、GIF，不超过 5MB"
+// @Success 200 {object} utils.Response{data=service.BrandLogoResponse}
+// @Failure 400,404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Security BearerAuth
+// @Router /api/brands/{id}/logo [post]
+func (h *UploadHandler) UploadBrandLogo(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.ParamError(c, "未选择要上传的图片")
+		return
+	}
+
+	brandID := c.Param("id")
+	userID := utils.GetUserIDFromContext(c)
+	logo, err := h.uploadService.UploadBrandLogo(c, userID, brandID, file)
+	if err != nil {
+		switch err {
+		case service.ErrBrandLogoTooLarge, service.ErrBrandLogoInvalid, service.ErrBrandLogoTooSmall:
+			utils.ParamError(c, err.Error())
+		case service.ErrBrandNotFound:
+			utils.NotFoundError(c, err.Error())
+		default:
+			h.logger.Error("上传品牌LOGO失败", zap.String("brandID", brandID), zap.Error(err))
+			utils.ServerError(c, "上传品牌LOGO失败")
+		}
+		return
+	}
+
+	utils.SuccessWithMessage(c, "品牌LOGO已更新", logo)
+}
@@ -5,6 +5,7 @@ import (
 	"beicun/back/service"
 	"beicun/back/utils"
 	"github.com/gin-gonic/gin"
+	"net/http"
 	"strconv"
 )
 
@@ -92,10 +93,14 @@ func (h *UserHandler) ListCurrentUserFavorites(c *gin.Context) {
 
 // AddToFavorites 添加收藏
 func (h *UserHandler) AddToFavorites(c *gin.Context) {
-	userID := utils.GetUserIDFromContext(c)
-	productID := c.Param("productId")
+	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
+	if err != nil {
+		utils.ParamError(c, "无效的产品ID")
+		return
+	}
 
-	if err := h.userService.AddToFavorites(c, userID, productID); err != nil {
+	userID := utils.GetUserIDFromContext(c)
+	if err := h.userService.AddToFavorites(c, userID, uint(productID)); err != nil {
 		utils.InternalError(c, err)
 		return
 	}
@@ -105,10 +110,14 @@ func (h *UserHandler) AddToFavorites(c *gin.Context) {
 
 // RemoveFromFavorites 取消收藏
 func (h *UserHandler) RemoveFromFavorites(c *gin.Context) {
-	userID := utils.GetUserIDFromContext(c)
-	productID := c.Param("productId")
+	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
+	if err != nil {
+		utils.ParamError(c, "无效的产品ID")
+		return
+	}
 
-	if err := h.userService.RemoveFromFavorites(c, userID, productID); err != nil {
+	userID := utils.GetUserIDFromContext(c)
+	if err := h.userService.RemoveFromFavorites(c, userID, uint(productID)); err != nil {
 		utils.InternalError(c, err)
 		return
 	}
@@ -201,3 +210,109 @@ func (h *UserHandler) UpdateUserStatus(c *gin.Context) {
 	}
 	utils.SuccessWithMessage(c, "状态更新成功", nil)
 }
+
+// UpdateUserTrustLevelRequest 更新评论信任等级请求
+type UpdateUserTrustLevelRequest struct {
+	TrustLevel model.TrustLevel `json:"trustLevel" binding:"required,oneof=NEW TRUSTED RESTRICTED"`
+}
+
+// UpdateUserTrustLevel 更新用户评论信任等级（管理员）
+// @Summary 更新用户评论信任等级
+// @Description 管理员调整用户的评论信任等级，决定评论是否需要先审后发
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param id path string true "用户ID"
+// @Param request body UpdateUserTrustLevelRequest true "信任等级"
+// @Success 200 {object} utils.Response
+// @Failure 400,401,403,404 {object} utils.Response
+// @Security BearerAuth
+// @Router /users/{id}/trust-level [put]
+func (h *UserHandler) UpdateUserTrustLevel(c *gin.Context) {
+	var req UpdateUserTrustLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	userID := c.Param("id")
+	if err := h.userService.UpdateUserTrustLevel(c, userID, req.TrustLevel); err != nil {
+		if err == service.ErrUserNotFound {
+			utils.NotFoundError(c, "用户不存在")
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+	utils.SuccessWithMessage(c, "信任等级更新成功", nil)
+}
+
+// GetPublicProfile 获取用户公开主页
+func (h *UserHandler) GetPublicProfile(c *gin.Context) {
+	viewerID := utils.GetUserIDFromContext(c)
+	viewerRole := utils.GetUserRoleFromContext(c)
+
+	profile, err := h.userService.GetPublicProfile(c, c.Param("id"), viewerID, viewerRole)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	utils.Success(c, profile)
+}
+
+// ListPublicFavorites 获取用户公开的收藏列表
+func (h *UserHandler) ListPublicFavorites(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	viewerID := utils.GetUserIDFromContext(c)
+	viewerRole := utils.GetUserRoleFromContext(c)
+	products, total, err := h.userService.ListPublicFavorites(c, c.Param("id"), viewerID, viewerRole, page, pageSize)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	utils.PageSuccess(c, products, total, page, pageSize)
+}
+
+// GetProfilePrivacy 获取当前用户的主页可见性设置
+func (h *UserHandler) GetProfilePrivacy(c *gin.Context) {
+	userID := utils.GetUserIDFromContext(c)
+	privacy, err := h.userService.GetProfilePrivacy(c, userID)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	utils.Success(c, privacy)
+}
+
+// UpdateProfilePrivacy 更新当前用户的主页可见性设置
+func (h *UserHandler) UpdateProfilePrivacy(c *gin.Context) {
+	var req service.ProfilePrivacyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, "无效的请求参数")
+		return
+	}
+
+	userID := utils.GetUserIDFromContext(c)
+	privacy, err := h.userService.UpdateProfilePrivacy(c, userID, &req)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	utils.SuccessWithMessage(c, "可见性设置已更新", privacy)
+}
+
+func respondProfileError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrUserNotFound:
+		utils.NotFoundError(c, "用户不存在")
+	case service.ErrFavoritesHidden:
+		utils.Error(c, http.StatusForbidden, err.Error())
+	case service.ErrInvalidProfileField, service.ErrInvalidProfileVisibility:
+		utils.ValidationError(c, err.Error())
+	default:
+		utils.InternalError(c, err)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"net/http/httputil"
@@ -72,10 +73,14 @@ func main() {
 	captchaService := service.NewCaptchaService(emailService, redisClient)
 	userService := service.NewUserService(db)
 	authService := service.NewAuthService(userService, captchaService, cfg)
-	productService := service.NewProductService(db)
-	reviewService := service.NewReviewService(db, productService)
+	viewService := service.NewViewService(db, redisClient, cfg)
+	realtimeService := service.NewRealtimeService(db, redisClient, cfg)
+	notificationService := service.NewNotificationService(db, cfg, emailService, realtimeService)
+	productService := service.NewProductService(db, viewService, notificationService)
+	reviewService := service.NewReviewService(db, cfg, productService, viewService, notificationService, cacheClient)
 	brandService := service.NewBrandService(db)
-	commentService := service.NewCommentService(db)
+	moderationService := service.NewModerationService(db, cfg)
+	commentService := service.NewCommentService(db, moderationService, notificationService, realtimeService)
 	utilityTypeService := service.NewUtilityTypeService(db)
 	productTypeService := service.NewProductTypeService(db)
 	channelTypeService := service.NewChannelTypeService(db)
@@ -84,7 +89,29 @@ func main() {
 	searchService := service.NewSearchService(db)
 	storageService := service.NewStorageService(db, cfg, redisClient, zap.L())
 	
-	uploadService := service.NewUploadService(db, zap.L(), &cfg.Storage)
+	uploadService := service.NewUploadService(db, zap.L(), &cfg.Storage, realtimeService)
+	accountService := service.NewAccountService(db, cfg, emailService, zap.L())
+	trashService := service.NewTrashService(db, cfg, accountService, zap.L())
+	dimensionService := service.NewScoreDimensionService(db)
+	reactionService := service.NewReactionService(db)
+	reportService := service.NewReportService(db, cfg, moderationService, notificationService, cacheClient)
+	followService := service.NewFollowService(db)
+	collectionService := service.NewCollectionService(db)
+
+	// 启动回收站自动清理
+	trashService.StartPurgeWorker(context.Background())
+	// 启动测评定时发布
+	reviewService.StartPublishScheduler(context.Background(), cfg.Review.PublishInterval)
+	// 补充渲染历史测评正文
+	reviewService.StartRenderBackfill(context.Background())
+	// 启动浏览量定期写入
+	viewService.StartFlushWorker(context.Background())
+	// 启动通知邮件摘要任务
+	notificationService.StartDigestWorker(context.Background())
+	// 启动实时事件订阅
+	realtimeService.StartSubscriber(context.Background())
+	// 启动数据导出和账号注销任务
+	accountService.StartWorker(context.Background())
 
 	// 初始化处理器
 	authHandler := handler.NewAuthHandler(authService, captchaService)
@@ -101,6 +128,15 @@ func main() {
 	searchHandler := handler.NewSearchHandler(searchService)
 	storageHandler := handler.NewStorageHandler(storageService, cfg) 
 	uploadHandler := handler.NewUploadHandler(uploadService, zap.L())
+	trashHandler := handler.NewTrashHandler(trashService)
+	dimensionHandler := handler.NewScoreDimensionHandler(dimensionService)
+	reactionHandler := handler.NewReactionHandler(reactionService)
+	reportHandler := handler.NewReportHandler(reportService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	realtimeHandler := handler.NewRealtimeHandler(realtimeService, cfg)
+	followHandler := handler.NewFollowHandler(followService)
+	collectionHandler := handler.NewCollectionHandler(collectionService)
+	accountHandler := handler.NewAccountHandler(accountService)
 
 	// 创建路由引擎
 	r := gin.Default()
@@ -124,6 +160,15 @@ func main() {
 		searchHandler,
 		storageHandler,
 		uploadHandler,
+		trashHandler,
+		dimensionHandler,
+		reactionHandler,
+		reportHandler,
+		notificationHandler,
+		realtimeHandler,
+		followHandler,
+		collectionHandler,
+		accountHandler,
 		authService,
 		cfg.JWT.Secret,
 		cfg,
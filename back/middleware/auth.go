@@ -48,6 +48,11 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "无效的用户信息"})
 			return
 		}
+		// 已注销的账号在注销前签发的令牌全部失效
+		if user.Status == model.UserStatusDeleted {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "账号已注销"})
+			return
+		}
 
 		// 设置用户信息到上下文
 		utils.SetUserContext(c, user)
@@ -55,6 +60,45 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	}
 }
 
+// RequireStreamAuth 实时推送连接认证，浏览器 EventSource 无法设置请求头，允许通过 access_token 查询参数携带令牌，请求日志会隐去该参数
+func (m *AuthMiddleware) RequireStreamAuth() gin.HandlerFunc {
+	requireAuth := m.RequireAuth()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		requireAuth(c)
+	}
+}
+
+// OptionalAuth 可选认证，携带有效令牌时设置用户信息，否则按匿名访问继续处理
+func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.Next()
+			return
+		}
+
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		claims, err := utils.ParseToken(tokenString, m.jwtSecret)
+		if err != nil {
+			c.Next()
+			return
+		}
+		user, err := m.authService.GetUserFromToken(c, claims)
+		if err != nil || user.Status == model.UserStatusDeleted {
+			c.Next()
+			return
+		}
+
+		utils.SetUserContext(c, user)
+		c.Next()
+	}
+}
+
 // RequireRole 需要特定角色
 func (m *AuthMiddleware) RequireRole(role model.UserRole) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -71,6 +115,18 @@ func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
 	return m.RequireRole(model.UserRoleAdmin)
 }
 
+// RequireEditor 需要编辑或管理员权限
+func (m *AuthMiddleware) RequireEditor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := utils.GetUserRoleFromContext(c)
+		if role != model.UserRoleEditor && role != model.UserRoleAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "无权访问"})
+			return
+		}
+		c.Next()
+	}
+}
+
 // RequireUser 需要普通用户权限
 func (m *AuthMiddleware) RequireUser() gin.HandlerFunc {
 	return m.RequireRole(model.UserRoleUser)
@@ -0,0 +1,26 @@
+package middleware
+
+import "testing"
+
+func TestRedactPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "no query", path: "/api/events", want: "/api/events"},
+		{name: "unrelated query kept as is", path: "/api/reviews?page=2&b=1", want: "/api/reviews?page=2&b=1"},
+		{name: "token redacted", path: "/api/events?access_token=abc.def.ghi", want: "/api/events?access_token=REDACTED"},
+		{name: "token among other params", path: "/api/events?b=2&access_token=abc&a=1", want: "/api/events?a=1&access_token=REDACTED&b=2"},
+		{name: "repeated token", path: "/s?access_token=a&access_token=b", want: "/s?access_token=REDACTED"},
+		{name: "unparsable query dropped", path: "/s?access_token=%zz", want: "/s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactPath(tt.path); got != tt.want {
+				t.Errorf("redactPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
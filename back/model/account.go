@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// DataExportStatus 个人数据导出任务状态
+type DataExportStatus string
+
+const (
+	DataExportPending    DataExportStatus = "PENDING"    // 等待处理
+	DataExportProcessing DataExportStatus = "PROCESSING" // 正在打包
+	DataExportCompleted  DataExportStatus = "COMPLETED"  // 已完成，可以下载
+	DataExportFailed     DataExportStatus = "FAILED"     // 打包失败
+	DataExportExpired    DataExportStatus = "EXPIRED"    // 已过期，文件已删除
+)
+
+// DataExport 个人数据导出任务
+type DataExport struct {
+	ID          string           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`       // 任务ID
+	UserID      string           `gorm:"type:uuid;not null;index" json:"userId"`                          // 用户ID
+	Status      DataExportStatus `gorm:"type:varchar(20);not null;default:'PENDING';index" json:"status"` // 状态
+	FilePath    string           `gorm:"type:varchar(1024)" json:"-"`                                     // 导出文件存储路径
+	Size        int64            `gorm:"not null;default:0" json:"size"`                                  // 导出文件大小（字节）
+	Error       string           `gorm:"type:text" json:"error,omitempty"`                                // 失败原因
+	CompletedAt *time.Time       `json:"completedAt,omitempty"`                                           // 完成时间
+	ExpiresAt   *time.Time       `gorm:"index" json:"expiresAt,omitempty"`                                // 文件过期时间
+	CreatedAt   time.Time        `gorm:"not null" json:"createdAt"`                                       // 申请时间
+	UpdatedAt   time.Time        `gorm:"not null" json:"updatedAt"`                                       // 更新时间
+
+	User User `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"-"` // 用户
+}
+
+// AccountDeletionStatus 账号注销申请状态
+type AccountDeletionStatus string
+
+const (
+	AccountDeletionPending   AccountDeletionStatus = "PENDING"   // 冷静期中，可以撤销
+	AccountDeletionCancelled AccountDeletionStatus = "CANCELLED" // 已撤销
+	AccountDeletionCompleted AccountDeletionStatus = "COMPLETED" // 已注销
+)
+
+// AccountDeletion 账号注销申请，冷静期结束后匿名化账号，测评和评论保留并显示为已注销用户
+type AccountDeletion struct {
+	ID          string                `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`       // 申请ID
+	UserID      string                `gorm:"type:uuid;not null;index" json:"userId"`                          // 用户ID
+	Status      AccountDeletionStatus `gorm:"type:varchar(20);not null;default:'PENDING';index" json:"status"` // 状态
+	Reason      string                `gorm:"type:text" json:"reason,omitempty"`                               // 注销原因
+	ScheduledAt time.Time             `gorm:"not null;index" json:"scheduledAt"`                               // 计划注销时间
+	CancelledAt *time.Time            `json:"cancelledAt,omitempty"`                                           // 撤销时间
+	CompletedAt *time.Time            `json:"completedAt,omitempty"`                                           // 注销完成时间
+	CreatedAt   time.Time             `gorm:"not null" json:"createdAt"`                                       // 申请时间
+
+	User User `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"-"` // 用户
+}
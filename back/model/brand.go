@@ -3,19 +3,55 @@ package model
 import (
 	"time"
 
+	"gorm.io/gorm"
 )
 
 // Brand 品牌
 type Brand struct {
-	ID          string    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`            // 品牌ID
-	Slug        string    `gorm:"type:varchar(50);not null;uniqueIndex" json:"slug"`                    // 品牌Slug
-	Name        string    `gorm:"type:varchar(50);not null;uniqueIndex" json:"name"`                    // 品牌名称
-	Description string    `gorm:"type:text" json:"description"`                                         // 品牌描述
-	Website     *string   `gorm:"type:varchar(255)" json:"website,omitempty"`                           // 品牌网站
-	Logo        string    `gorm:"type:varchar(255)" json:"logo"`                                        // 品牌LOGO
-	SortOrder   int       `gorm:"default:0;index" json:"sortOrder"`                                     // 排序顺序
-	CreatedAt   time.Time `gorm:"not null" json:"createdAt"`                                           // 创建时间
-	UpdatedAt   time.Time `gorm:"not null" json:"updatedAt"`                                           // 更新时间
-
-	Products []Product `gorm:"foreignKey:BrandID;constraint:OnDelete:RESTRICT" json:"products,omitempty"` // 关联的产品
+	ID           string            `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"` // 品牌ID
+	Slug         string            `gorm:"type:varchar(50);not null;uniqueIndex" json:"slug"`         // 品牌Slug
+	Name         string            `gorm:"type:varchar(50);not null;uniqueIndex" json:"name"`         // 品牌名称
+	Description  string            `gorm:"type:text" json:"description"`                              // 品牌描述
+	Website      *string           `gorm:"type:varchar(255)" json:"website,omitempty"`                // 品牌网站
+	Logo         string            `gorm:"type:varchar(255)" json:"logo"`                             // 品牌LOGO
+	SortOrder    int               `gorm:"default:0;index" json:"sortOrder"`                          // 排序顺序
+	ParentID     *string           `gorm:"type:uuid;index" json:"parentId,omitempty"`                 // 母品牌ID，为空表示顶级品牌
+	Country      string            `gorm:"type:varchar(2)" json:"country,omitempty"`                  // 原产国（ISO 3166-1 二位代码）
+	FoundedYear  *int              `json:"foundedYear,omitempty"`                                     // 创立年份
+	SocialLinks  BrandSocialLinks  `gorm:"type:jsonb;serializer:json" json:"socialLinks,omitempty"`   // 社交媒体链接
+	LogoVariants BrandLogoVariants `gorm:"type:jsonb;serializer:json" json:"logoVariants,omitempty"`  // LOGO 各尺寸各格式地址
+	CreatedAt    time.Time         `gorm:"not null" json:"createdAt"`                                 // 创建时间
+	UpdatedAt    time.Time         `gorm:"not null" json:"updatedAt"`                                 // 更新时间
+	DeletedAt    gorm.DeletedAt    `gorm:"index" json:"-"`                                            // 软删除
+
+	Products  []Product `gorm:"foreignKey:BrandID;constraint:OnDelete:RESTRICT" json:"products,omitempty"`   // 关联的产品
+	SubBrands []Brand   `gorm:"foreignKey:ParentID;constraint:OnDelete:RESTRICT" json:"subBrands,omitempty"` // 子品牌
+}
+
+// BrandSocialLinks 品牌社交媒体链接，键为平台，值为主页地址
+type BrandSocialLinks map[string]string
+
+// 支持的社交媒体平台
+var BrandSocialPlatforms = map[string]bool{
+	"weibo":       true,
+	"bilibili":    true,
+	"xiaohongshu": true,
+	"douyin":      true,
+	"taobao":      true,
+	"jd":          true,
+	"twitter":     true,
+	"instagram":   true,
+	"facebook":    true,
+	"youtube":     true,
+	"tiktok":      true,
 }
+
+// BrandLogoVariant 品牌LOGO的一个尺寸，同时提供 PNG 和 WebP 格式
+type BrandLogoVariant struct {
+	Size int    `json:"size"` // 边长（像素）
+	PNG  string `json:"png"`  // PNG 地址
+	WebP string `json:"webp"` // WebP 地址
+}
+
+// BrandLogoVariants 品牌LOGO各尺寸，从大到小
+type BrandLogoVariants []BrandLogoVariant
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// CollectionVisibility 收藏夹可见性
+type CollectionVisibility string
+
+const (
+	CollectionVisibilityPublic  CollectionVisibility = "PUBLIC"  // 公开，出现在用户主页
+	CollectionVisibilityPrivate CollectionVisibility = "PRIVATE" // 私有，仅本人和持有分享链接的用户可见
+)
+
+// IsValid 检查收藏夹可见性是否有效
+func (v CollectionVisibility) IsValid() bool {
+	return v == CollectionVisibilityPublic || v == CollectionVisibilityPrivate
+}
+
+// Collection 用户创建的命名收藏夹，如心愿单
+type Collection struct {
+	ID          string               `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`     // 收藏夹ID
+	UserID      string               `gorm:"type:uuid;not null;index" json:"userId"`                        // 创建者ID
+	Name        string               `gorm:"type:varchar(100);not null" json:"name"`                        // 名称
+	Description string               `gorm:"type:text" json:"description"`                                  // 描述
+	Visibility  CollectionVisibility `gorm:"type:varchar(20);not null;default:'PRIVATE'" json:"visibility"` // 可见性
+	ShareToken  *string              `gorm:"type:varchar(32);uniqueIndex" json:"-"`                         // 分享令牌，为空表示未开启分享链接
+	CreatedAt   time.Time            `gorm:"not null" json:"createdAt"`                                     // 创建时间
+	UpdatedAt   time.Time            `gorm:"not null" json:"updatedAt"`                                     // 更新时间
+
+	User  User             `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"-"`       // 创建者
+	Items []CollectionItem `gorm:"foreignKey:CollectionID;constraint:OnDelete:CASCADE" json:"items,omitempty"` // 收藏的产品
+}
+
+// CollectionItem 收藏夹中的产品
+type CollectionItem struct {
+	CollectionID string    `gorm:"type:uuid;primaryKey" json:"collectionId"` // 收藏夹ID
+	ProductID    uint      `gorm:"primaryKey;index" json:"productId"`        // 产品ID
+	Position     int       `gorm:"not null;default:0" json:"position"`       // 排序位置，从小到大
+	Note         string    `gorm:"type:varchar(500)" json:"note"`            // 备注
+	CreatedAt    time.Time `gorm:"not null" json:"createdAt"`                // 加入时间
+
+	Product Product `gorm:"foreignKey:ProductID;references:ID;constraint:OnDelete:CASCADE" json:"product,omitempty"` // 产品
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// 维度评分范围
+const (
+	MinDimensionScore = 0
+	MaxDimensionScore = 10
+)
+
+// ScoreDimension 评分维度，按器具类型配置
+type ScoreDimension struct {
+	ID            string    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`             // 维度ID
+	UtilityTypeID string    `gorm:"type:uuid;not null;uniqueIndex:idx_dimension_key" json:"utilityTypeId"` // 器具类型ID
+	Key           string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_dimension_key" json:"key"`    // 维度标识，如 stimulation、cleaning
+	Name          string    `gorm:"type:varchar(50);not null" json:"name"`                                 // 维度名称
+	Description   string    `gorm:"type:text" json:"description"`                                          // 维度说明
+	SortOrder     int       `gorm:"default:0;index" json:"sortOrder"`                                      // 排序顺序
+	CreatedAt     time.Time `gorm:"not null" json:"createdAt"`                                             // 创建时间
+	UpdatedAt     time.Time `gorm:"not null" json:"updatedAt"`                                             // 更新时间
+
+	UtilityType UtilityType `gorm:"foreignKey:UtilityTypeID;references:ID;constraint:OnDelete:CASCADE" json:"-"` // 关联器具类型
+}
+
+// ReviewScore 测评的维度评分
+type ReviewScore struct {
+	ReviewID    string    `gorm:"type:uuid;primaryKey" json:"reviewId"`                                     // 测评ID
+	DimensionID string    `gorm:"type:uuid;primaryKey;index" json:"dimensionId"`                            // 维度ID
+	Score       float64   `gorm:"type:decimal(3,1);not null;check:score >= 0 AND score <= 10" json:"score"` // 评分(0-10)
+	CreatedAt   time.Time `gorm:"not null" json:"createdAt"`                                                // 创建时间
+
+	Review    Review         `gorm:"foreignKey:ReviewID;references:ID;constraint:OnDelete:CASCADE" json:"-"`    // 关联测评
+	Dimension ScoreDimension `gorm:"foreignKey:DimensionID;references:ID;constraint:OnDelete:CASCADE" json:"-"` // 关联维度
+}
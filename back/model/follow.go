@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// FollowTargetType 关注对象类型
+type FollowTargetType string
+
+const (
+	FollowTargetBrand   FollowTargetType = "brand"   // 品牌
+	FollowTargetAuthor  FollowTargetType = "author"  // 测评作者
+	FollowTargetProduct FollowTargetType = "product" // 产品
+)
+
+// IsValid 检查关注对象类型是否有效
+func (t FollowTargetType) IsValid() bool {
+	switch t {
+	case FollowTargetBrand, FollowTargetAuthor, FollowTargetProduct:
+		return true
+	}
+	return false
+}
+
+// Follow 用户关注的品牌、作者或产品
+type Follow struct {
+	UserID     string           `gorm:"type:uuid;primaryKey" json:"userId"`                                    // 用户ID
+	TargetType FollowTargetType `gorm:"type:varchar(20);primaryKey;index:idx_follow_target" json:"targetType"` // 对象类型
+	TargetID   string           `gorm:"type:varchar(36);primaryKey;index:idx_follow_target" json:"targetId"`   // 对象ID，产品ID为整数，统一以字符串保存
+	CreatedAt  time.Time        `gorm:"not null;index" json:"createdAt"`                                       // 关注时间
+
+	User User `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"-"` // 用户
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// NotificationType 通知类型
+type NotificationType string
+
+const (
+	NotificationReply            NotificationType = "REPLY"             // 评论被回复
+	NotificationMention          NotificationType = "MENTION"           // 在评论中被提及
+	NotificationReviewApproved   NotificationType = "REVIEW_APPROVED"   // 测评投稿通过审核
+	NotificationPriceDrop        NotificationType = "PRICE_DROP"        // 收藏的产品降价
+	NotificationModerationResult NotificationType = "MODERATION_RESULT" // 内容审核或举报处理结果
+)
+
+// NotificationTypes 所有通知类型
+var NotificationTypes = []NotificationType{
+	NotificationReply,
+	NotificationMention,
+	NotificationReviewApproved,
+	NotificationPriceDrop,
+	NotificationModerationResult,
+}
+
+// IsValid 检查通知类型是否有效
+func (t NotificationType) IsValid() bool {
+	for _, v := range NotificationTypes {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDigest 回复和提及较为频繁，邮件通过定期摘要汇总发送，其他类型立即发送
+func (t NotificationType) IsDigest() bool {
+	return t == NotificationReply || t == NotificationMention
+}
+
+// NotificationChannel 通知投递方式
+type NotificationChannel string
+
+const (
+	NotificationChannelInApp NotificationChannel = "IN_APP" // 仅站内通知
+	NotificationChannelEmail NotificationChannel = "EMAIL"  // 站内通知并发送邮件
+	NotificationChannelOff   NotificationChannel = "OFF"    // 不接收
+)
+
+// IsValid 检查投递方式是否有效
+func (c NotificationChannel) IsValid() bool {
+	switch c {
+	case NotificationChannelInApp, NotificationChannelEmail, NotificationChannelOff:
+		return true
+	}
+	return false
+}
+
+// DefaultNotificationChannels 用户未设置时各类型通知的投递方式
+var DefaultNotificationChannels = map[NotificationType]NotificationChannel{
+	NotificationReply:            NotificationChannelInApp,
+	NotificationMention:          NotificationChannelInApp,
+	NotificationReviewApproved:   NotificationChannelEmail,
+	NotificationPriceDrop:        NotificationChannelInApp,
+	NotificationModerationResult: NotificationChannelEmail,
+}
+
+// NotificationPreferences 各类型通知的投递方式
+type NotificationPreferences map[NotificationType]NotificationChannel
+
+// Channel 获取通知类型的投递方式，未设置时使用默认值
+func (p NotificationPreferences) Channel(t NotificationType) NotificationChannel {
+	if c, ok := p[t]; ok && c.IsValid() {
+		return c
+	}
+	return DefaultNotificationChannels[t]
+}
+
+// Notification 站内通知
+type Notification struct {
+	ID         string           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`    // 通知ID
+	UserID     string           `gorm:"type:uuid;not null;index:idx_notification_user" json:"userId"` // 接收人ID
+	Type       NotificationType `gorm:"type:varchar(30);not null" json:"type"`                        // 通知类型
+	ActorID    *string          `gorm:"type:uuid" json:"actorId,omitempty"`                           // 触发人ID，为空表示系统通知
+	TargetType string           `gorm:"type:varchar(20)" json:"targetType"`                           // 关联对象类型
+	TargetID   string           `gorm:"type:varchar(64)" json:"targetId"`                             // 关联对象ID，产品为数字ID
+	Title      string           `gorm:"type:varchar(255);not null" json:"title"`                      // 标题
+	Content    string           `gorm:"type:text" json:"content"`                                     // 内容摘要
+	Data       json.RawMessage  `gorm:"type:jsonb" json:"data,omitempty"`                             // 跳转所需的附加数据
+	ReadAt     *time.Time       `json:"readAt,omitempty"`                                             // 阅读时间
+	EmailedAt  *time.Time       `json:"-"`                                                            // 邮件发送时间
+	CreatedAt  time.Time        `gorm:"not null;index:idx_notification_user" json:"createdAt"`        // 创建时间
+
+	User  User  `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"-"`   // 接收人
+	Actor *User `gorm:"foreignKey:ActorID;references:ID;constraint:OnDelete:SET NULL" json:"-"` // 触发人
+}
+
+// NotificationSetting 用户通知设置
+type NotificationSetting struct {
+	UserID      string                  `gorm:"type:uuid;primaryKey" json:"userId"`            // 用户ID
+	Preferences NotificationPreferences `gorm:"type:jsonb;serializer:json" json:"preferences"` // 各类型通知的投递方式，未设置的类型使用默认值
+	UpdatedAt   time.Time               `gorm:"not null" json:"updatedAt"`                     // 更新时间
+
+	User User `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"-"` // 用户
+}
@@ -21,6 +21,8 @@ type Product struct {
 	ChannelLength    float64          `json:"channelLength"`                                            // 通道长度
 	TotalLength      float64          `json:"totalLength"`                                              // 总长度
 	Weight           float64          `json:"weight"`                                                   // 重量
+	LengthUnit       LengthUnit       `gorm:"type:varchar(10);default:'cm'" json:"lengthUnit"`          // 尺寸单位
+	WeightUnit       WeightUnit       `gorm:"type:varchar(10);default:'g'" json:"weightUnit"`           // 重量单位
 	Version          string           `gorm:"type:varchar(50)" json:"version"`                          // 版本
 	IsReversible     bool             `json:"isReversible"`                                             // 是否可逆
 	Stimulation      StimulationLevel `gorm:"type:varchar(20)" json:"stimulation"`                      // 刺激度
@@ -60,6 +62,27 @@ type Product struct {
 	Ratings      []Rating `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"ratings,omitempty"` // 产品评分
 	Tags         []ProductTag    `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"tags,omitempty"`
 	Reviews      []Review        `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"reviews,omitempty"` // 产品测评
+	Variants     []ProductVariant `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"variants,omitempty"` // 产品版本
+}
+
+// ProductVariant 产品版本（同一产品的不同版本/款式），尺寸与重量沿用所属产品的单位
+type ProductVariant struct {
+	ID            string          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`     // 版本ID
+	ProductID     uint            `gorm:"index;not null" json:"productId"`                               // 所属产品ID
+	Name          string          `gorm:"type:varchar(50);not null" json:"name"`                        // 版本名称
+	Price         *float64        `json:"price,omitempty"`                                               // 价格（为空时沿用产品价格）
+	Height        *float64        `json:"height,omitempty"`                                              // 高度
+	Width         *float64        `json:"width,omitempty"`                                               // 宽度
+	Length        *float64        `json:"length,omitempty"`                                              // 长度
+	ChannelLength *float64        `json:"channelLength,omitempty"`                                       // 通道长度
+	TotalLength   *float64        `json:"totalLength,omitempty"`                                         // 总长度
+	Weight        *float64        `json:"weight,omitempty"`                                              // 重量
+	Images        json.RawMessage `gorm:"type:jsonb" json:"images"`                                     // 版本图片
+	SortOrder     int             `gorm:"default:0;index" json:"sortOrder"`                             // 排序顺序
+	CreatedAt     time.Time       `gorm:"not null" json:"createdAt"`                                    // 创建时间
+	UpdatedAt     time.Time       `gorm:"not null" json:"updatedAt"`                                    // 更新时间
+
+	Product Product `gorm:"foreignKey:ProductID;references:ID;constraint:OnDelete:CASCADE" json:"-"` // 关联产品
 }
 
 // MainImage 主图
@@ -91,13 +114,15 @@ type ProductImage struct {
 type Rating struct {
 	ID        string    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`              // 评分ID
 	ProductID uint      `gorm:"index;not null" json:"productId"`                                        // 产品ID
+	VariantID *string   `gorm:"type:uuid;index" json:"variantId,omitempty"`                             // 产品版本ID，为空表示针对整个产品
 	UserID    string    `gorm:"index;not null" json:"userId"`                                          // 用户ID
 	Rating    float64   `gorm:"type:decimal(2,1);not null;check:rating >= 1 AND rating <= 5" json:"rating"` // 评分(1-5)
 	Reason    *string   `gorm:"type:text" json:"reason,omitempty"`                                      // 评分理由
 	CreatedAt time.Time `gorm:"not null" json:"createdAt"`                                             // 创建时间
 
-	Product Product `gorm:"foreignKey:ProductID;references:ID;constraint:OnDelete:CASCADE" json:"-"`     // 关联产品
-	User    User    `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"-"`       // 关联用户
+	Product Product         `gorm:"foreignKey:ProductID;references:ID;constraint:OnDelete:CASCADE" json:"-"`     // 关联产品
+	Variant *ProductVariant `gorm:"foreignKey:VariantID;references:ID;constraint:OnDelete:SET NULL" json:"-"`    // 关联版本
+	User    User            `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"-"`       // 关联用户
 }
 
 // Review 测评
@@ -105,24 +130,70 @@ type Review struct {
 	ID            string         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`       // 测评ID
 	Title         string         `gorm:"not null" json:"title"`                                          // 标题
 	Cover         string         `gorm:"not null" json:"cover"`                                          // 封面
-	Slug          string         `gorm:"index" json:"slug"`                                              // slug
-	Status        ReviewStatus   `gorm:"type:varchar(20);default:'PENDING';index" json:"status"`          // 状态
+	Slug          string         `gorm:"uniqueIndex:idx_reviews_slug_unique" json:"slug"`                // slug，唯一
+	Status        ReviewStatus   `gorm:"type:varchar(20);default:'DRAFT';index" json:"status"`            // 状态
 	ProductID     uint           `gorm:"index;not null" json:"productId"`                                // 产品ID
+	VariantID     *string        `gorm:"type:uuid;index" json:"variantId,omitempty"`                     // 产品版本ID，为空表示针对整个产品
 	UserID        string         `gorm:"index;not null" json:"userId"`                                   // 用户ID
 	Content       string         `gorm:"type:text;not null" json:"content"`                              // 内容
+	ContentHTML   string         `gorm:"type:text" json:"contentHtml"`                                   // 渲染并净化后的正文 HTML
+	TOC           json.RawMessage `gorm:"type:jsonb" json:"toc"`                                         // 正文目录
+	ReadingTime   int            `gorm:"default:0" json:"readingTime"`                                   // 预计阅读时长（分钟）
 	Pros          pq.StringArray `gorm:"type:text[]" json:"pros"`                                        // 优点列表
 	Cons          pq.StringArray `gorm:"type:text[]" json:"cons"`                                        // 缺点列表
 	Conclusion    string         `gorm:"type:text" json:"conclusion"`                                    // 总结
 	Views         int            `gorm:"default:0;index" json:"views"`                                   // 浏览量
 	RatingCount   int            `gorm:"default:0;index" json:"ratingCount"`                             // 评论数量
 	IsRecommended bool           `gorm:"default:false;index" json:"isRecommended"`                       // 是否推荐
+	IsCommunity   bool           `gorm:"default:false;index" json:"isCommunity"`                         // 是否为用户投稿
+	HelpfulCount    int          `gorm:"default:0" json:"helpfulCount"`                                  // 有帮助数
+	NotHelpfulCount int          `gorm:"default:0" json:"notHelpfulCount"`                               // 没帮助数
+	HelpfulScore    float64      `gorm:"default:0;index" json:"helpfulScore"`                            // 有帮助排序分（Wilson 下界）
 	PublishedAt   *time.Time     `gorm:"index" json:"publishedAt,omitempty"`                            // 发布时间
+	ScheduledAt   *time.Time     `gorm:"index" json:"scheduledAt,omitempty"`                            // 定时发布时间
+	ReviewerID    *string        `gorm:"type:uuid;index" json:"reviewerId,omitempty"`                   // 审核人ID
 	CreatedAt     time.Time      `gorm:"not null" json:"createdAt"`                                     // 创建时间
 	UpdatedAt     time.Time      `gorm:"not null" json:"updatedAt"`                                     // 更新时间
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`                                                // 软删除
 
-	Product  *Product   `gorm:"foreignKey:ProductID;references:ID;constraint:OnDelete:CASCADE" json:"-"`  // 关联产品
+	Product  *Product        `gorm:"foreignKey:ProductID;references:ID;constraint:OnDelete:CASCADE" json:"-"`  // 关联产品
+	Variant  *ProductVariant `gorm:"foreignKey:VariantID;references:ID;constraint:OnDelete:SET NULL" json:"-"` // 关联版本
 	Author   User       `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"author"` // 作者
+	Reviewer *User      `gorm:"foreignKey:ReviewerID;references:ID;constraint:OnDelete:SET NULL" json:"-"` // 审核人
 	Comments []Comment  `gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE" json:"comments,omitempty"` // 评论列表
+	Revisions []ReviewRevision `gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE" json:"-"`          // 修订历史
+	Scores    []ReviewScore    `gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE" json:"-"`          // 维度评分
+}
+
+// ReviewRevision 测评修订版本
+type ReviewRevision struct {
+	ID         string         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`       // 修订ID
+	ReviewID   string         `gorm:"type:uuid;not null;uniqueIndex:idx_review_revision" json:"reviewId"` // 测评ID
+	Version    int            `gorm:"not null;uniqueIndex:idx_review_revision" json:"version"`         // 版本号，从1开始递增
+	Title      string         `gorm:"not null" json:"title"`                                          // 标题
+	Content    string         `gorm:"type:text;not null" json:"content"`                              // 内容
+	Pros       pq.StringArray `gorm:"type:text[]" json:"pros"`                                        // 优点列表
+	Cons       pq.StringArray `gorm:"type:text[]" json:"cons"`                                        // 缺点列表
+	Conclusion string         `gorm:"type:text" json:"conclusion"`                                    // 总结
+	EditorID   string         `gorm:"type:uuid;index;not null" json:"editorId"`                       // 修改人ID
+	Note       string         `gorm:"type:varchar(255)" json:"note"`                                  // 修订说明
+	CreatedAt  time.Time      `gorm:"not null" json:"createdAt"`                                     // 创建时间
+
+	Editor User `gorm:"foreignKey:EditorID;references:ID;constraint:OnDelete:CASCADE" json:"-"` // 修改人
+}
+
+// ReviewStatusLog 测评状态变更记录
+type ReviewStatusLog struct {
+	ID         string       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"` // 记录ID
+	ReviewID   string       `gorm:"type:uuid;not null;index" json:"reviewId"`                  // 测评ID
+	FromStatus ReviewStatus `gorm:"type:varchar(20);not null" json:"fromStatus"`               // 变更前状态
+	ToStatus   ReviewStatus `gorm:"type:varchar(20);not null" json:"toStatus"`                 // 变更后状态
+	ActorID    *string      `gorm:"type:uuid;index" json:"actorId,omitempty"`                  // 操作人ID，为空表示系统操作
+	Note       string       `gorm:"type:text" json:"note"`                                     // 编辑备注
+	CreatedAt  time.Time    `gorm:"not null" json:"createdAt"`                                 // 创建时间
+
+	Review Review `gorm:"foreignKey:ReviewID;references:ID;constraint:OnDelete:CASCADE" json:"-"` // 关联测评
+	Actor  *User  `gorm:"foreignKey:ActorID;references:ID;constraint:OnDelete:SET NULL" json:"-"` // 操作人
 }
 
 // Comment 评论
@@ -135,8 +206,14 @@ type Comment struct {
 	Content   string        `gorm:"type:text;not null" json:"content"`                                  // 评论内容
 	Status    CommentStatus `gorm:"type:varchar(20);default:'PENDING';index" json:"status"`             // 状态
 	Level     int          `gorm:"type:int;default:1;not null" json:"level"`                           // 评论层级，1为顶级评论
+	HelpfulCount    int     `gorm:"default:0" json:"helpfulCount"`                                   // 有帮助数
+	NotHelpfulCount int     `gorm:"default:0" json:"notHelpfulCount"`                                // 没帮助数
+	HelpfulScore    float64 `gorm:"default:0;index" json:"helpfulScore"`                             // 有帮助排序分（Wilson 下界）
+	ModerationNote  string  `gorm:"type:text" json:"-"`                                              // 自动审核命中的规则
+	NotifiedAt      *time.Time `json:"-"`                                                               // 回复和提及通知的发送时间
 	CreatedAt time.Time     `gorm:"not null" json:"createdAt"`                                          // 创建时间
 	UpdatedAt time.Time     `gorm:"not null" json:"updatedAt"`                                          // 更新时间
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`                                                    // 软删除
 
 	Review   Review    `gorm:"foreignKey:ReviewID;references:ID;constraint:OnDelete:CASCADE" json:"-"`   // 关联测评
 	User     User      `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"user"`  // 评论用户
@@ -235,3 +312,14 @@ type MaterialType struct {
 
 	Products []Product `gorm:"foreignKey:MaterialTypeID;constraint:OnDelete:RESTRICT" json:"products,omitempty"` // 关联产品
 }
+
+// ProductPriceChange 产品价格变动记录
+type ProductPriceChange struct {
+	ID        string    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"` // 记录ID
+	ProductID uint      `gorm:"not null;index" json:"productId"`                           // 产品ID
+	OldPrice  float64   `gorm:"not null" json:"oldPrice"`                                  // 原价格
+	NewPrice  float64   `gorm:"not null" json:"newPrice"`                                  // 新价格
+	CreatedAt time.Time `gorm:"not null;index" json:"createdAt"`                           // 变动时间
+
+	Product *Product `gorm:"foreignKey:ProductID;references:ID;constraint:OnDelete:CASCADE" json:"-"` // 关联产品
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// ReactionTargetType 反馈对象类型
+type ReactionTargetType string
+
+const (
+	ReactionTargetReview  ReactionTargetType = "review"  // 测评
+	ReactionTargetComment ReactionTargetType = "comment" // 评论
+)
+
+// ReactionType 反馈类型
+type ReactionType string
+
+const (
+	ReactionHelpful    ReactionType = "HELPFUL"     // 有帮助
+	ReactionNotHelpful ReactionType = "NOT_HELPFUL" // 没帮助
+)
+
+// IsValid 检查反馈类型是否有效
+func (t ReactionType) IsValid() bool {
+	return t == ReactionHelpful || t == ReactionNotHelpful
+}
+
+// Reaction 读者对测评或评论的反馈，每个用户对同一对象只保留一条
+type Reaction struct {
+	ID         string             `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`                                             // 反馈ID
+	UserID     string             `gorm:"type:uuid;not null;uniqueIndex:idx_reaction_target" json:"userId"`                                      // 用户ID
+	TargetType ReactionTargetType `gorm:"type:varchar(20);not null;uniqueIndex:idx_reaction_target;index:idx_reaction_lookup" json:"targetType"` // 对象类型
+	TargetID   string             `gorm:"type:uuid;not null;uniqueIndex:idx_reaction_target;index:idx_reaction_lookup" json:"targetId"`          // 对象ID
+	Type       ReactionType       `gorm:"type:varchar(20);not null" json:"type"`                                                                 // 反馈类型
+	CreatedAt  time.Time          `gorm:"not null" json:"createdAt"`                                                                             // 创建时间
+	UpdatedAt  time.Time          `gorm:"not null" json:"updatedAt"`                                                                             // 更新时间
+
+	User User `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"-"` // 关联用户
+}
@@ -0,0 +1,76 @@
+package model
+
+import "time"
+
+// ReportTargetType 举报对象类型
+type ReportTargetType string
+
+const (
+	ReportTargetReview  ReportTargetType = "review"  // 测评
+	ReportTargetComment ReportTargetType = "comment" // 评论
+)
+
+// ReportReason 举报原因
+type ReportReason string
+
+const (
+	ReportReasonSpam           ReportReason = "SPAM"           // 垃圾广告
+	ReportReasonHarassment     ReportReason = "HARASSMENT"     // 辱骂骚扰
+	ReportReasonHate           ReportReason = "HATE"           // 仇恨歧视
+	ReportReasonIllegal        ReportReason = "ILLEGAL"        // 违法违规
+	ReportReasonPrivacy        ReportReason = "PRIVACY"        // 泄露隐私
+	ReportReasonMisinformation ReportReason = "MISINFORMATION" // 不实信息
+	ReportReasonOther          ReportReason = "OTHER"          // 其他
+)
+
+// IsValid 检查举报原因是否有效
+func (r ReportReason) IsValid() bool {
+	switch r {
+	case ReportReasonSpam, ReportReasonHarassment, ReportReasonHate, ReportReasonIllegal,
+		ReportReasonPrivacy, ReportReasonMisinformation, ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+// ReportStatus 举报处理状态
+type ReportStatus string
+
+const (
+	ReportStatusPending   ReportStatus = "PENDING"   // 待处理
+	ReportStatusResolved  ReportStatus = "RESOLVED"  // 举报成立，内容已下架
+	ReportStatusDismissed ReportStatus = "DISMISSED" // 举报不成立
+)
+
+// ReportCase 同一对象的举报汇总为一个待处理事项，处理后新的举报会开启新的事项
+type ReportCase struct {
+	ID           string           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`                // 事项ID
+	TargetType   ReportTargetType `gorm:"type:varchar(20);not null;index:idx_report_case_target" json:"targetType"` // 对象类型
+	TargetID     string           `gorm:"type:uuid;not null;index:idx_report_case_target" json:"targetId"`          // 对象ID
+	Status       ReportStatus     `gorm:"type:varchar(20);not null;default:'PENDING';index" json:"status"`          // 处理状态
+	ReportCount  int              `gorm:"not null;default:0;index" json:"reportCount"`                              // 举报人数
+	Hidden       bool             `gorm:"not null;default:false" json:"hidden"`                                     // 是否因举报自动隐藏
+	HiddenStatus string           `gorm:"type:varchar(20)" json:"-"`                                                // 隐藏前的对象状态，驳回时恢复
+	Resolution   string           `gorm:"type:text" json:"resolution"`                                              // 处理说明，反馈给举报人
+	HandledBy    *string          `gorm:"type:uuid" json:"handledBy,omitempty"`                                     // 处理人ID
+	HandledAt    *time.Time       `json:"handledAt,omitempty"`                                                      // 处理时间
+	CreatedAt    time.Time        `gorm:"not null" json:"createdAt"`                                                // 创建时间
+	UpdatedAt    time.Time        `gorm:"not null" json:"updatedAt"`                                                // 更新时间
+
+	Reports []Report `gorm:"foreignKey:CaseID;references:ID;constraint:OnDelete:CASCADE" json:"reports,omitempty"` // 举报记录
+	Handler *User    `gorm:"foreignKey:HandledBy;references:ID;constraint:OnDelete:SET NULL" json:"-"`             // 处理人
+}
+
+// Report 用户举报记录，每个用户对同一对象只能举报一次
+type Report struct {
+	ID          string           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`                          // 举报ID
+	CaseID      string           `gorm:"type:uuid;not null;index" json:"caseId"`                                             // 所属事项ID
+	ReporterID  string           `gorm:"type:uuid;not null;uniqueIndex:idx_report_reporter_target" json:"reporterId"`        // 举报人ID
+	TargetType  ReportTargetType `gorm:"type:varchar(20);not null;uniqueIndex:idx_report_reporter_target" json:"targetType"` // 对象类型
+	TargetID    string           `gorm:"type:uuid;not null;uniqueIndex:idx_report_reporter_target" json:"targetId"`          // 对象ID
+	Reason      ReportReason     `gorm:"type:varchar(20);not null" json:"reason"`                                            // 举报原因
+	Description string           `gorm:"type:varchar(500)" json:"description"`                                               // 补充说明
+	CreatedAt   time.Time        `gorm:"not null" json:"createdAt"`                                                          // 举报时间
+
+	Reporter User `gorm:"foreignKey:ReporterID;references:ID;constraint:OnDelete:CASCADE" json:"-"` // 举报人
+}
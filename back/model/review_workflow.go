@@ -0,0 +1,66 @@
+package model
+
+// ReviewActor 测评流程中的参与方
+type ReviewActor string
+
+const (
+	ReviewActorAuthor   ReviewActor = "author"   // 作者，负责撰写和提交
+	ReviewActorReviewer ReviewActor = "reviewer" // 审核人，负责审核和发布
+)
+
+// reviewTransitions 测评状态机：当前状态 -> 目标状态 -> 可执行的参与方
+var reviewTransitions = map[ReviewStatus]map[ReviewStatus]ReviewActor{
+	ReviewStatusDraft: {
+		ReviewStatusInReview: ReviewActorAuthor,
+	},
+	ReviewStatusInReview: {
+		ReviewStatusDraft:            ReviewActorAuthor,
+		ReviewStatusChangesRequested: ReviewActorReviewer,
+		ReviewStatusScheduled:        ReviewActorReviewer,
+		ReviewStatusPublished:        ReviewActorReviewer,
+		ReviewStatusRejected:         ReviewActorReviewer,
+	},
+	ReviewStatusChangesRequested: {
+		ReviewStatusDraft:    ReviewActorAuthor,
+		ReviewStatusInReview: ReviewActorAuthor,
+	},
+	ReviewStatusScheduled: {
+		ReviewStatusInReview:  ReviewActorReviewer,
+		ReviewStatusPublished: ReviewActorReviewer,
+	},
+	ReviewStatusPublished: {
+		ReviewStatusArchived: ReviewActorReviewer,
+	},
+	ReviewStatusArchived: {
+		ReviewStatusDraft:     ReviewActorAuthor,
+		ReviewStatusPublished: ReviewActorReviewer,
+	},
+	ReviewStatusRejected: {
+		ReviewStatusDraft: ReviewActorAuthor,
+	},
+}
+
+// communityEditableStatuses 社区投稿作者可编辑的状态
+var communityEditableStatuses = map[ReviewStatus]bool{
+	ReviewStatusDraft:            true,
+	ReviewStatusInReview:         true,
+	ReviewStatusChangesRequested: true,
+	ReviewStatusRejected:         true,
+}
+
+// IsValid 是否为有效的测评状态
+func (s ReviewStatus) IsValid() bool {
+	_, ok := reviewTransitions[s]
+	return ok
+}
+
+// TransitionActor 返回从当前状态变更到目标状态所需的参与方，不允许的变更返回 false
+func (s ReviewStatus) TransitionActor(to ReviewStatus) (ReviewActor, bool) {
+	actor, ok := reviewTransitions[s][to]
+	return actor, ok
+}
+
+// EditableByCommunityAuthor 普通用户是否可以在当前状态下编辑自己的投稿
+func (s ReviewStatus) EditableByCommunityAuthor() bool {
+	return communityEditableStatuses[s]
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// SlugEntityType 使用 slug 访问的实体类型
+type SlugEntityType string
+
+const (
+	SlugEntityReview  SlugEntityType = "review"  // 测评
+	SlugEntityProduct SlugEntityType = "product" // 产品
+	SlugEntityBrand   SlugEntityType = "brand"   // 品牌
+)
+
+// SlugHistory 实体曾经使用过的 slug，旧链接据此重定向到当前 slug
+type SlugHistory struct {
+	ID         string         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`                // 记录ID
+	EntityType SlugEntityType `gorm:"type:varchar(20);not null;uniqueIndex:idx_slug_history" json:"entityType"` // 实体类型
+	Slug       string         `gorm:"type:varchar(255);not null;uniqueIndex:idx_slug_history" json:"slug"`      // 旧 slug
+	EntityID   string         `gorm:"type:varchar(36);not null;index" json:"entityId"`                          // 实体ID，产品ID为数字的字符串形式
+	CreatedAt  time.Time      `gorm:"not null" json:"createdAt"`                                                // 变更时间
+}
@@ -46,10 +46,11 @@ type File struct {
 	Height      *int       `gorm:"" json:"height,omitempty"`                         // 图片高度
 	Duration    *int       `gorm:"" json:"duration,omitempty"`                       // 视频/音频时长（秒）
 	FolderID    string    `gorm:"type:uuid;index" json:"folderId,omitempty"`       // 所属文件夹ID
-	MD5         string     `gorm:"type:varchar(32);not null;uniqueIndex" json:"md5"` // 文件MD5
+	MD5         string     `gorm:"type:varchar(32);not null;uniqueIndex:idx_files_md5_active,where:deleted_at IS NULL" json:"md5"` // 文件MD5，回收站中的文件不占用唯一索引
 	UserID      string     `gorm:"type:uuid;not null;index" json:"userId"`          // 上传用户ID
 	CreatedAt   time.Time  `gorm:"not null" json:"createdAt"`                       // 创建时间
 	UpdatedAt   time.Time  `gorm:"not null" json:"updatedAt"`                       // 更新时间
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`                               // 软删除
 
 	// 关联
 	Folder    Folder    `gorm:"foreignKey:FolderID" json:"folder,omitempty"`       // 所属文件夹
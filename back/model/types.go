@@ -17,15 +17,37 @@ const (
 	UserStatusActive   UserStatus = "ACTIVE"   // 正常
 	UserStatusBlocked  UserStatus = "BLOCKED"  // 已封禁
 	UserStatusInactive UserStatus = "INACTIVE" // 未激活
+	UserStatusDeleted  UserStatus = "DELETED"  // 已注销，个人信息已匿名化
+)
+
+// HiddenUserStatuses 不公开展示的用户状态
+var HiddenUserStatuses = []UserStatus{UserStatusBlocked, UserStatusDeleted}
+
+// IsHidden 用户是否不再公开展示，已封禁和已注销的用户不出现在搜索、主页和提及中
+func (s UserStatus) IsHidden() bool {
+	return s == UserStatusBlocked || s == UserStatusDeleted
+}
+
+// TrustLevel 用户信任等级，决定评论是否需要人工审核
+type TrustLevel string
+
+const (
+	TrustLevelNew        TrustLevel = "NEW"        // 新用户，评论需人工审核
+	TrustLevelTrusted    TrustLevel = "TRUSTED"    // 可信用户，未命中审核规则的评论自动通过
+	TrustLevelRestricted TrustLevel = "RESTRICTED" // 受限用户，所有评论均需人工审核
 )
 
 // ReviewStatus 测评状态
 type ReviewStatus string
 
 const (
-	ReviewStatusPending   ReviewStatus = "PENDING"   // 下架
-	ReviewStatusPublished ReviewStatus = "PUBLISHED" // 发布
-	ReviewStatusArchived  ReviewStatus = "ARCHIVED"  // 草稿
+	ReviewStatusDraft            ReviewStatus = "DRAFT"             // 草稿
+	ReviewStatusInReview         ReviewStatus = "IN_REVIEW"         // 审核中
+	ReviewStatusChangesRequested ReviewStatus = "CHANGES_REQUESTED" // 需要修改
+	ReviewStatusScheduled        ReviewStatus = "SCHEDULED"         // 定时发布
+	ReviewStatusPublished        ReviewStatus = "PUBLISHED"         // 已发布
+	ReviewStatusArchived         ReviewStatus = "ARCHIVED"          // 已归档
+	ReviewStatusRejected         ReviewStatus = "REJECTED"          // 已拒绝
 )
 
 // CommentStatus 评论状态
@@ -37,6 +59,15 @@ const (
 	CommentStatusRejected CommentStatus = "REJECTED" // 已拒绝
 )
 
+// IsValid 是否为有效的评论状态
+func (s CommentStatus) IsValid() bool {
+	switch s {
+	case CommentStatusPending, CommentStatusApproved, CommentStatusRejected:
+		return true
+	}
+	return false
+}
+
 // StimulationLevel 刺激度
 type StimulationLevel string
 
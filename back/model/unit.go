@@ -0,0 +1,110 @@
+package model
+
+import "math"
+
+// LengthUnit 长度单位
+type LengthUnit string
+
+const (
+	LengthUnitMM LengthUnit = "mm" // 毫米
+	LengthUnitCM LengthUnit = "cm" // 厘米
+	LengthUnitIN LengthUnit = "in" // 英寸
+)
+
+// WeightUnit 重量单位
+type WeightUnit string
+
+const (
+	WeightUnitG  WeightUnit = "g"  // 克
+	WeightUnitKG WeightUnit = "kg" // 千克
+	WeightUnitOZ WeightUnit = "oz" // 盎司
+	WeightUnitLB WeightUnit = "lb" // 磅
+)
+
+// UnitSystem 单位制
+type UnitSystem string
+
+const (
+	UnitSystemMetric   UnitSystem = "metric"   // 公制（cm / g）
+	UnitSystemImperial UnitSystem = "imperial" // 英制（in / oz）
+)
+
+// 尺寸与重量的合理范围（以 cm / g 计）
+const (
+	MinDimensionCM = 0.1
+	MaxDimensionCM = 100
+	MinWeightG     = 1
+	MaxWeightG     = 20000
+)
+
+// lengthToCM 各长度单位换算为厘米的系数
+var lengthToCM = map[LengthUnit]float64{
+	LengthUnitMM: 0.1,
+	LengthUnitCM: 1,
+	LengthUnitIN: 2.54,
+}
+
+// weightToG 各重量单位换算为克的系数
+var weightToG = map[WeightUnit]float64{
+	WeightUnitG:  1,
+	WeightUnitKG: 1000,
+	WeightUnitOZ: 28.349523125,
+	WeightUnitLB: 453.59237,
+}
+
+// IsValid 是否为支持的长度单位
+func (u LengthUnit) IsValid() bool {
+	_, ok := lengthToCM[u]
+	return ok
+}
+
+// IsValid 是否为支持的重量单位
+func (u WeightUnit) IsValid() bool {
+	_, ok := weightToG[u]
+	return ok
+}
+
+// ParseUnitSystem 解析单位制，无法识别时返回空值
+func ParseUnitSystem(s string) UnitSystem {
+	switch UnitSystem(s) {
+	case UnitSystemMetric, UnitSystemImperial:
+		return UnitSystem(s)
+	}
+	return ""
+}
+
+// LengthUnit 单位制对应的长度单位
+func (s UnitSystem) LengthUnit() LengthUnit {
+	if s == UnitSystemImperial {
+		return LengthUnitIN
+	}
+	return LengthUnitCM
+}
+
+// WeightUnit 单位制对应的重量单位
+func (s UnitSystem) WeightUnit() WeightUnit {
+	if s == UnitSystemImperial {
+		return WeightUnitOZ
+	}
+	return WeightUnitG
+}
+
+// ConvertLength 长度单位换算，结果保留两位小数
+func ConvertLength(v float64, from, to LengthUnit) float64 {
+	if from == to || !from.IsValid() || !to.IsValid() {
+		return v
+	}
+	return round2(v * lengthToCM[from] / lengthToCM[to])
+}
+
+// ConvertWeight 重量单位换算，结果保留两位小数
+func ConvertWeight(v float64, from, to WeightUnit) float64 {
+	if from == to || !from.IsValid() || !to.IsValid() {
+		return v
+	}
+	return round2(v * weightToG[from] / weightToG[to])
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
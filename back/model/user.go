@@ -17,12 +17,15 @@ type User struct {
 	IsEmailVerified   bool      `gorm:"default:false;index" json:"isEmailVerified"`                            // 邮箱是否已验证
 	Role              UserRole  `gorm:"type:varchar(20);default:'USER';index" json:"role"`                     // 用户角色
 	Avatar            string   `gorm:"type:varchar(255)" json:"avatar,omitempty"`                             // 用户头像
+	AvatarSizes       AvatarSizes `gorm:"type:jsonb;serializer:json" json:"avatarSizes,omitempty"`            // 上传头像生成的各尺寸地址
 	Bio               string   `gorm:"type:text" json:"bio,omitempty"`                                        // 用户简介
 	LastLoginAt      *time.Time `gorm:"index" json:"lastLoginAt,omitempty"`                                    // 最后登录时间
 	CreatedAt        time.Time  `gorm:"not null" json:"createdAt"`                                            // 创建时间
 	UpdatedAt        time.Time  `gorm:"not null" json:"updatedAt"`                                            // 更新时间
 	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`                                                    // 软删除
 	Status           UserStatus `gorm:"type:varchar(20);default:'active'" json:"status"`                       // 用户状态
+	TrustLevel       TrustLevel `gorm:"type:varchar(20);default:'NEW';index" json:"trustLevel"`                // 信任等级
+	Privacy          ProfilePrivacy `gorm:"type:jsonb;serializer:json" json:"privacy"`                          // 公开主页各字段的可见性，未设置的字段使用默认值
 
 	// 关联
 	Products  []Product       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"products,omitempty"`   // 用户的产品
@@ -32,10 +35,74 @@ type User struct {
 	Favorites []UserFavorite  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"favorites,omitempty"` // 用户的收藏
 }
 
+// AvatarSizes 头像边长（像素）到访问地址的映射
+type AvatarSizes map[int]string
+
+// ProfileField 公开主页中可设置可见性的字段，名称和头像始终公开
+type ProfileField string
+
+const (
+	ProfileFieldBio       ProfileField = "bio"       // 个人简介
+	ProfileFieldStats     ProfileField = "stats"     // 测评数、评论数和关注者数
+	ProfileFieldJoinDate  ProfileField = "joinDate"  // 注册时间
+	ProfileFieldFavorites ProfileField = "favorites" // 收藏列表
+)
+
+// ProfileFields 所有可设置可见性的字段
+var ProfileFields = []ProfileField{
+	ProfileFieldBio,
+	ProfileFieldStats,
+	ProfileFieldJoinDate,
+	ProfileFieldFavorites,
+}
+
+// IsValid 检查字段是否有效
+func (f ProfileField) IsValid() bool {
+	for _, v := range ProfileFields {
+		if f == v {
+			return true
+		}
+	}
+	return false
+}
+
+// ProfileVisibility 字段可见性
+type ProfileVisibility string
+
+const (
+	ProfileVisibilityPublic  ProfileVisibility = "PUBLIC"  // 所有人可见
+	ProfileVisibilityPrivate ProfileVisibility = "PRIVATE" // 仅自己和管理员可见
+)
+
+// IsValid 检查可见性是否有效
+func (v ProfileVisibility) IsValid() bool {
+	return v == ProfileVisibilityPublic || v == ProfileVisibilityPrivate
+}
+
+// DefaultProfileVisibility 用户未设置时各字段的可见性，收藏默认不公开
+var DefaultProfileVisibility = map[ProfileField]ProfileVisibility{
+	ProfileFieldBio:       ProfileVisibilityPublic,
+	ProfileFieldStats:     ProfileVisibilityPublic,
+	ProfileFieldJoinDate:  ProfileVisibilityPublic,
+	ProfileFieldFavorites: ProfileVisibilityPrivate,
+}
+
+// ProfilePrivacy 公开主页各字段的可见性
+type ProfilePrivacy map[ProfileField]ProfileVisibility
+
+// IsPublic 字段是否公开，未设置时使用默认值
+func (p ProfilePrivacy) IsPublic(f ProfileField) bool {
+	v, ok := p[f]
+	if !ok || !v.IsValid() {
+		v = DefaultProfileVisibility[f]
+	}
+	return v == ProfileVisibilityPublic
+}
+
 // UserFavorite 用户收藏
 type UserFavorite struct {
 	UserID    string    `gorm:"type:uuid;not null;primaryKey" json:"userId"`
-	ProductID string    `gorm:"type:uuid;not null;primaryKey" json:"productId"`
+	ProductID uint      `gorm:"not null;primaryKey" json:"productId"`
 	CreatedAt time.Time `gorm:"not null" json:"createdAt"`
 
 	User    User    `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
@@ -18,6 +18,9 @@ var (
 	// 产品相关错误
 	ErrInvalidPrice      = errors.New("无效的价格")
 	ErrInvalidDimension  = errors.New("无效的尺寸")
+	ErrInvalidUnit       = errors.New("无效的单位")
+	ErrDimensionRange    = errors.New("尺寸超出合理范围")
+	ErrWeightRange       = errors.New("重量超出合理范围")
 	ErrInvalidRating     = errors.New("无效的评分")
 	ErrInvalidStatus     = errors.New("无效的状态")
 	ErrInvalidSortOrder  = errors.New("无效的排序")
@@ -90,9 +93,8 @@ func (p *Product) Validate() error {
 	}
 
 	// 验证尺寸
-	if p.Height <= 0 || p.Width <= 0 || p.Length <= 0 || 
-	   p.ChannelLength <= 0 || p.TotalLength <= 0 || p.Weight <= 0 {
-		return ErrInvalidDimension
+	if err := p.ValidateDimensions(); err != nil {
+		return err
 	}
 
 	// 验证URL（如果有）
@@ -106,6 +108,125 @@ func (p *Product) Validate() error {
 	return nil
 }
 
+// ValidateDimensions 验证产品尺寸、重量及其单位
+func (p *Product) ValidateDimensions() error {
+	return p.ValidateDimensionFields(map[string]bool{
+		"height": true, "width": true, "length": true, "channelLength": true, "totalLength": true,
+		"weight": true, "lengthUnit": true, "weightUnit": true,
+	})
+}
+
+// ValidateDimensionFields 只校验指定的尺寸字段（JSON 字段名），单位变更时校验该单位下的全部字段。
+// 部分更新时使用，避免历史数据中超出范围的尺寸导致无关字段也无法修改
+func (p *Product) ValidateDimensionFields(fields map[string]bool) error {
+	// 验证单位
+	if fields["lengthUnit"] && !p.LengthUnit.IsValid() {
+		return ErrInvalidUnit
+	}
+	if fields["weightUnit"] && !p.WeightUnit.IsValid() {
+		return ErrInvalidUnit
+	}
+
+	// 换算为 cm / g 后验证范围
+	lengths := []struct {
+		field string
+		value float64
+	}{
+		{"height", p.Height},
+		{"width", p.Width},
+		{"length", p.Length},
+		{"channelLength", p.ChannelLength},
+		{"totalLength", p.TotalLength},
+	}
+	for _, d := range lengths {
+		if !fields[d.field] && !fields["lengthUnit"] {
+			continue
+		}
+		if d.value <= 0 {
+			return ErrInvalidDimension
+		}
+		if !p.LengthUnit.IsValid() {
+			return ErrInvalidUnit
+		}
+		cm := ConvertLength(d.value, p.LengthUnit, LengthUnitCM)
+		if cm < MinDimensionCM || cm > MaxDimensionCM {
+			return ErrDimensionRange
+		}
+	}
+	if fields["weight"] || fields["weightUnit"] {
+		if p.Weight <= 0 {
+			return ErrInvalidDimension
+		}
+		if !p.WeightUnit.IsValid() {
+			return ErrInvalidUnit
+		}
+		g := ConvertWeight(p.Weight, p.WeightUnit, WeightUnitG)
+		if g < MinWeightG || g > MaxWeightG {
+			return ErrWeightRange
+		}
+	}
+
+	// 通道长度不能超过总长度
+	if (fields["channelLength"] || fields["totalLength"]) && p.ChannelLength > p.TotalLength {
+		return ErrInvalidDimension
+	}
+
+	return nil
+}
+
+// ValidateProductVariant 验证产品版本
+func (v *ProductVariant) Validate() error {
+	// 验证必填字段
+	if v.Name == "" || v.ProductID == 0 {
+		return ErrRequired
+	}
+
+	// 验证价格
+	if v.Price != nil && *v.Price < 0 {
+		return ErrInvalidPrice
+	}
+
+	// 验证尺寸（仅校验有覆盖值的字段）
+	for _, d := range []*float64{v.Height, v.Width, v.Length, v.ChannelLength, v.TotalLength, v.Weight} {
+		if d != nil && *d <= 0 {
+			return ErrInvalidDimension
+		}
+	}
+
+	// 验证排序顺序
+	if v.SortOrder < 0 {
+		return ErrInvalidSortOrder
+	}
+
+	return nil
+}
+
+// ValidateDimensions 将版本覆盖的尺寸代入所属产品后校验，版本尺寸与重量沿用产品的单位
+func (v *ProductVariant) ValidateDimensions(product *Product) error {
+	merged := *product
+	fields := make(map[string]bool)
+	overrides := []struct {
+		field  string
+		value  *float64
+		target *float64
+	}{
+		{"height", v.Height, &merged.Height},
+		{"width", v.Width, &merged.Width},
+		{"length", v.Length, &merged.Length},
+		{"channelLength", v.ChannelLength, &merged.ChannelLength},
+		{"totalLength", v.TotalLength, &merged.TotalLength},
+		{"weight", v.Weight, &merged.Weight},
+	}
+	for _, o := range overrides {
+		if o.value == nil {
+			continue
+		}
+		*o.target = *o.value
+		fields[o.field] = true
+	}
+	return merged.ValidateDimensionFields(fields)
+}
+
 // ValidateProductRating 验证产品评分
 func (r *Rating) Validate() error {
 	// 验证评分范围
@@ -129,9 +250,7 @@ func (r *Review) Validate() error {
 	}
 
 	// 验证状态
-	switch r.Status {
-	case ReviewStatusPending, ReviewStatusPublished, ReviewStatusArchived:
-	default:
+	if !r.Status.IsValid() {
 		return ErrInvalidStatus
 	}
 
@@ -146,9 +265,7 @@ func (c *Comment) Validate() error {
 	}
 
 	// 验证状态
-	switch c.Status {
-	case CommentStatusPending, CommentStatusApproved, CommentStatusRejected:
-	default:
+	if !c.Status.IsValid() {
 		return ErrInvalidStatus
 	}
 
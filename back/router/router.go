@@ -28,11 +28,21 @@ func SetupRouter(
 	searchHandler *handler.SearchHandler,
 	storageHandler *handler.StorageHandler,
 	uploadHandler *handler.UploadHandler,
+	trashHandler *handler.TrashHandler,
+	dimensionHandler *handler.ScoreDimensionHandler,
+	reactionHandler *handler.ReactionHandler,
+	reportHandler *handler.ReportHandler,
+	notificationHandler *handler.NotificationHandler,
+	realtimeHandler *handler.RealtimeHandler,
+	followHandler *handler.FollowHandler,
+	collectionHandler *handler.CollectionHandler,
+	accountHandler *handler.AccountHandler,
 	authService *service.AuthService,
 	jwtSecret string,
 	cfg *config.Config,
 ) *gin.Engine {
-	r := gin.Default()
+	r := gin.New()
+	r.Use(middleware.LoggerMiddleware(), gin.Recovery())
 
 	// 添加请求日志中间件
 	r.Use(func(c *gin.Context) {
@@ -76,6 +86,8 @@ func SetupRouter(
 			products.GET("/:id", productHandler.GetProduct)   // ID获取产品详情
 			products.GET("/slug/:slug", productHandler.GetProductBySlug) // Slug获取产品详情
 			products.GET("/:id/reviews", reviewHandler.ListProductReviews) // 获取产品测评
+			products.GET("/:id/variants", productHandler.ListVariants)     // 获取产品版本
+			products.GET("/:id/radar", dimensionHandler.GetProductRadar)   // 获取维度评分雷达图
 
 		}
 
@@ -105,20 +117,39 @@ func SetupRouter(
 		{
 			utilityTypes.GET("", utilityTypeHandler.ListTypes)             // 获取器具类型列表
 			utilityTypes.GET("/:id", utilityTypeHandler.GetType)           // 获取器具类型详情
+			utilityTypes.GET("/:id/dimensions", dimensionHandler.ListDimensions) // 获取评分维度
 		}
 
 		// 公开的测评相关路由
 		reviews := api.Group("/reviews")
 		{
-			reviews.GET("", reviewHandler.ListReviews)                    // 获取测评列表
-			reviews.GET("/:id", reviewHandler.GetReview)                 // 获取单个测评
-			reviews.GET("/slug/:slug", reviewHandler.GetReviewBySlug)    // 获取单个测评
+			reviews.GET("", authMiddleware.OptionalAuth(), reviewHandler.ListReviews)                 // 获取测评列表
+			reviews.GET("/:id", authMiddleware.OptionalAuth(), reviewHandler.GetReview)              // 获取单个测评（未发布的仅作者和编辑可见）
+			reviews.GET("/slug/:slug", authMiddleware.OptionalAuth(), reviewHandler.GetReviewBySlug) // 获取单个测评（未发布的仅作者和编辑可见）
 			reviews.GET("/slug/:slug/product", reviewHandler.GetProductByReviewSlug)    // 获取产品详情
+			reviews.GET("/:id/related", reviewHandler.GetRelatedReviews)  // 获取相关测评
+		}
+
+		// 公开的作者主页
+		authors := api.Group("/users")
+		{
+			authors.GET("/:id/reviews", authMiddleware.OptionalAuth(), reviewHandler.GetAuthorPage)      // 获取作者主页
+			authors.GET("/:id/profile", authMiddleware.OptionalAuth(), userHandler.GetPublicProfile)       // 获取用户公开主页
+			authors.GET("/:id/favorites", authMiddleware.OptionalAuth(), userHandler.ListPublicFavorites) // 获取用户公开的收藏列表
+			authors.GET("/:id/collections", authMiddleware.OptionalAuth(), collectionHandler.ListUserCollections) // 获取用户的公开收藏夹
+		}
+
+		// 公开的收藏夹
+		collections := api.Group("/collections")
+		{
+			collections.GET("/:id", authMiddleware.OptionalAuth(), collectionHandler.GetCollection) // 获取收藏夹详情
+			collections.GET("/shared/:token", collectionHandler.GetSharedCollection)               // 通过分享链接获取收藏夹
 		}
        //公开的评论
 		comments := api.Group("/comments")
 		{
-			comments.GET("", commentHandler.ListComments)                    // 获取评论列表
+			comments.GET("", authMiddleware.OptionalAuth(), commentHandler.ListComments) // 获取评论列表
+			comments.GET("/:id/replies", authMiddleware.OptionalAuth(), commentHandler.ListReplies) // 加载更多回复
 		}
 		// 公开的品牌相关路由
 		brands := api.Group("/brands")
@@ -140,6 +171,9 @@ func SetupRouter(
 
 	}
 
+	// 实时推送，单独认证以支持通过查询参数携带令牌
+	api.GET("/events", authMiddleware.RequireStreamAuth(), realtimeHandler.Stream)
+
 	// 需要认证的路由
 	authorized := api.Group("")
 	authorized.Use(authMiddleware.RequireAuth())
@@ -149,11 +183,41 @@ func SetupRouter(
 		{
 			user.GET("/profile", userHandler.GetCurrentUser)         // 获取个人信息
 			user.PUT("/profile", userHandler.UpdateCurrentUser)      // 更新个人信息
+			user.POST("/avatar", uploadHandler.UploadAvatar)         // 上传头像
+			user.GET("/privacy", userHandler.GetProfilePrivacy)      // 获取主页可见性设置
+			user.PUT("/privacy", userHandler.UpdateProfilePrivacy)   // 更新主页可见性设置
 			user.POST("/change-password", authHandler.ChangePassword) // 修改密码
 			user.GET("/me/reviews", userHandler.ListCurrentUserReviews) // 获取当前用户测评
 			user.GET("/me/favorites", userHandler.ListCurrentUserFavorites) // 获取收藏列表
 			user.POST("/me/favorites/:productId", userHandler.AddToFavorites) // 添加收藏
 			user.DELETE("/me/favorites/:productId", userHandler.RemoveFromFavorites) // 取消收藏
+			user.GET("/me/reports", reportHandler.ListMyReports) // 获取我的举报
+			user.GET("/me/collections", collectionHandler.ListMyCollections) // 获取我的收藏夹
+			user.POST("/me/collections", collectionHandler.CreateCollection) // 创建收藏夹
+			user.PUT("/me/collections/:id", collectionHandler.UpdateCollection) // 更新收藏夹
+			user.DELETE("/me/collections/:id", collectionHandler.DeleteCollection) // 删除收藏夹
+			user.POST("/me/collections/:id/items", collectionHandler.AddItem) // 向收藏夹添加产品
+			user.PUT("/me/collections/:id/items/:productId", collectionHandler.UpdateItem) // 更新收藏夹产品备注
+			user.DELETE("/me/collections/:id/items/:productId", collectionHandler.RemoveItem) // 从收藏夹移除产品
+			user.PUT("/me/collections/:id/order", collectionHandler.ReorderItems) // 调整收藏夹产品顺序
+			user.POST("/me/collections/:id/share", collectionHandler.CreateShareLink) // 生成分享链接
+			user.DELETE("/me/collections/:id/share", collectionHandler.RevokeShareLink) // 关闭分享链接
+			user.GET("/me/follows", followHandler.ListFollows) // 获取关注列表
+			user.GET("/me/exports", accountHandler.ListExports) // 获取个人数据导出任务
+			user.POST("/me/exports", accountHandler.RequestExport) // 申请导出个人数据
+			user.GET("/me/exports/:id/download", accountHandler.DownloadExport) // 下载导出文件
+			user.GET("/me/deletion", accountHandler.GetDeletion) // 获取注销申请
+			user.POST("/me/deletion", accountHandler.RequestDeletion) // 申请注销账号
+			user.DELETE("/me/deletion", accountHandler.CancelDeletion) // 撤销注销申请
+			user.PUT("/me/follows/:targetType/:targetId", followHandler.Follow) // 关注品牌、作者或产品
+			user.DELETE("/me/follows/:targetType/:targetId", followHandler.Unfollow) // 取消关注
+			user.GET("/feed", followHandler.GetFeed) // 获取个性化动态
+			user.GET("/notifications", notificationHandler.ListNotifications)           // 获取通知列表
+			user.GET("/notifications/settings", notificationHandler.GetSettings)        // 获取通知设置
+			user.PUT("/notifications/settings", notificationHandler.UpdateSettings)     // 更新通知设置
+			user.GET("/notifications/unread-count", notificationHandler.GetUnreadCount) // 获取未读通知数
+			user.PUT("/notifications/read-all", notificationHandler.MarkAllRead)        // 全部标记已读
+			user.PUT("/notifications/:id/read", notificationHandler.MarkRead)           // 标记通知已读
 		}
 
 		// 用户管理（需要管理员权限）
@@ -164,6 +228,7 @@ func SetupRouter(
 			users.PUT("/:id", authMiddleware.RequireAdmin(), userHandler.UpdateUser)       // 更新用户
 			users.DELETE("/:id", authMiddleware.RequireAdmin(), userHandler.DeleteUser)    // 删除用户
 			users.POST("/:id/reset-password", authMiddleware.RequireAdmin(), authHandler.ResetPassword) // 重置密码
+			users.PUT("/:id/trust-level", authMiddleware.RequireAdmin(), userHandler.UpdateUserTrustLevel) // 更新评论信任等级
 		}
 
 		// 产品管理
@@ -172,13 +237,28 @@ func SetupRouter(
 			products.POST("", authMiddleware.RequireAdmin(),  productHandler.CreateProduct)     // 创建产品
 			products.PUT("/:id", authMiddleware.RequireAdmin(), productHandler.UpdateProduct)  // 更新产品
 			products.DELETE("/:id", authMiddleware.RequireAdmin(), productHandler.DeleteProduct) // 删除产品
+			products.POST("/:id/variants", authMiddleware.RequireAdmin(), productHandler.CreateVariant)              // 创建产品版本
+			products.PUT("/:id/variants/:variantId", authMiddleware.RequireAdmin(), productHandler.UpdateVariant)    // 更新产品版本
+			products.DELETE("/:id/variants/:variantId", authMiddleware.RequireAdmin(), productHandler.DeleteVariant) // 删除产品版本
 		}
 		// 测评管理
 		reviews := authorized.Group("/reviews")
 		{
 			reviews.POST("", authMiddleware.RequireAdmin(),  reviewHandler.CreateReview)        // 创建测评
-			reviews.PUT("/:id", authMiddleware.RequireAdmin(), reviewHandler.UpdateReview)     // 更新测评
+			reviews.PUT("/:id", reviewHandler.UpdateReview)                                     // 更新测评（作者或审核人）
+			reviews.POST("/submissions", reviewHandler.SubmitReview)                           // 用户投稿测评
+			reviews.GET("/moderation", authMiddleware.RequireEditor(), reviewHandler.ListModerationQueue) // 投稿审核队列
 			reviews.DELETE("/:id", authMiddleware.RequireAdmin(), reviewHandler.DeleteReview)  // 删除测评
+			reviews.GET("/:id/revisions", authMiddleware.RequireEditor(), reviewHandler.ListRevisions)        // 获取修订历史
+			reviews.GET("/:id/revisions/diff", authMiddleware.RequireEditor(), reviewHandler.DiffRevisions)   // 比较修订版本
+			reviews.GET("/:id/revisions/:version", authMiddleware.RequireEditor(), reviewHandler.GetRevision) // 获取修订版本详情
+			reviews.POST("/:id/revisions/:version/rollback", authMiddleware.RequireEditor(), reviewHandler.RollbackReview) // 回滚到指定版本
+			reviews.POST("/:id/transition", reviewHandler.TransitionReview)                                  // 变更测评状态（按流程校验权限）
+			reviews.PUT("/:id/reviewer", authMiddleware.RequireAdmin(), reviewHandler.AssignReviewer)        // 指派审核人
+			reviews.GET("/:id/status-logs", authMiddleware.RequireEditor(), reviewHandler.ListStatusLogs)    // 获取状态变更记录
+			reviews.PUT("/:id/reaction", reactionHandler.ReactToReview)                                      // 反馈测评是否有帮助
+			reviews.DELETE("/:id/reaction", reactionHandler.RemoveReviewReaction)                            // 取消测评反馈
+			reviews.POST("/:id/report", reportHandler.ReportReview)                                         // 举报测评
 		}
 
 		// 品牌管理
@@ -187,6 +267,7 @@ func SetupRouter(
 			brands.POST("", authMiddleware.RequireAdmin(),  brandHandler.CreateBrand)        // 创建品牌
 			brands.PUT("/:id", authMiddleware.RequireAdmin(), brandHandler.UpdateBrand)     // 更新品牌
 			brands.DELETE("/:id", authMiddleware.RequireAdmin(), brandHandler.DeleteBrand)  // 删除品牌
+			brands.POST("/:id/logo", authMiddleware.RequireAdmin(), uploadHandler.UploadBrandLogo) // 上传品牌LOGO
 		}
 
 		// 类型相关路由
@@ -196,6 +277,9 @@ func SetupRouter(
 	        utilityType.POST("", authMiddleware.RequireAdmin(),  utilityTypeHandler.CreateType)      // 创建器具类型 
 			utilityType.PUT("/:id",authMiddleware.RequireAdmin(), utilityTypeHandler.UpdateType)   // 更新器具类型
 			utilityType.DELETE("/:id", authMiddleware.RequireAdmin(), utilityTypeHandler.DeleteType) // 删除器具类型
+			utilityType.POST("/:id/dimensions", authMiddleware.RequireAdmin(), dimensionHandler.CreateDimension)                // 创建评分维度
+			utilityType.PUT("/:id/dimensions/:dimensionId", authMiddleware.RequireAdmin(), dimensionHandler.UpdateDimension)    // 更新评分维度
+			utilityType.DELETE("/:id/dimensions/:dimensionId", authMiddleware.RequireAdmin(), dimensionHandler.DeleteDimension) // 删除评分维度
 
 		}
 
@@ -230,6 +314,9 @@ func SetupRouter(
 			comments.GET("/:id", commentHandler.GetComment)                                            // 获取评论详情
 			comments.GET("/all", authMiddleware.RequireAdmin(),commentHandler.ListAllComments)                                              // 获取评论列表
 			comments.PUT("/:id/status", authMiddleware.RequireAdmin(), commentHandler.UpdateCommentStatus) // 更新评论状态
+			comments.PUT("/:id/reaction", reactionHandler.ReactToComment)                                // 反馈评论是否有帮助
+			comments.DELETE("/:id/reaction", reactionHandler.RemoveCommentReaction)                      // 取消评论反馈
+			comments.POST("/:id/report", reportHandler.ReportComment)                                    // 举报评论
 		}
 
 		// 管理员评论路由
@@ -238,7 +325,25 @@ func SetupRouter(
 		{
 			adminComments := admin.Group("/comments")
 			{
-				adminComments.GET("", commentHandler.ListAllComments) // 获取所有评论列表
+				adminComments.GET("", commentHandler.ListAllComments)                  // 获取所有评论列表
+				adminComments.PUT("/status", commentHandler.BulkUpdateCommentStatus) // 批量审核评论
+			}
+
+			// 举报处理
+			reports := admin.Group("/reports")
+			{
+				reports.GET("", reportHandler.ListReportCases)            // 获取举报处理队列
+				reports.GET("/:id", reportHandler.GetReportCase)          // 获取举报详情
+				reports.POST("/:id/resolve", reportHandler.ResolveReport) // 举报成立
+				reports.POST("/:id/dismiss", reportHandler.DismissReport) // 驳回举报
+			}
+
+			// 回收站
+			trash := admin.Group("/trash")
+			{
+				trash.GET("", trashHandler.ListTrash)                        // 获取回收站列表
+				trash.POST("/:type/:id/restore", trashHandler.RestoreTrash) // 恢复记录
+				trash.DELETE("/:type/:id", trashHandler.PurgeTrash)         // 彻底删除记录
 			}
 		}
 
@@ -259,7 +364,7 @@ func SetupRouter(
 		// 搜索路由
 		search := authorized.Group("/search")
 		{
-			search.GET("/users", authMiddleware.RequireAdmin(),  searchHandler.SearchUsers)     // 搜索用户（管理员）
+			search.GET("/users", searchHandler.SearchUsers) // 搜索用户，非管理员只返回公开信息
 		}
 
 		// 文件存储路由
@@ -0,0 +1,703 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"beicun/back/config"
+	"beicun/back/model"
+)
+
+// 注销后用户的显示名称
+const deletedUserName = "已注销用户"
+
+// 打包中断的导出任务超过该时长后重新排队
+const staleExportTimeout = time.Hour
+
+var (
+	ErrExportInProgress  = errors.New("已有正在处理的导出任务")
+	ErrExportTooFrequent = errors.New("导出过于频繁，请稍后再试")
+	ErrExportNotFound    = errors.New("导出任务不存在")
+	ErrExportNotReady    = errors.New("导出文件尚未生成或已过期")
+	ErrInvalidPassword   = errors.New("密码错误")
+	ErrDeletionPending   = errors.New("已有待处理的注销申请")
+	ErrDeletionNotFound  = errors.New("没有待处理的注销申请")
+)
+
+// RequestAccountDeletionRequest 申请注销账号请求
+type RequestAccountDeletionRequest struct {
+	Password string `json:"password" binding:"required"` // 当前密码，用于确认本人操作
+	Reason   string `json:"reason" binding:"max=500"`
+}
+
+// exportReview 导出的测评
+type exportReview struct {
+	ID          string             `json:"id"`
+	Title       string             `json:"title"`
+	Slug        string             `json:"slug"`
+	Status      model.ReviewStatus `json:"status"`
+	ProductID   uint               `json:"productId"`
+	Content     string             `json:"content"`
+	Pros        []string           `json:"pros"`
+	Cons        []string           `json:"cons"`
+	Conclusion  string             `json:"conclusion"`
+	PublishedAt *time.Time         `json:"publishedAt,omitempty"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt"`
+}
+
+// exportComment 导出的评论
+type exportComment struct {
+	ID        string              `json:"id"`
+	ReviewID  string              `json:"reviewId"`
+	ParentID  *string             `json:"parentId,omitempty"`
+	Content   string              `json:"content"`
+	Status    model.CommentStatus `json:"status"`
+	CreatedAt time.Time           `json:"createdAt"`
+}
+
+// exportFavorite 导出的收藏
+type exportFavorite struct {
+	ProductID   uint      `json:"productId"`
+	ProductName string    `json:"productName"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// exportCollection 导出的收藏夹
+type exportCollection struct {
+	ID          string                     `json:"id"`
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Visibility  model.CollectionVisibility `json:"visibility"`
+	Items       []exportCollectionItem     `json:"items"`
+	CreatedAt   time.Time                  `json:"createdAt"`
+}
+
+// exportCollectionItem 导出的收藏夹产品
+type exportCollectionItem struct {
+	ProductID uint      `json:"productId"`
+	Note      string    `json:"note"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// exportFile 导出的上传文件，ArchivePath 为压缩包内的路径，原文件缺失时为空
+type exportFile struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Type        model.FileType `json:"type"`
+	MimeType    string         `json:"mimeType"`
+	Size        int64          `json:"size"`
+	URL         string         `json:"url"`
+	ArchivePath string         `json:"archivePath,omitempty"`
+	CreatedAt   time.Time      `json:"createdAt"`
+}
+
+// AccountService 个人数据导出和账号注销
+type AccountService struct {
+	db           *gorm.DB
+	cfg          *config.Config
+	emailService *EmailService
+	logger       *zap.Logger
+}
+
+func NewAccountService(db *gorm.DB, cfg *config.Config, emailService *EmailService, logger *zap.Logger) *AccountService {
+	return &AccountService{
+		db:           db,
+		cfg:          cfg,
+		emailService: emailService,
+		logger:       logger,
+	}
+}
+
+// RequestExport 申请导出个人数据，由后台任务异步打包
+func (s *AccountService) RequestExport(c *gin.Context, userID string) (*model.DataExport, error) {
+	var last model.DataExport
+	err := s.db.WithContext(c).Where("user_id = ?", userID).Order("created_at DESC").First(&last).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInternal
+	}
+	if err == nil {
+		switch {
+		case last.Status == model.DataExportPending || last.Status == model.DataExportProcessing:
+			return nil, ErrExportInProgress
+		case last.Status != model.DataExportFailed && time.Since(last.CreatedAt) < s.cfg.Account.ExportCooldown:
+			return nil, ErrExportTooFrequent
+		}
+	}
+
+	export := &model.DataExport{
+		UserID: userID,
+		Status: model.DataExportPending,
+	}
+	if err := s.db.WithContext(c).Create(export).Error; err != nil {
+		return nil, ErrInternal
+	}
+	return export, nil
+}
+
+// ListExports 获取最近的导出任务
+func (s *AccountService) ListExports(c *gin.Context, userID string) ([]*model.DataExport, error) {
+	exports := []*model.DataExport{}
+	if err := s.db.WithContext(c).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(10).
+		Find(&exports).Error; err != nil {
+		return nil, ErrInternal
+	}
+	return exports, nil
+}
+
+// GetExportFile 获取可下载的导出任务
+func (s *AccountService) GetExportFile(c *gin.Context, userID, exportID string) (*model.DataExport, error) {
+	var export model.DataExport
+	if err := s.db.WithContext(c).First(&export, "id = ? AND user_id = ?", exportID, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExportNotFound
+		}
+		return nil, ErrInternal
+	}
+	if export.Status != model.DataExportCompleted || export.ExpiresAt == nil || time.Now().After(*export.ExpiresAt) {
+		return nil, ErrExportNotReady
+	}
+	return &export, nil
+}
+
+// RequestDeletion 申请注销账号，冷静期结束后由后台任务执行
+func (s *AccountService) RequestDeletion(c *gin.Context, userID string, req *RequestAccountDeletionRequest) (*model.AccountDeletion, error) {
+	var user model.User
+	if err := s.db.WithContext(c).First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrInternal
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password+user.Salt)); err != nil {
+		return nil, ErrInvalidPassword
+	}
+
+	var count int64
+	if err := s.db.WithContext(c).Model(&model.AccountDeletion{}).
+		Where("user_id = ? AND status = ?", userID, model.AccountDeletionPending).
+		Count(&count).Error; err != nil {
+		return nil, ErrInternal
+	}
+	if count > 0 {
+		return nil, ErrDeletionPending
+	}
+
+	deletion := &model.AccountDeletion{
+		UserID:      userID,
+		Status:      model.AccountDeletionPending,
+		Reason:      req.Reason,
+		ScheduledAt: time.Now().AddDate(0, 0, s.cfg.Account.DeletionGraceDays),
+	}
+	if err := s.db.WithContext(c).Create(deletion).Error; err != nil {
+		return nil, ErrInternal
+	}
+
+	go func() {
+		if err := s.emailService.SendAccountDeletionScheduled(user.Email, user.Name, deletion.ScheduledAt); err != nil {
+			s.logger.Error("发送注销确认邮件失败", zap.String("userID", userID), zap.Error(err))
+		}
+	}()
+	return deletion, nil
+}
+
+// GetDeletion 获取待处理的注销申请
+func (s *AccountService) GetDeletion(c *gin.Context, userID string) (*model.AccountDeletion, error) {
+	var deletion model.AccountDeletion
+	if err := s.db.WithContext(c).
+		First(&deletion, "user_id = ? AND status = ?", userID, model.AccountDeletionPending).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDeletionNotFound
+		}
+		return nil, ErrInternal
+	}
+	return &deletion, nil
+}
+
+// CancelDeletion 在冷静期内撤销注销申请
+func (s *AccountService) CancelDeletion(c *gin.Context, userID string) error {
+	result := s.db.WithContext(c).Model(&model.AccountDeletion{}).
+		Where("user_id = ? AND status = ?", userID, model.AccountDeletionPending).
+		Updates(map[string]interface{}{
+			"status":       model.AccountDeletionCancelled,
+			"cancelled_at": time.Now(),
+		})
+	if result.Error != nil {
+		return ErrInternal
+	}
+	if result.RowsAffected == 0 {
+		return ErrDeletionNotFound
+	}
+	return nil
+}
+
+// StartWorker 定期处理导出任务、清理过期的导出文件并执行到期的注销申请
+func (s *AccountService) StartWorker(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.Account.WorkerInterval)
+		defer ticker.Stop()
+
+		for {
+			s.ProcessExports(ctx)
+			s.ExpireExports(ctx)
+			s.ProcessDeletions(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// ProcessExports 打包等待处理的导出任务
+func (s *AccountService) ProcessExports(ctx context.Context) {
+	db := s.db.WithContext(ctx)
+
+	// 进程中断时遗留的任务重新排队
+	if err := db.Model(&model.DataExport{}).
+		Where("status = ? AND updated_at < ?", model.DataExportProcessing, time.Now().Add(-staleExportTimeout)).
+		Update("status", model.DataExportPending).Error; err != nil {
+		s.logger.Error("重置导出任务失败", zap.Error(err))
+	}
+
+	var exports []*model.DataExport
+	if err := db.Where("status = ?", model.DataExportPending).Order("created_at").Limit(10).Find(&exports).Error; err != nil {
+		s.logger.Error("查询导出任务失败", zap.Error(err))
+		return
+	}
+
+	for _, export := range exports {
+		// 多实例部署时只由抢到任务的实例处理
+		result := db.Model(&model.DataExport{}).
+			Where("id = ? AND status = ?", export.ID, model.DataExportPending).
+			Update("status", model.DataExportProcessing)
+		if result.Error != nil || result.RowsAffected == 0 {
+			continue
+		}
+		s.processExport(ctx, export)
+	}
+}
+
+func (s *AccountService) processExport(ctx context.Context, export *model.DataExport) {
+	db := s.db.WithContext(ctx)
+
+	path, size, err := s.buildExport(ctx, export)
+	if err != nil {
+		s.logger.Error("打包个人数据失败", zap.String("exportID", export.ID), zap.Error(err))
+		db.Model(export).Updates(map[string]interface{}{
+			"status": model.DataExportFailed,
+			"error":  "打包失败，请重新申请",
+		})
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.cfg.Account.ExportRetention)
+	if err := db.Model(export).Updates(map[string]interface{}{
+		"status":       model.DataExportCompleted,
+		"file_path":    path,
+		"size":         size,
+		"completed_at": now,
+		"expires_at":   expiresAt,
+	}).Error; err != nil {
+		s.logger.Error("更新导出任务失败", zap.String("exportID", export.ID), zap.Error(err))
+		os.Remove(path)
+		return
+	}
+
+	var user model.User
+	if err := db.Select("email", "name").First(&user, "id = ?", export.UserID).Error; err != nil {
+		return
+	}
+	if err := s.emailService.SendDataExportReady(user.Email, user.Name, expiresAt); err != nil {
+		s.logger.Error("发送导出完成邮件失败", zap.String("exportID", export.ID), zap.Error(err))
+	}
+}
+
+// buildExport 将用户的个人数据和上传的文件打包为 ZIP，返回文件路径和大小
+func (s *AccountService) buildExport(ctx context.Context, export *model.DataExport) (string, int64, error) {
+	dir := s.cfg.GetStoragePath(s.cfg.Account.ExportDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", 0, err
+	}
+	path := filepath.Join(dir, export.ID+".zip")
+	tempPath := path + ".tmp"
+
+	file, err := os.Create(tempPath)
+	if err != nil {
+		return "", 0, err
+	}
+	zw := zip.NewWriter(file)
+	err = s.writeExportArchive(ctx, zw, export.UserID)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return "", 0, err
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return "", 0, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+	return path, info.Size(), nil
+}
+
+func (s *AccountService) writeExportArchive(ctx context.Context, zw *zip.Writer, userID string) error {
+	db := s.db.WithContext(ctx)
+
+	var user model.User
+	if err := db.First(&user, "id = ?", userID).Error; err != nil {
+		return err
+	}
+	var setting model.NotificationSetting
+	if err := db.Where("user_id = ?", userID).Limit(1).Find(&setting).Error; err != nil {
+		return err
+	}
+	if err := writeExportJSON(zw, "profile.json", map[string]interface{}{
+		"user":                    user,
+		"notificationPreferences": setting.Preferences,
+	}); err != nil {
+		return err
+	}
+
+	var reviews []model.Review
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&reviews).Error; err != nil {
+		return err
+	}
+	exportReviews := make([]exportReview, len(reviews))
+	for i, review := range reviews {
+		exportReviews[i] = exportReview{
+			ID:          review.ID,
+			Title:       review.Title,
+			Slug:        review.Slug,
+			Status:      review.Status,
+			ProductID:   review.ProductID,
+			Content:     review.Content,
+			Pros:        review.Pros,
+			Cons:        review.Cons,
+			Conclusion:  review.Conclusion,
+			PublishedAt: review.PublishedAt,
+			CreatedAt:   review.CreatedAt,
+			UpdatedAt:   review.UpdatedAt,
+		}
+	}
+	if err := writeExportJSON(zw, "reviews.json", exportReviews); err != nil {
+		return err
+	}
+
+	var comments []model.Comment
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&comments).Error; err != nil {
+		return err
+	}
+	exportComments := make([]exportComment, len(comments))
+	for i, comment := range comments {
+		exportComments[i] = exportComment{
+			ID:        comment.ID,
+			ReviewID:  comment.ReviewID,
+			ParentID:  comment.ParentID,
+			Content:   comment.Content,
+			Status:    comment.Status,
+			CreatedAt: comment.CreatedAt,
+		}
+	}
+	if err := writeExportJSON(zw, "comments.json", exportComments); err != nil {
+		return err
+	}
+
+	ratings := []model.Rating{}
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&ratings).Error; err != nil {
+		return err
+	}
+	if err := writeExportJSON(zw, "ratings.json", ratings); err != nil {
+		return err
+	}
+
+	favorites := []exportFavorite{}
+	if err := db.Model(&model.UserFavorite{}).
+		Select("user_favorites.product_id, products.name AS product_name, user_favorites.created_at").
+		Joins("LEFT JOIN products ON products.id = user_favorites.product_id").
+		Where("user_favorites.user_id = ?", userID).
+		Order("user_favorites.created_at").
+		Scan(&favorites).Error; err != nil {
+		return err
+	}
+	if err := writeExportJSON(zw, "favorites.json", favorites); err != nil {
+		return err
+	}
+
+	var collections []model.Collection
+	if err := db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Where("user_id = ?", userID).Order("created_at").Find(&collections).Error; err != nil {
+		return err
+	}
+	exportCollections := make([]exportCollection, len(collections))
+	for i, collection := range collections {
+		items := make([]exportCollectionItem, len(collection.Items))
+		for j, item := range collection.Items {
+			items[j] = exportCollectionItem{
+				ProductID: item.ProductID,
+				Note:      item.Note,
+				Position:  item.Position,
+				CreatedAt: item.CreatedAt,
+			}
+		}
+		exportCollections[i] = exportCollection{
+			ID:          collection.ID,
+			Name:        collection.Name,
+			Description: collection.Description,
+			Visibility:  collection.Visibility,
+			Items:       items,
+			CreatedAt:   collection.CreatedAt,
+		}
+	}
+	if err := writeExportJSON(zw, "collections.json", exportCollections); err != nil {
+		return err
+	}
+
+	follows := []model.Follow{}
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&follows).Error; err != nil {
+		return err
+	}
+	if err := writeExportJSON(zw, "follows.json", follows); err != nil {
+		return err
+	}
+
+	var files []model.File
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&files).Error; err != nil {
+		return err
+	}
+	exportFiles := make([]exportFile, len(files))
+	for i, f := range files {
+		exportFiles[i] = exportFile{
+			ID:        f.ID,
+			Name:      f.Name,
+			Type:      f.Type,
+			MimeType:  f.MimeType,
+			Size:      f.Size,
+			URL:       f.URL,
+			CreatedAt: f.CreatedAt,
+		}
+		archivePath := "files/" + f.ID + "-" + filepath.Base(f.Name)
+		copied, err := copyExportFile(zw, archivePath, f.Path)
+		if err != nil {
+			return err
+		}
+		if copied {
+			exportFiles[i].ArchivePath = archivePath
+		}
+	}
+	return writeExportJSON(zw, "files.json", exportFiles)
+}
+
+// writeExportJSON 向压缩包写入格式化的 JSON 文件
+func writeExportJSON(zw *zip.Writer, name string, v interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// copyExportFile 将上传的文件复制到压缩包，原文件不存在时跳过
+func copyExportFile(zw *zip.Writer, name, path string) (bool, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer src.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ExpireExports 删除过期的导出文件
+func (s *AccountService) ExpireExports(ctx context.Context) {
+	db := s.db.WithContext(ctx)
+
+	var exports []*model.DataExport
+	if err := db.Where("status = ? AND expires_at < ?", model.DataExportCompleted, time.Now()).Find(&exports).Error; err != nil {
+		s.logger.Error("查询过期导出任务失败", zap.Error(err))
+		return
+	}
+	for _, export := range exports {
+		if err := os.Remove(export.FilePath); err != nil && !os.IsNotExist(err) {
+			s.logger.Error("删除导出文件失败", zap.String("exportID", export.ID), zap.Error(err))
+			continue
+		}
+		db.Model(export).Updates(map[string]interface{}{
+			"status":    model.DataExportExpired,
+			"file_path": "",
+		})
+	}
+}
+
+// ProcessDeletions 执行冷静期已结束的注销申请
+func (s *AccountService) ProcessDeletions(ctx context.Context) {
+	var deletions []*model.AccountDeletion
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND scheduled_at <= ?", model.AccountDeletionPending, time.Now()).
+		Find(&deletions).Error; err != nil {
+		s.logger.Error("查询注销申请失败", zap.Error(err))
+		return
+	}
+
+	for _, deletion := range deletions {
+		if err := s.anonymizeUser(ctx, deletion.UserID, deletion); err != nil {
+			s.logger.Error("注销账号失败", zap.String("userID", deletion.UserID), zap.Error(err))
+			continue
+		}
+		s.logger.Info("账号已注销", zap.String("userID", deletion.UserID))
+	}
+}
+
+// anonymizeUser 匿名化账号并删除个人数据，测评、评论和评分保留并显示为已注销用户
+// deletion 为空时表示回收站中到期的用户，账号同时移出回收站
+func (s *AccountService) anonymizeUser(ctx context.Context, userID string, deletion *model.AccountDeletion) error {
+	var exportPaths, avatarPaths []string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 撤销和执行并发时以先提交的为准
+		query := tx.Model(&model.AccountDeletion{}).Where("user_id = ?", userID)
+		if deletion != nil {
+			query = tx.Model(deletion)
+		}
+		result := query.
+			Where("status = ?", model.AccountDeletionPending).
+			Updates(map[string]interface{}{
+				"status":       model.AccountDeletionCompleted,
+				"completed_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if deletion != nil && result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Unscoped().Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"name":              deletedUserName,
+			"email":             fmt.Sprintf("deleted-%s@deleted.invalid", userID),
+			"password":          "",
+			"salt":              "",
+			"avatar":            "",
+			"avatar_sizes":      gorm.Expr("NULL"),
+			"bio":               "",
+			"is_email_verified": false,
+			"last_login_at":     nil,
+			"privacy":           gorm.Expr("NULL"),
+			"status":            model.UserStatusDeleted,
+			"deleted_at":        nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.DataExport{}).
+			Where("user_id = ? AND file_path != ''", userID).
+			Pluck("file_path", &exportPaths).Error; err != nil {
+			return err
+		}
+		paths, err := deleteAvatarFiles(tx, userID)
+		if err != nil {
+			return err
+		}
+		avatarPaths = paths
+		for _, personal := range []interface{}{
+			&model.UserFavorite{}, &model.Collection{}, &model.Follow{},
+			&model.Notification{}, &model.NotificationSetting{}, &model.DataExport{},
+		} {
+			if err := tx.Where("user_id = ?", userID).Delete(personal).Error; err != nil {
+				return err
+			}
+		}
+		// 其他用户对该作者的关注一并删除
+		return tx.Where("target_type = ? AND target_id = ?", model.FollowTargetAuthor, userID).
+			Delete(&model.Follow{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, path := range exportPaths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			s.logger.Error("删除导出文件失败", zap.String("path", path), zap.Error(err))
+		}
+	}
+	for _, path := range avatarPaths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			s.logger.Error("删除头像文件失败", zap.String("path", path), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// deleteAvatarFiles 彻底删除用户上传的头像文件记录（包括回收站中的），返回需要删除的物理文件路径
+// 仍被其他用户头像或品牌LOGO引用的文件保留
+func deleteAvatarFiles(tx *gorm.DB, userID string) ([]string, error) {
+	var folder model.Folder
+	if err := tx.Where("path = ?", "/"+avatarFolderName).Take(&folder).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var files []model.File
+	if err := unreferencedImageFiles(tx.Unscoped(), userID, "").
+		Select("id", "path").
+		Where("user_id = ? AND folder_id = ?", userID, folder.ID).
+		Find(&files).Error; err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(files))
+	paths := make([]string, len(files))
+	for i, file := range files {
+		ids[i] = file.ID
+		paths[i] = file.Path
+	}
+	if err := tx.Unscoped().Where("id IN ?", ids).Delete(&model.File{}).Error; err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
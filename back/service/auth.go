@@ -199,6 +199,8 @@ func (s *AuthService) Register(c *gin.Context, req *RegisterRequest) (*TokenResp
 		Name:     req.Name,
 		Role:     model.UserRoleUser,
 		Status:   model.UserStatusActive,
+		// 注册时已校验邮箱验证码
+		IsEmailVerified: true,
 	}
 
 	if err := s.userService.CreateUser(c, user); err != nil {
@@ -256,12 +258,7 @@ func (s *AuthService) GetUserFromToken(c *gin.Context, claims *utils.Claims) (*m
 		return nil, errors.New("无效的token claims")
 	}
 
-	user, err := s.userService.GetUser(c, claims.UserID)
-	if err != nil {
-		return nil, err
-	}
-
-	return user, nil
+	return s.userService.GetUser(c, claims.UserID)
 }
 
 func (s *AuthService) ChangePassword(c *gin.Context, userID, oldPassword, newPassword string) error {
@@ -340,7 +337,7 @@ func (s *AuthService) RefreshToken(c *gin.Context, refreshToken string) (*TokenR
 
 	// 2. 获取用户
 	user, err := s.userService.GetUser(c, claims.UserID)
-	if err != nil {
+	if err != nil || user.Status == model.UserStatusDeleted {
 		return nil, errors.New("用户不存在")
 	}
 
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"beicun/back/model"
+	"beicun/back/utils"
+)
+
+// 头像输出边长（像素），从大到小；原图较小时跳过超出原图的尺寸
+var avatarSizes = []int{512, 256, 128, 64}
+
+const (
+	maxAvatarFileSize  = 5 * 1024 * 1024 // 头像原图最大 5MB
+	maxAvatarPixels    = 40000000        // 头像原图最大像素数，避免解码超大图片占满内存
+	minAvatarDimension = 64              // 头像原图短边最小像素
+	avatarFolderName   = "avatars"       // 头像文件所在的文件夹
+)
+
+var (
+	ErrAvatarTooLarge = errors.New("头像图片过大，请上传不超过 5MB 且不超过 4000 万像素的图片")
+	ErrAvatarInvalid  = errors.New("不支持的图片格式，请上传 JPG、PNG 或 GIF 图片")
+	ErrAvatarTooSmall = errors.New("头像图片尺寸不能小于 64×64")
+)
+
+// 图片校验的通用错误，由调用方转换为头像、品牌LOGO各自的提示
+var (
+	errImageTooLarge = errors.New("图片过大")
+	errImageInvalid  = errors.New("不支持的图片格式")
+	errImageTooSmall = errors.New("图片尺寸过小")
+)
+
+// imageErrors 图片校验失败时对外返回的错误
+type imageErrors struct {
+	tooLarge error
+	invalid  error
+	tooSmall error
+}
+
+var avatarImageErrors = imageErrors{
+	tooLarge: ErrAvatarTooLarge,
+	invalid:  ErrAvatarInvalid,
+	tooSmall: ErrAvatarTooSmall,
+}
+
+// wrap 将通用的图片校验错误转换为对应的业务错误
+func (e imageErrors) wrap(err error) error {
+	switch err {
+	case errImageTooLarge:
+		return e.tooLarge
+	case errImageInvalid:
+		return e.invalid
+	case errImageTooSmall:
+		return e.tooSmall
+	}
+	return err
+}
+
+// AvatarResponse 头像上传结果
+type AvatarResponse struct {
+	Avatar string            `json:"avatar"` // 最大尺寸的头像地址
+	Sizes  model.AvatarSizes `json:"sizes"`  // 各尺寸头像地址
+}
+
+// UploadAvatar 上传头像：修正 EXIF 方向后居中裁剪为正方形，生成多个尺寸并替换用户当前头像
+func (s *UploadService) UploadAvatar(c *gin.Context, userID string, header *multipart.FileHeader) (*AvatarResponse, error) {
+	tempPath, err := s.saveTempImage(c, header, "avatar", maxAvatarFileSize)
+	if err != nil {
+		return nil, avatarImageErrors.wrap(err)
+	}
+	defer os.Remove(tempPath)
+
+	_, format, err := checkImageHeader(tempPath, maxAvatarPixels, minAvatarDimension)
+	if err != nil {
+		return nil, avatarImageErrors.wrap(err)
+	}
+
+	img, _ := s.correctImageOrientation(tempPath)
+	if img == nil {
+		return nil, ErrAvatarInvalid
+	}
+	bounds := img.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	square := imaging.CropCenter(img, side, side)
+
+	// PNG 和 GIF 可能带透明背景，输出 PNG，其余输出 JPEG
+	outFormat, outExt, mimeType := imaging.JPEG, ".jpg", "image/jpeg"
+	if format == "png" || format == "gif" {
+		outFormat, outExt, mimeType = imaging.PNG, ".png", "image/png"
+	}
+
+	folder, err := s.getImageFolder(avatarFolderName)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := model.AvatarSizes{}
+	avatar := ""
+	for _, size := range avatarSizes {
+		if size > side {
+			continue
+		}
+		var buf bytes.Buffer
+		resized := imaging.Resize(square, size, size, imaging.Lanczos)
+		if err := imaging.Encode(&buf, resized, outFormat, imaging.JPEGQuality(90)); err != nil {
+			return nil, err
+		}
+		file, err := s.saveImageVariant(userID, folder, "avatar", size, buf.Bytes(), outExt, mimeType)
+		if err != nil {
+			return nil, err
+		}
+		sizes[size] = file.URL
+		if avatar == "" {
+			avatar = file.URL
+		}
+	}
+
+	if err := s.replaceAvatar(userID, folder.ID, avatar, sizes); err != nil {
+		return nil, err
+	}
+	return &AvatarResponse{Avatar: avatar, Sizes: sizes}, nil
+}
+
+// saveTempImage 校验文件大小和扩展名后将上传的图片保存到临时目录（方向修正需要读取原始文件），
+// 返回临时文件路径，由调用方删除
+func (s *UploadService) saveTempImage(c *gin.Context, header *multipart.FileHeader, prefix string, maxFileSize int64) (string, error) {
+	if header.Size > maxFileSize {
+		return "", errImageTooLarge
+	}
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	switch ext {
+	case ".jpg", ".jpeg", ".png", ".gif":
+	default:
+		return "", errImageInvalid
+	}
+
+	tempDir := filepath.Join(s.cfg.Path, s.cfg.TempDir)
+	if err := utils.EnsureDir(tempDir); err != nil {
+		return "", err
+	}
+	tempPath := filepath.Join(tempDir, prefix+"-"+uuid.New().String()+ext)
+	if err := c.SaveUploadedFile(header, tempPath); err != nil {
+		return "", err
+	}
+	return tempPath, nil
+}
+
+// checkImageHeader 读取图片头信息，按文件内容校验格式、像素数和短边尺寸（minSide 为 0 时不校验短边），
+// 不信任扩展名，返回图片尺寸和格式
+func checkImageHeader(path string, maxPixels, minSide int) (image.Config, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, "", err
+	}
+	defer f.Close()
+
+	config, format, err := image.DecodeConfig(f)
+	if err != nil {
+		return image.Config{}, "", errImageInvalid
+	}
+	switch format {
+	case "jpeg", "png", "gif":
+	default:
+		return image.Config{}, "", errImageInvalid
+	}
+	if config.Width*config.Height > maxPixels {
+		return image.Config{}, "", errImageTooLarge
+	}
+	if config.Width < minSide || config.Height < minSide {
+		return image.Config{}, "", errImageTooSmall
+	}
+	return config, format, nil
+}
+
+// getImageFolder 获取头像、品牌LOGO等图片所在的根文件夹，不存在时创建
+func (s *UploadService) getImageFolder(name string) (*model.Folder, error) {
+	var folder model.Folder
+	if err := s.db.Where(model.Folder{Path: "/" + name}).
+		Attrs(model.Folder{Name: name}).
+		FirstOrCreate(&folder).Error; err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// saveImageVariant 保存图片的一个尺寸并创建文件记录，内容相同的文件直接复用
+func (s *UploadService) saveImageVariant(userID string, folder *model.Folder, prefix string, size int, data []byte, ext, mimeType string) (*model.File, error) {
+	sum := md5.Sum(data)
+	md5sum := hex.EncodeToString(sum[:])
+
+	var existing model.File
+	err := s.db.Where("md5 = ?", md5sum).First(&existing).Error
+	if err == nil {
+		return &existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	fileID := uuid.New().String()
+	relativePath := filepath.Join(
+		folder.Name,
+		fmt.Sprintf("%d", now.Year()),
+		fmt.Sprintf("%02d", now.Month()),
+		fmt.Sprintf("%s_%d%s", fileID, size, ext),
+	)
+	fullPath := filepath.Join(s.cfg.Path, s.cfg.UploadDir, relativePath)
+	if err := utils.EnsureDir(filepath.Dir(fullPath)); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(fullPath, data, 0644); err != nil {
+		return nil, err
+	}
+
+	file := &model.File{
+		ID:       fileID,
+		Name:     fmt.Sprintf("%s_%d%s", prefix, size, ext),
+		Path:     fullPath,
+		URL:      "/" + strings.ReplaceAll(filepath.Join(s.cfg.UploadDir, relativePath), "\\", "/"),
+		Size:     int64(len(data)),
+		Type:     model.FileTypeImage,
+		MimeType: mimeType,
+		Width:    &size,
+		Height:   &size,
+		MD5:      md5sum,
+		FolderID: folder.ID,
+		UserID:   userID,
+	}
+	if err := s.db.Create(file).Error; err != nil {
+		os.Remove(fullPath)
+		return nil, err
+	}
+	return file, nil
+}
+
+// replaceAvatar 更新用户头像，不再被引用的旧头像文件移入回收站
+func (s *UploadService) replaceAvatar(userID, folderID, avatar string, sizes model.AvatarSizes) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.Select("id", "avatar_sizes").First(&user, "id = ?", userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		var oldURLs, newURLs []string
+		for _, url := range user.AvatarSizes {
+			oldURLs = append(oldURLs, url)
+		}
+		for _, url := range sizes {
+			newURLs = append(newURLs, url)
+		}
+
+		user.Avatar = avatar
+		user.AvatarSizes = sizes
+		if err := tx.Model(&user).Select("avatar", "avatar_sizes").Updates(&user).Error; err != nil {
+			return err
+		}
+
+		if len(oldURLs) == 0 {
+			return nil
+		}
+		// 旧头像可能复用了其他用户上传的文件，也可能仍被其他用户引用，只按引用关系判断
+		return unreferencedImageFiles(tx, userID, "").
+			Where("folder_id = ? AND url IN ? AND url NOT IN ?", folderID, oldURLs, newURLs).
+			Delete(&model.File{}).Error
+	})
+}
+
+// unreferencedImageFiles 过滤掉仍被其他用户头像或其他品牌LOGO引用的文件，
+// 内容相同的图片会复用同一个文件记录，exceptUserID、exceptBrandID 为正在更新的对象，为空表示不排除
+func unreferencedImageFiles(query *gorm.DB, exceptUserID, exceptBrandID string) *gorm.DB {
+	return query.
+		Where(`NOT EXISTS (SELECT 1 FROM users u WHERE u.id::text <> ? AND (u.avatar = files.url OR
+			jsonb_path_exists(u.avatar_sizes, ?::jsonpath, jsonb_build_object('url', files.url))))`,
+			exceptUserID, "$.* ? (@ == $url)").
+		Where(`NOT EXISTS (SELECT 1 FROM brands b WHERE b.id::text <> ? AND (b.logo = files.url OR
+			jsonb_path_exists(b.logo_variants, ?::jsonpath, jsonb_build_object('url', files.url))))`,
+			exceptBrandID, "$[*].* ? (@ == $url)")
+}
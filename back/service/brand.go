@@ -3,31 +3,48 @@ package service
 import (
 	"beicun/back/model"
 	"errors"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/gosimple/slug"
 	"gorm.io/gorm"
 )
 
 var (
-	ErrBrandNotFound = errors.New("品牌不存在")
-	ErrBrandExists   = errors.New("品牌已存在")
+	ErrBrandNotFound         = errors.New("品牌不存在")
+	ErrBrandExists           = errors.New("品牌已存在")
+	ErrBrandHasProducts      = errors.New("品牌下仍有产品，无法删除")
+	ErrBrandHasSubBrands     = errors.New("品牌下仍有子品牌，无法删除")
+	ErrBrandParentNotFound   = errors.New("母品牌不存在")
+	ErrBrandParentCycle      = errors.New("不能将品牌自身或其子品牌设为母品牌")
+	ErrInvalidFoundedYear    = errors.New("创立年份无效")
+	ErrInvalidSocialPlatform = errors.New("不支持的社交媒体平台")
 )
 
+// 品牌创立年份下限
+const minBrandFoundedYear = 1800
+
 type CreateBrandRequest struct {
-	Name        string  `json:"name" binding:"required"`
-	Description string  `json:"description"`
-	Website     *string `json:"website,omitempty"`
-	Logo        string  `json:"logo,omitempty"`
-	SortOrder   int     `json:"sortOrder"`
+	Name        string                 `json:"name" binding:"required"`
+	Description string                 `json:"description"`
+	Website     *string                `json:"website,omitempty"`
+	Logo        string                 `json:"logo,omitempty"`
+	SortOrder   int                    `json:"sortOrder"`
+	ParentID    *string                `json:"parentId,omitempty"`                                        // 母品牌ID
+	Country     string                 `json:"country,omitempty" binding:"omitempty,iso3166_1_alpha2"`    // 原产国（ISO 3166-1 二位代码，大写）
+	FoundedYear *int                   `json:"foundedYear,omitempty"`                                     // 创立年份
+	SocialLinks model.BrandSocialLinks `json:"socialLinks,omitempty" binding:"omitempty,max=20,dive,url"` // 社交媒体链接，键为平台
 }
 
 type UpdateBrandRequest struct {
-	Name        string  `json:"name,omitempty"`
-	Description string  `json:"description,omitempty"`
-	Website     *string `json:"website,omitempty"`
-	Logo        string  `json:"logo,omitempty"`
-	SortOrder   *int    `json:"sortOrder,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Website     *string                `json:"website,omitempty"`
+	Logo        string                 `json:"logo,omitempty"`
+	SortOrder   *int                   `json:"sortOrder,omitempty"`
+	ParentID    *string                `json:"parentId,omitempty"`                                           // 母品牌ID，传空字符串取消母品牌
+	Country     *string                `json:"country,omitempty" binding:"omitempty,len=0|iso3166_1_alpha2"` // 原产国，传空字符串清除
+	FoundedYear *int                   `json:"foundedYear,omitempty"`                                        // 创立年份，传 0 清除
+	SocialLinks model.BrandSocialLinks `json:"socialLinks,omitempty" binding:"omitempty,max=20,dive,url"`    // 社交媒体链接，传空对象清除
 }
 
 type BrandQueryParams struct {
@@ -47,38 +64,32 @@ func NewBrandService(db *gorm.DB) *BrandService {
 }
 
 // generateUniqueSlug 生成唯一的品牌 slug
-func (s *BrandService) generateUniqueSlug(name string) (string, error) {
-	baseSlug := slug.Make(name)
-	finalSlug := baseSlug
-	counter := 1
-
-	for {
-		var count int64
-		if err := s.db.Model(&model.Brand{}).Where("slug = ?", finalSlug).Count(&count).Error; err != nil {
-			return "", err
-		}
-		if count == 0 {
-			break
-		}
-		finalSlug = slug.Make(baseSlug + "-" + string(counter))
-		counter++
-	}
-
-	return finalSlug, nil
+func (s *BrandService) generateUniqueSlug(name, excludeID string) (string, error) {
+	return generateEntitySlug(s.db, &model.Brand{}, model.SlugEntityBrand, name, excludeID)
 }
 
 // CreateBrand 创建品牌
 func (s *BrandService) CreateBrand(c *gin.Context, req *CreateBrandRequest) (*model.Brand, error) {
 	var count int64
-	if err := s.db.Model(&model.Brand{}).Where("name = ?", req.Name).Count(&count).Error; err != nil {
+	if err := s.db.Unscoped().Model(&model.Brand{}).Where("name = ?", req.Name).Count(&count).Error; err != nil {
 		return nil, err
 	}
 	if count > 0 {
 		return nil, ErrBrandExists
 	}
+	if err := validateBrandProfile(req.FoundedYear, req.SocialLinks); err != nil {
+		return nil, err
+	}
+	if req.ParentID != nil && *req.ParentID != "" {
+		if err := s.checkBrandParent("", *req.ParentID); err != nil {
+			return nil, err
+		}
+	} else {
+		req.ParentID = nil
+	}
 
 	// 生成唯一的 slug
-	slug, err := s.generateUniqueSlug(req.Name)
+	slug, err := s.generateUniqueSlug(req.Name, "")
 	if err != nil {
 		return nil, err
 	}
@@ -90,6 +101,10 @@ func (s *BrandService) CreateBrand(c *gin.Context, req *CreateBrandRequest) (*mo
 		Logo:        req.Logo,
 		Website:     req.Website,
 		SortOrder:   req.SortOrder,
+		ParentID:    req.ParentID,
+		Country:     req.Country,
+		FoundedYear: req.FoundedYear,
+		SocialLinks: req.SocialLinks,
 	}
 
 	if err := s.db.Create(brand).Error; err != nil {
@@ -105,10 +120,11 @@ func (s *BrandService) UpdateBrand(c *gin.Context, id string, req *UpdateBrandRe
 	if err != nil {
 		return nil, err
 	}
+	oldSlug := brand.Slug
 
 	if req.Name != "" && req.Name != brand.Name {
 		var count int64
-		if err := s.db.Model(&model.Brand{}).Where("name = ? AND id != ?", req.Name, id).Count(&count).Error; err != nil {
+		if err := s.db.Unscoped().Model(&model.Brand{}).Where("name = ? AND id != ?", req.Name, id).Count(&count).Error; err != nil {
 			return nil, err
 		}
 		if count > 0 {
@@ -117,7 +133,7 @@ func (s *BrandService) UpdateBrand(c *gin.Context, id string, req *UpdateBrandRe
 		brand.Name = req.Name
 
 		// 更新 slug
-		slug, err := s.generateUniqueSlug(req.Name)
+		slug, err := s.generateUniqueSlug(req.Name, id)
 		if err != nil {
 			return nil, err
 		}
@@ -136,8 +152,40 @@ func (s *BrandService) UpdateBrand(c *gin.Context, id string, req *UpdateBrandRe
 	if req.SortOrder != nil {
 		brand.SortOrder = *req.SortOrder
 	}
+	if req.ParentID != nil {
+		if *req.ParentID == "" {
+			brand.ParentID = nil
+		} else {
+			if err := s.checkBrandParent(brand.ID, *req.ParentID); err != nil {
+				return nil, err
+			}
+			brand.ParentID = req.ParentID
+		}
+	}
+	if req.Country != nil {
+		brand.Country = *req.Country
+	}
+	if req.FoundedYear != nil {
+		if *req.FoundedYear == 0 {
+			brand.FoundedYear = nil
+		} else {
+			brand.FoundedYear = req.FoundedYear
+		}
+	}
+	if req.SocialLinks != nil {
+		brand.SocialLinks = req.SocialLinks
+	}
+	if err := validateBrandProfile(brand.FoundedYear, brand.SocialLinks); err != nil {
+		return nil, err
+	}
 
-	if err := s.db.Save(brand).Error; err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(brand).Error; err != nil {
+			return err
+		}
+		return recordSlugChange(tx, model.SlugEntityBrand, brand.ID, oldSlug, brand.Slug)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -146,6 +194,20 @@ func (s *BrandService) UpdateBrand(c *gin.Context, id string, req *UpdateBrandRe
 
 // DeleteBrand 删除品牌
 func (s *BrandService) DeleteBrand(c *gin.Context, id string) error {
+	var count int64
+	if err := s.db.Model(&model.Product{}).Where("brand_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrBrandHasProducts
+	}
+	if err := s.db.Model(&model.Brand{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrBrandHasSubBrands
+	}
+
 	result := s.db.Delete(&model.Brand{}, "id = ?", id)
 	if result.Error != nil {
 		return result.Error
@@ -169,12 +231,12 @@ func (s *BrandService) GetBrand(c *gin.Context, id string) (*model.Brand, error)
 	return &brand, nil
 }
 
-// GetBrandBySlug 通过 slug 获取品牌详情
+// GetBrandBySlug 通过 slug 获取品牌详情，旧 slug 返回 SlugMovedError
 func (s *BrandService) GetBrandBySlug(c *gin.Context, slug string) (*model.Brand, error) {
 	var brand model.Brand
 	if err := s.db.Where("slug = ?", slug).First(&brand).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrBrandNotFound
+			return nil, resolveMovedSlug(s.db, &model.Brand{}, model.SlugEntityBrand, slug, ErrBrandNotFound)
 		}
 		return nil, err
 	}
@@ -217,7 +279,7 @@ func (s *BrandService) ListBrands(c *gin.Context, params *BrandQueryParams) ([]*
 
 	// 添加搜索条件
 	if params.Keyword != "" {
-		query = query.Where("name LIKE ? OR description LIKE ?", 
+		query = query.Where("name LIKE ? OR description LIKE ?",
 			"%"+params.Keyword+"%", "%"+params.Keyword+"%")
 	}
 
@@ -248,3 +310,158 @@ func (s *BrandService) ListBrands(c *gin.Context, params *BrandQueryParams) ([]*
 
 	return brands, total, nil
 }
+
+// validateBrandProfile 校验品牌创立年份和社交媒体平台
+func validateBrandProfile(foundedYear *int, links model.BrandSocialLinks) error {
+	if foundedYear != nil && (*foundedYear < minBrandFoundedYear || *foundedYear > time.Now().Year()) {
+		return ErrInvalidFoundedYear
+	}
+	for platform := range links {
+		if !model.BrandSocialPlatforms[platform] {
+			return ErrInvalidSocialPlatform
+		}
+	}
+	return nil
+}
+
+// checkBrandParent 校验母品牌存在，且沿母品牌链向上不会回到当前品牌
+func (s *BrandService) checkBrandParent(id, parentID string) error {
+	visited := make(map[string]bool)
+	current := parentID
+	for current != "" {
+		if current == id || visited[current] {
+			return ErrBrandParentCycle
+		}
+		visited[current] = true
+
+		var parent model.Brand
+		if err := s.db.Select("id", "parent_id").Where("id = ?", current).First(&parent).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				if current == parentID {
+					return ErrBrandParentNotFound
+				}
+				return nil
+			}
+			return err
+		}
+		current = ""
+		if parent.ParentID != nil {
+			current = *parent.ParentID
+		}
+	}
+	return nil
+}
+
+// BrandStats 品牌统计信息
+type BrandStats struct {
+	ProductCount  int64    `json:"productCount"`  // 产品数
+	AverageRating float64  `json:"averageRating"` // 平均评分，按各产品评分数加权
+	RatingCount   int64    `json:"ratingCount"`   // 评分数
+	ReviewCount   int64    `json:"reviewCount"`   // 已发布的测评数
+	MinPrice      *float64 `json:"minPrice"`      // 最低价格（含产品版本），没有定价时为空
+	MaxPrice      *float64 `json:"maxPrice"`      // 最高价格（含产品版本），没有定价时为空
+}
+
+// BrandDetailResponse 品牌详情，包含母品牌、子品牌和统计信息
+type BrandDetailResponse struct {
+	*model.Brand
+	Parent    *BrandBrief   `json:"parent,omitempty"` // 母品牌
+	SubBrands []*BrandBrief `json:"subBrands"`        // 子品牌
+	Stats     *BrandStats   `json:"stats"`            // 统计信息
+}
+
+// GetBrandDetailBySlug 通过 slug 获取品牌详情及统计信息，旧 slug 返回 SlugMovedError
+func (s *BrandService) GetBrandDetailBySlug(c *gin.Context, slug string) (*BrandDetailResponse, error) {
+	brand, err := s.GetBrandBySlug(c, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &BrandDetailResponse{Brand: brand, SubBrands: make([]*BrandBrief, 0)}
+	if brand.ParentID != nil {
+		var parent model.Brand
+		err := s.db.Select("id", "name", "slug", "logo").Where("id = ?", *brand.ParentID).First(&parent).Error
+		if err == nil {
+			resp.Parent = &BrandBrief{ID: parent.ID, Name: parent.Name, Slug: parent.Slug, Logo: parent.Logo}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	var subBrands []model.Brand
+	if err := s.db.Select("id", "name", "slug", "logo").
+		Where("parent_id = ?", brand.ID).
+		Order("sort_order ASC, name ASC").
+		Find(&subBrands).Error; err != nil {
+		return nil, err
+	}
+	for _, sub := range subBrands {
+		resp.SubBrands = append(resp.SubBrands, &BrandBrief{ID: sub.ID, Name: sub.Name, Slug: sub.Slug, Logo: sub.Logo})
+	}
+
+	stats, err := s.getBrandStats(brand.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp.Stats = stats
+	return resp, nil
+}
+
+// getBrandStats 统计品牌的产品数、评分、已发布测评数和价格区间
+func (s *BrandService) getBrandStats(brandID string) (*BrandStats, error) {
+	var stats BrandStats
+	if err := s.db.Model(&model.Product{}).
+		Where("brand_id = ?", brandID).
+		Count(&stats.ProductCount).Error; err != nil {
+		return nil, err
+	}
+
+	// 评分统计与产品评分一致，直接汇总 ratings 表
+	var ratings struct {
+		AverageRating float64
+		RatingCount   int64
+	}
+	err := s.db.Raw(`
+		SELECT
+			COALESCE(ROUND(AVG(ratings.rating)::numeric, 2), 0) AS average_rating,
+			COUNT(*) AS rating_count
+		FROM ratings
+		JOIN products ON products.id = ratings.product_id
+		WHERE products.brand_id = ? AND products.deleted_at IS NULL
+	`, brandID).Scan(&ratings).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.AverageRating = ratings.AverageRating
+	stats.RatingCount = ratings.RatingCount
+
+	if err := s.db.Model(&model.Review{}).
+		Joins("JOIN products ON products.id = reviews.product_id AND products.deleted_at IS NULL").
+		Where("products.brand_id = ? AND reviews.status = ?", brandID, model.ReviewStatusPublished).
+		Count(&stats.ReviewCount).Error; err != nil {
+		return nil, err
+	}
+
+	// 价格为 0 表示未定价，不计入价格区间
+	var prices struct {
+		MinPrice *float64
+		MaxPrice *float64
+	}
+	err = s.db.Raw(`
+		SELECT MIN(price) AS min_price, MAX(price) AS max_price
+		FROM (
+			SELECT p.price FROM products p
+			WHERE p.brand_id = ? AND p.deleted_at IS NULL AND p.price > 0
+			UNION ALL
+			SELECT v.price FROM product_variants v
+			JOIN products p ON p.id = v.product_id
+			WHERE p.brand_id = ? AND p.deleted_at IS NULL AND v.price > 0
+		) t
+	`, brandID, brandID).Scan(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.MinPrice = prices.MinPrice
+	stats.MaxPrice = prices.MaxPrice
+	return &stats, nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"mime/multipart"
+	"os"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/disintegration/imaging"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"beicun/back/model"
+)
+
+// 品牌LOGO输出边长（像素），从大到小；原图较小时跳过超出原图长边的尺寸
+var brandLogoSizes = []int{512, 256, 128}
+
+const (
+	maxBrandLogoFileSize  = 5 * 1024 * 1024 // LOGO 原图最大 5MB
+	maxBrandLogoPixels    = 40000000        // LOGO 原图最大像素数
+	minBrandLogoDimension = 128             // LOGO 原图长边最小像素
+	brandLogoFolderName   = "brand-logos"   // 品牌LOGO文件所在的文件夹
+)
+
+var (
+	ErrBrandLogoTooLarge = errors.New("LOGO 图片过大，请上传不超过 5MB 且不超过 4000 万像素的图片")
+	ErrBrandLogoInvalid  = errors.New("不支持的图片格式，请上传 JPG、PNG 或 GIF 图片")
+	ErrBrandLogoTooSmall = errors.New("LOGO 图片长边不能小于 128 像素")
+)
+
+var brandLogoImageErrors = imageErrors{
+	tooLarge: ErrBrandLogoTooLarge,
+	invalid:  ErrBrandLogoInvalid,
+	tooSmall: ErrBrandLogoTooSmall,
+}
+
+// BrandLogoResponse 品牌LOGO上传结果
+type BrandLogoResponse struct {
+	Logo     string                  `json:"logo"`     // 最大尺寸的 PNG 地址
+	Variants model.BrandLogoVariants `json:"variants"` // 各尺寸 PNG 和 WebP 地址
+}
+
+// UploadBrandLogo 上传品牌LOGO：等比缩放后居中放入透明背景的正方形画布，
+// 生成多个尺寸的 PNG 和 WebP 并替换品牌当前 LOGO
+func (s *UploadService) UploadBrandLogo(c *gin.Context, userID, brandID string, header *multipart.FileHeader) (*BrandLogoResponse, error) {
+	var count int64
+	if err := s.db.Model(&model.Brand{}).Where("id = ?", brandID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrBrandNotFound
+	}
+
+	tempPath, err := s.saveTempImage(c, header, "brand-logo", maxBrandLogoFileSize)
+	if err != nil {
+		return nil, brandLogoImageErrors.wrap(err)
+	}
+	defer os.Remove(tempPath)
+
+	// LOGO 不裁剪，只要求长边足够
+	config, _, err := checkImageHeader(tempPath, maxBrandLogoPixels, 0)
+	if err != nil {
+		return nil, brandLogoImageErrors.wrap(err)
+	}
+	longest := config.Width
+	if config.Height > longest {
+		longest = config.Height
+	}
+	if longest < minBrandLogoDimension {
+		return nil, ErrBrandLogoTooSmall
+	}
+
+	img, _ := s.correctImageOrientation(tempPath)
+	if img == nil {
+		return nil, ErrBrandLogoInvalid
+	}
+
+	folder, err := s.getImageFolder(brandLogoFolderName)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := model.BrandLogoVariants{}
+	for _, size := range brandLogoSizes {
+		if size > longest {
+			continue
+		}
+		// LOGO 不裁剪，等比缩放后留白部分保持透明
+		canvas := imaging.New(size, size, color.NRGBA{})
+		canvas = imaging.PasteCenter(canvas, imaging.Fit(img, size, size, imaging.Lanczos))
+
+		var pngBuf, webpBuf bytes.Buffer
+		if err := imaging.Encode(&pngBuf, canvas, imaging.PNG); err != nil {
+			return nil, err
+		}
+		if err := nativewebp.Encode(&webpBuf, canvas, nil); err != nil {
+			return nil, err
+		}
+		pngFile, err := s.saveImageVariant(userID, folder, "logo", size, pngBuf.Bytes(), ".png", "image/png")
+		if err != nil {
+			return nil, err
+		}
+		webpFile, err := s.saveImageVariant(userID, folder, "logo", size, webpBuf.Bytes(), ".webp", "image/webp")
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, model.BrandLogoVariant{Size: size, PNG: pngFile.URL, WebP: webpFile.URL})
+	}
+
+	logo := variants[0].PNG
+	if err := s.replaceBrandLogo(brandID, folder.ID, logo, variants); err != nil {
+		return nil, err
+	}
+	return &BrandLogoResponse{Logo: logo, Variants: variants}, nil
+}
+
+// replaceBrandLogo 更新品牌LOGO，不再被引用的旧 LOGO 文件移入回收站
+func (s *UploadService) replaceBrandLogo(brandID, folderID, logo string, variants model.BrandLogoVariants) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var brand model.Brand
+		if err := tx.Select("id", "logo_variants").First(&brand, "id = ?", brandID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrBrandNotFound
+			}
+			return err
+		}
+
+		var oldURLs, newURLs []string
+		for _, variant := range brand.LogoVariants {
+			oldURLs = append(oldURLs, variant.PNG, variant.WebP)
+		}
+		for _, variant := range variants {
+			newURLs = append(newURLs, variant.PNG, variant.WebP)
+		}
+
+		brand.Logo = logo
+		brand.LogoVariants = variants
+		if err := tx.Model(&brand).Select("logo", "logo_variants").Updates(&brand).Error; err != nil {
+			return err
+		}
+
+		if len(oldURLs) == 0 {
+			return nil
+		}
+		return unreferencedImageFiles(tx, "", brandID).
+			Where("folder_id = ? AND url IN ? AND url NOT IN ?", folderID, oldURLs, newURLs).
+			Delete(&model.File{}).Error
+	})
+}
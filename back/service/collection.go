@@ -0,0 +1,564 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"beicun/back/model"
+	"beicun/back/utils"
+)
+
+// 单个收藏夹最多包含的产品数，详情接口一次返回全部产品
+const maxCollectionItems = 200
+
+// 分享令牌长度
+const collectionShareTokenLength = 24
+
+var (
+	ErrCollectionNotFound          = errors.New("收藏夹不存在")
+	ErrCollectionItemNotFound      = errors.New("收藏夹中没有该产品")
+	ErrCollectionItemExists        = errors.New("产品已在收藏夹中")
+	ErrCollectionFull              = errors.New("收藏夹中的产品已达上限")
+	ErrInvalidCollectionVisibility = errors.New("无效的收藏夹可见性")
+	ErrInvalidCollectionItemsOrder = errors.New("排序列表必须包含收藏夹中的全部产品")
+)
+
+// CreateCollectionRequest 创建收藏夹请求
+type CreateCollectionRequest struct {
+	Name        string                     `json:"name" binding:"required,max=100"`
+	Description string                     `json:"description" binding:"max=1000"`
+	Visibility  model.CollectionVisibility `json:"visibility"` // 默认为 PRIVATE
+}
+
+// UpdateCollectionRequest 更新收藏夹请求，只更新提交的字段
+type UpdateCollectionRequest struct {
+	Name        *string                     `json:"name" binding:"omitempty,min=1,max=100"`
+	Description *string                     `json:"description" binding:"omitempty,max=1000"`
+	Visibility  *model.CollectionVisibility `json:"visibility"`
+}
+
+// AddCollectionItemRequest 向收藏夹添加产品请求，新产品排在末尾
+type AddCollectionItemRequest struct {
+	ProductID uint   `json:"productId" binding:"required"`
+	Note      string `json:"note" binding:"max=500"`
+}
+
+// UpdateCollectionItemRequest 更新收藏夹产品备注请求
+type UpdateCollectionItemRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+// ReorderCollectionItemsRequest 调整收藏夹产品顺序请求
+type ReorderCollectionItemsRequest struct {
+	ProductIDs []uint `json:"productIds" binding:"required"` // 收藏夹中全部产品ID，按新顺序排列
+}
+
+// CollectionResponse 收藏夹信息
+type CollectionResponse struct {
+	ID          string                     `json:"id"`
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Visibility  model.CollectionVisibility `json:"visibility"`
+	ShareToken  string                     `json:"shareToken,omitempty"` // 分享令牌，仅创建者可见
+	ItemCount   int64                      `json:"itemCount"`
+	Owner       *UserBrief                 `json:"owner"`
+	CreatedAt   string                     `json:"createdAt"`
+	UpdatedAt   string                     `json:"updatedAt"`
+}
+
+// CollectionItemResponse 收藏夹中的产品
+type CollectionItemResponse struct {
+	Product  *ProductBrief `json:"product"`
+	Note     string        `json:"note"`
+	Position int           `json:"position"`
+	AddedAt  string        `json:"addedAt"`
+}
+
+// CollectionDetailResponse 收藏夹详情，包含全部产品
+type CollectionDetailResponse struct {
+	CollectionResponse
+	Items []*CollectionItemResponse `json:"items"`
+}
+
+type CollectionService struct {
+	db *gorm.DB
+}
+
+func NewCollectionService(db *gorm.DB) *CollectionService {
+	return &CollectionService{db: db}
+}
+
+// CreateCollection 创建收藏夹
+func (s *CollectionService) CreateCollection(c *gin.Context, userID string, req *CreateCollectionRequest) (*CollectionResponse, error) {
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = model.CollectionVisibilityPrivate
+	}
+	if !visibility.IsValid() {
+		return nil, ErrInvalidCollectionVisibility
+	}
+
+	collection := &model.Collection{
+		UserID:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+		Visibility:  visibility,
+	}
+	if err := s.db.WithContext(c).Create(collection).Error; err != nil {
+		return nil, ErrInternal
+	}
+	return s.getCollectionResponse(c, collection, true)
+}
+
+// UpdateCollection 更新收藏夹名称、描述或可见性
+func (s *CollectionService) UpdateCollection(c *gin.Context, userID, collectionID string, req *UpdateCollectionRequest) (*CollectionResponse, error) {
+	if req.Visibility != nil && !req.Visibility.IsValid() {
+		return nil, ErrInvalidCollectionVisibility
+	}
+
+	collection, err := s.getOwnedCollection(s.db.WithContext(c), userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.Visibility != nil {
+		updates["visibility"] = *req.Visibility
+	}
+	if len(updates) > 0 {
+		if err := s.db.WithContext(c).Model(collection).Updates(updates).Error; err != nil {
+			return nil, ErrInternal
+		}
+	}
+	return s.getCollectionResponse(c, collection, true)
+}
+
+// DeleteCollection 删除收藏夹及其中的产品记录
+func (s *CollectionService) DeleteCollection(c *gin.Context, userID, collectionID string) error {
+	result := s.db.WithContext(c).Where("id = ? AND user_id = ?", collectionID, userID).Delete(&model.Collection{})
+	if result.Error != nil {
+		return ErrInternal
+	}
+	if result.RowsAffected == 0 {
+		return ErrCollectionNotFound
+	}
+	return nil
+}
+
+// ListMyCollections 获取当前用户的全部收藏夹
+func (s *CollectionService) ListMyCollections(c *gin.Context, userID string, page, pageSize int) ([]*CollectionResponse, int64, error) {
+	return s.listCollections(c, s.db.WithContext(c).Where("user_id = ?", userID), true, page, pageSize)
+}
+
+// ListUserCollections 获取用户的收藏夹，本人和管理员可以看到私有收藏夹
+func (s *CollectionService) ListUserCollections(c *gin.Context, ownerID, viewerID string, viewerRole model.UserRole, page, pageSize int) ([]*CollectionResponse, int64, error) {
+	var count int64
+	if err := s.db.WithContext(c).Model(&model.User{}).
+		Where("id = ? AND status NOT IN ?", ownerID, model.HiddenUserStatuses).
+		Count(&count).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+	if count == 0 {
+		return nil, 0, ErrUserNotFound
+	}
+
+	isOwner := viewerID == ownerID
+	query := s.db.WithContext(c).Where("user_id = ?", ownerID)
+	if !isOwner && viewerRole != model.UserRoleAdmin {
+		query = query.Where("visibility = ?", model.CollectionVisibilityPublic)
+	}
+	return s.listCollections(c, query, isOwner, page, pageSize)
+}
+
+// GetCollection 获取收藏夹详情，私有收藏夹仅本人和管理员可见
+func (s *CollectionService) GetCollection(c *gin.Context, collectionID, viewerID string, viewerRole model.UserRole) (*CollectionDetailResponse, error) {
+	var collection model.Collection
+	if err := s.db.WithContext(c).First(&collection, "id = ?", collectionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, ErrInternal
+	}
+
+	isOwner := viewerID == collection.UserID
+	if collection.Visibility != model.CollectionVisibilityPublic && !isOwner && viewerRole != model.UserRoleAdmin {
+		// 不区分私有和不存在，避免泄露私有收藏夹
+		return nil, ErrCollectionNotFound
+	}
+	return s.getCollectionDetail(c, &collection, isOwner)
+}
+
+// GetSharedCollection 通过分享链接获取收藏夹详情，私有收藏夹也可访问
+func (s *CollectionService) GetSharedCollection(c *gin.Context, token string) (*CollectionDetailResponse, error) {
+	var collection model.Collection
+	if err := s.db.WithContext(c).First(&collection, "share_token = ?", token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, ErrInternal
+	}
+	return s.getCollectionDetail(c, &collection, false)
+}
+
+// AddItem 向收藏夹添加产品
+func (s *CollectionService) AddItem(c *gin.Context, userID, collectionID string, req *AddCollectionItemRequest) (*CollectionDetailResponse, error) {
+	var collection *model.Collection
+	err := s.db.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		var err error
+		// 锁定收藏夹，保证并发添加时位置不重复
+		collection, err = s.getOwnedCollection(tx.Clauses(clause.Locking{Strength: "UPDATE"}), userID, collectionID)
+		if err != nil {
+			return err
+		}
+
+		var product model.Product
+		if err := tx.Select("id").First(&product, "id = ?", req.ProductID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+
+		var stats struct {
+			Count       int64
+			MaxPosition int
+		}
+		if err := tx.Model(&model.CollectionItem{}).
+			Select("COUNT(*) AS count, COALESCE(MAX(position), 0) AS max_position").
+			Where("collection_id = ?", collection.ID).
+			Scan(&stats).Error; err != nil {
+			return err
+		}
+		if stats.Count >= maxCollectionItems {
+			return ErrCollectionFull
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.CollectionItem{
+			CollectionID: collection.ID,
+			ProductID:    req.ProductID,
+			Position:     stats.MaxPosition + 1,
+			Note:         req.Note,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrCollectionItemExists
+		}
+		return touchCollection(tx, collection)
+	})
+	if err != nil {
+		return nil, collectionError(err)
+	}
+	return s.getCollectionDetail(c, collection, true)
+}
+
+// UpdateItem 更新收藏夹中产品的备注
+func (s *CollectionService) UpdateItem(c *gin.Context, userID, collectionID string, productID uint, req *UpdateCollectionItemRequest) (*CollectionDetailResponse, error) {
+	var collection *model.Collection
+	err := s.db.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		var err error
+		collection, err = s.getOwnedCollection(tx, userID, collectionID)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.CollectionItem{}).
+			Where("collection_id = ? AND product_id = ?", collection.ID, productID).
+			Update("note", req.Note)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrCollectionItemNotFound
+		}
+		return touchCollection(tx, collection)
+	})
+	if err != nil {
+		return nil, collectionError(err)
+	}
+	return s.getCollectionDetail(c, collection, true)
+}
+
+// RemoveItem 从收藏夹移除产品
+func (s *CollectionService) RemoveItem(c *gin.Context, userID, collectionID string, productID uint) error {
+	err := s.db.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		collection, err := s.getOwnedCollection(tx, userID, collectionID)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Where("collection_id = ? AND product_id = ?", collection.ID, productID).
+			Delete(&model.CollectionItem{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrCollectionItemNotFound
+		}
+		return touchCollection(tx, collection)
+	})
+	return collectionError(err)
+}
+
+// ReorderItems 按给定顺序重新排列收藏夹中的产品
+func (s *CollectionService) ReorderItems(c *gin.Context, userID, collectionID string, req *ReorderCollectionItemsRequest) (*CollectionDetailResponse, error) {
+	var collection *model.Collection
+	err := s.db.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		var err error
+		collection, err = s.getOwnedCollection(tx.Clauses(clause.Locking{Strength: "UPDATE"}), userID, collectionID)
+		if err != nil {
+			return err
+		}
+
+		var productIDs []uint
+		if err := tx.Model(&model.CollectionItem{}).
+			Where("collection_id = ?", collection.ID).
+			Pluck("product_id", &productIDs).Error; err != nil {
+			return err
+		}
+
+		// 排序列表必须与收藏夹中的产品一一对应
+		if len(req.ProductIDs) != len(productIDs) {
+			return ErrInvalidCollectionItemsOrder
+		}
+		existing := make(map[uint]bool, len(productIDs))
+		for _, id := range productIDs {
+			existing[id] = true
+		}
+		for _, id := range req.ProductIDs {
+			if !existing[id] {
+				return ErrInvalidCollectionItemsOrder
+			}
+			delete(existing, id)
+		}
+
+		for i, id := range req.ProductIDs {
+			if err := tx.Model(&model.CollectionItem{}).
+				Where("collection_id = ? AND product_id = ?", collection.ID, id).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return touchCollection(tx, collection)
+	})
+	if err != nil {
+		return nil, collectionError(err)
+	}
+	return s.getCollectionDetail(c, collection, true)
+}
+
+// CreateShareLink 生成新的分享令牌，旧的分享链接随之失效
+func (s *CollectionService) CreateShareLink(c *gin.Context, userID, collectionID string) (*CollectionResponse, error) {
+	collection, err := s.getOwnedCollection(s.db.WithContext(c), userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	token := utils.GenerateRandomString(collectionShareTokenLength)
+	if err := s.db.WithContext(c).Model(collection).Update("share_token", token).Error; err != nil {
+		return nil, ErrInternal
+	}
+	collection.ShareToken = &token
+	return s.getCollectionResponse(c, collection, true)
+}
+
+// RevokeShareLink 关闭分享链接
+func (s *CollectionService) RevokeShareLink(c *gin.Context, userID, collectionID string) error {
+	collection, err := s.getOwnedCollection(s.db.WithContext(c), userID, collectionID)
+	if err != nil {
+		return err
+	}
+	if err := s.db.WithContext(c).Model(collection).Update("share_token", nil).Error; err != nil {
+		return ErrInternal
+	}
+	return nil
+}
+
+// getOwnedCollection 获取当前用户创建的收藏夹，他人的收藏夹视为不存在
+func (s *CollectionService) getOwnedCollection(db *gorm.DB, userID, collectionID string) (*model.Collection, error) {
+	var collection model.Collection
+	if err := db.First(&collection, "id = ? AND user_id = ?", collectionID, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, ErrInternal
+	}
+	return &collection, nil
+}
+
+// touchCollection 收藏夹中的产品变化时更新收藏夹的更新时间
+func touchCollection(tx *gorm.DB, collection *model.Collection) error {
+	return tx.Model(collection).Update("updated_at", time.Now()).Error
+}
+
+// collectionError 保留业务错误，其余错误统一为内部错误
+func collectionError(err error) error {
+	switch err {
+	case nil, ErrCollectionNotFound, ErrCollectionItemNotFound, ErrCollectionItemExists,
+		ErrCollectionFull, ErrInvalidCollectionItemsOrder, ErrProductNotFound:
+		return err
+	}
+	return ErrInternal
+}
+
+func (s *CollectionService) listCollections(c *gin.Context, query *gorm.DB, ownerView bool, page, pageSize int) ([]*CollectionResponse, int64, error) {
+	var total int64
+	if err := query.Model(&model.Collection{}).Count(&total).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	var collections []*model.Collection
+	if err := query.Order("updated_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&collections).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	responses, err := s.getCollectionResponses(c, collections, ownerView)
+	if err != nil {
+		return nil, 0, err
+	}
+	return responses, total, nil
+}
+
+func (s *CollectionService) getCollectionResponse(c *gin.Context, collection *model.Collection, ownerView bool) (*CollectionResponse, error) {
+	responses, err := s.getCollectionResponses(c, []*model.Collection{collection}, ownerView)
+	if err != nil {
+		return nil, err
+	}
+	return responses[0], nil
+}
+
+// getCollectionResponses 批量组装收藏夹信息，ownerView 为 true 时返回分享令牌
+func (s *CollectionService) getCollectionResponses(c *gin.Context, collections []*model.Collection, ownerView bool) ([]*CollectionResponse, error) {
+	responses := make([]*CollectionResponse, 0, len(collections))
+	if len(collections) == 0 {
+		return responses, nil
+	}
+
+	collectionIDs := make([]string, len(collections))
+	userIDs := make([]string, len(collections))
+	for i, collection := range collections {
+		collectionIDs[i] = collection.ID
+		userIDs[i] = collection.UserID
+	}
+
+	var counts []struct {
+		CollectionID string
+		Count        int64
+	}
+	if err := s.db.WithContext(c).Model(&model.CollectionItem{}).
+		Select("collection_id, COUNT(*) AS count").
+		Where("collection_id IN ?", collectionIDs).
+		Group("collection_id").
+		Scan(&counts).Error; err != nil {
+		return nil, ErrInternal
+	}
+	countMap := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		countMap[count.CollectionID] = count.Count
+	}
+
+	var owners []model.User
+	if err := s.db.WithContext(c).Select("id", "name", "avatar").Where("id IN ?", userIDs).Find(&owners).Error; err != nil {
+		return nil, ErrInternal
+	}
+	ownerMap := make(map[string]*UserBrief, len(owners))
+	for _, owner := range owners {
+		ownerMap[owner.ID] = &UserBrief{ID: owner.ID, Name: owner.Name, Avatar: owner.Avatar}
+	}
+
+	for _, collection := range collections {
+		response := &CollectionResponse{
+			ID:          collection.ID,
+			Name:        collection.Name,
+			Description: collection.Description,
+			Visibility:  collection.Visibility,
+			ItemCount:   countMap[collection.ID],
+			Owner:       ownerMap[collection.UserID],
+			CreatedAt:   collection.CreatedAt.Format("2006-01-02 15:04:05"),
+			UpdatedAt:   collection.UpdatedAt.Format("2006-01-02 15:04:05"),
+		}
+		if ownerView && collection.ShareToken != nil {
+			response.ShareToken = *collection.ShareToken
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// getCollectionDetail 组装收藏夹详情，产品按位置排序
+func (s *CollectionService) getCollectionDetail(c *gin.Context, collection *model.Collection, ownerView bool) (*CollectionDetailResponse, error) {
+	response, err := s.getCollectionResponse(c, collection, ownerView)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []model.CollectionItem
+	if err := s.db.WithContext(c).
+		Preload("Product").
+		Where("collection_id = ?", collection.ID).
+		Order("position ASC, created_at ASC").
+		Find(&items).Error; err != nil {
+		return nil, ErrInternal
+	}
+
+	detail := &CollectionDetailResponse{
+		CollectionResponse: *response,
+		Items:              make([]*CollectionItemResponse, 0, len(items)),
+	}
+	for i := range items {
+		// 已删除的产品不再展示
+		if items[i].Product.ID == 0 {
+			continue
+		}
+		brief, err := toProductBrief(&items[i].Product)
+		if err != nil {
+			return nil, ErrInternal
+		}
+		detail.Items = append(detail.Items, &CollectionItemResponse{
+			Product:  brief,
+			Note:     items[i].Note,
+			Position: items[i].Position,
+			AddedAt:  items[i].CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return detail, nil
+}
+
+// countProductCollections 批量统计包含产品的公开收藏夹数
+func countProductCollections(db *gorm.DB, productIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(productIDs))
+	if len(productIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ProductID uint
+		Count     int64
+	}
+	if err := db.Model(&model.CollectionItem{}).
+		Select("collection_items.product_id, COUNT(*) AS count").
+		Joins("JOIN collections ON collections.id = collection_items.collection_id").
+		Where("collection_items.product_id IN ? AND collections.visibility = ?", productIDs, model.CollectionVisibilityPublic).
+		Group("collection_items.product_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ProductID] = row.Count
+	}
+	return counts, nil
+}
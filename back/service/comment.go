@@ -2,9 +2,12 @@ package service
 
 import (
 	"beicun/back/model"
+	"beicun/back/utils"
+	"context"
 	"errors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -17,7 +20,7 @@ type CreateCommentRequest struct {
 	ReviewID  string  `json:"reviewId" binding:"required"`
 	Content   string  `json:"content" binding:"required,min=1,max=1000"`
 	ParentID  *string `json:"parentId,omitempty"`
-	ReplyToID *string `json:"replyToId,omitempty"`
+	ReplyToID *string `json:"replyToId,omitempty"` // 被回复的用户，须为父评论或同一父评论下其他回复的作者，否则忽略
 }
 
 type UpdateCommentRequest struct {
@@ -25,7 +28,18 @@ type UpdateCommentRequest struct {
 }
 
 type UpdateCommentStatusRequest struct {
-	Status model.CommentStatus `json:"status" binding:"required,oneof=PENDING PUBLISHED REJECTED"`
+	Status model.CommentStatus `json:"status" binding:"required,oneof=PENDING APPROVED REJECTED"`
+}
+
+// BulkUpdateCommentStatusRequest 批量审核请求
+type BulkUpdateCommentStatusRequest struct {
+	IDs    []string            `json:"ids" binding:"required,min=1,max=100"`
+	Status model.CommentStatus `json:"status" binding:"required,oneof=APPROVED REJECTED"`
+}
+
+// BulkUpdateCommentStatusResponse 批量审核结果
+type BulkUpdateCommentStatusResponse struct {
+	Updated int64 `json:"updated"` // 实际更新的评论数
 }
 
 type CommentResponse struct {
@@ -34,6 +48,11 @@ type CommentResponse struct {
 	Content   string             `json:"content"`
 	Status    model.CommentStatus `json:"status"`
 	Level     int                `json:"level"`
+	HelpfulCount    int          `json:"helpfulCount"`
+	NotHelpfulCount int          `json:"notHelpfulCount"`
+	ModerationNote  string       `json:"moderationNote,omitempty"` // 自动审核说明，仅管理员可见
+	ReplyCount      int64        `json:"replyCount"`               // 可见的直接回复数
+	RepliesCursor   string       `json:"repliesCursor,omitempty"`  // 加载更多回复的游标，为空表示已全部加载
 	CreatedAt string             `json:"createdAt"`
 	UpdatedAt string             `json:"updatedAt"`
 	User      *UserBrief         `json:"user"`
@@ -42,30 +61,46 @@ type CommentResponse struct {
 }
 
 
+// commentEvent 新公开评论的实时事件，客户端据此刷新对应的评论或回复列表
+type commentEvent struct {
+	ID       string  `json:"id"`
+	ReviewID string  `json:"reviewId"`
+	ParentID *string `json:"parentId,omitempty"`
+}
+
 type CommentService struct {
-	db *gorm.DB
+	db                  *gorm.DB
+	moderationService   *ModerationService
+	notificationService *NotificationService
+	realtimeService     *RealtimeService
 }
 
-func NewCommentService(db *gorm.DB) *CommentService {
-	return &CommentService{db: db}
+func NewCommentService(db *gorm.DB, moderationService *ModerationService, notificationService *NotificationService, realtimeService *RealtimeService) *CommentService {
+	return &CommentService{
+		db:                  db,
+		moderationService:   moderationService,
+		notificationService: notificationService,
+		realtimeService:     realtimeService,
+	}
 }
 
 // CreateComment 创建评论
 func (s *CommentService) CreateComment(c *gin.Context, userID string, req *CreateCommentRequest) (*CommentResponse, error) {
-	// 检查测评是否存在
+	// 只能评论已发布的测评
 	var review model.Review
-	if err := s.db.First(&review, "id = ?", req.ReviewID).Error; err != nil {
+	if err := s.db.First(&review, "id = ? AND status = ?", req.ReviewID, model.ReviewStatusPublished).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrReviewNotFound
 		}
 		return nil, ErrInternal
 	}
 
-	// 如果是回复评论，检查父评论是否存在
+	// 如果是回复评论，检查父评论是否存在且属于同一测评
 	var level = 1
+	var replyToID *string
 	if req.ParentID != nil {
 		var parentComment model.Comment
-		if err := s.db.First(&parentComment, "id = ?", req.ParentID).Error; err != nil {
+		if err := s.db.First(&parentComment, "id = ? AND review_id = ?", req.ParentID, req.ReviewID).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return nil, ErrCommentNotFound
 			}
@@ -75,21 +110,33 @@ func (s *CommentService) CreateComment(c *gin.Context, userID string, req *Creat
 		if level > 3 { // 限制评论层级最多为3层
 			return nil, ErrInvalidComment
 		}
+		var err error
+		if replyToID, err = s.resolveReplyTo(&parentComment, req.ReplyToID); err != nil {
+			return nil, ErrInternal
+		}
+	}
+
+	var user model.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, ErrInternal
 	}
+	status, note := s.moderationService.ModerateComment(c.Request.Context(), &user, req.Content)
 
 	comment := &model.Comment{
-		ReviewID:  req.ReviewID,
-		UserID:    userID,
-		ParentID:  req.ParentID,
-		ReplyToID: req.ReplyToID,
-		Content:   req.Content,
-		Status:    model.CommentStatusPending,
-		Level:     level,
+		ReviewID:       req.ReviewID,
+		UserID:         userID,
+		ParentID:       req.ParentID,
+		ReplyToID:      replyToID,
+		Content:        req.Content,
+		Status:         status,
+		Level:          level,
+		ModerationNote: note,
 	}
 
 	if err := s.db.Create(comment).Error; err != nil {
 		return nil, ErrInternal
 	}
+	s.onCommentPublished(c.Request.Context(), comment)
 
 	return s.getCommentResponse(comment)
 }
@@ -104,10 +151,36 @@ func (s *CommentService) UpdateComment(c *gin.Context, id string, userID string,
 		return nil, ErrInternal
 	}
 
-	comment.Content = req.Content
-	if err := s.db.Save(comment).Error; err != nil {
+	// 修改后的内容重新审核
+	remoderated := comment.Content != req.Content
+	if remoderated {
+		var user model.User
+		if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+			return nil, ErrInternal
+		}
+		comment.Content = req.Content
+		comment.Status, comment.ModerationNote = s.moderationService.ModerateComment(c.Request.Context(), &user, req.Content)
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 因举报自动隐藏且尚未处理时，修改不能解除隐藏，新的审核结果在举报驳回后恢复
+		if remoderated {
+			result := tx.Model(&model.ReportCase{}).
+				Where("target_type = ? AND target_id = ? AND status = ? AND hidden = ?",
+					model.ReportTargetComment, comment.ID, model.ReportStatusPending, true).
+				Update("hidden_status", comment.Status)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				comment.Status = model.CommentStatusPending
+			}
+		}
+		return tx.Save(comment).Error
+	})
+	if err != nil {
 		return nil, ErrInternal
 	}
+	s.onCommentPublished(c.Request.Context(), comment)
 
 	return s.getCommentResponse(comment)
 }
@@ -122,12 +195,86 @@ func (s *CommentService) UpdateCommentStatus(c *gin.Context, id string, req *Upd
 		return nil, ErrInternal
 	}
 
+	changed := comment.Status != req.Status
 	comment.Status = req.Status
 	if err := s.db.Save(comment).Error; err != nil {
 		return nil, ErrInternal
 	}
+	s.moderationService.RecordDecision(c.Request.Context(), comment.UserID, comment.Status)
+	s.onCommentPublished(c.Request.Context(), comment)
+	if changed {
+		s.notificationService.NotifyCommentModeration(c.Request.Context(), comment, "")
+	}
 
-	return s.getCommentResponse(comment)
+	response, err := s.getCommentResponse(comment)
+	if err != nil {
+		return nil, err
+	}
+	response.ModerationNote = comment.ModerationNote
+	return response, nil
+}
+
+// BulkUpdateCommentStatus 批量通过或拒绝评论（管理员操作），已处于目标状态的评论不重复处理
+func (s *CommentService) BulkUpdateCommentStatus(c *gin.Context, req *BulkUpdateCommentStatusRequest) (*BulkUpdateCommentStatusResponse, error) {
+	var comments []model.Comment
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id", "review_id", "user_id", "parent_id", "reply_to_id", "content").
+			Where("id IN ? AND status != ?", req.IDs, req.Status).
+			Find(&comments).Error; err != nil {
+			return err
+		}
+		if len(comments) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(comments))
+		for i, comment := range comments {
+			ids[i] = comment.ID
+		}
+		return tx.Model(&model.Comment{}).Where("id IN ?", ids).Update("status", req.Status).Error
+	})
+	if err != nil {
+		return nil, ErrInternal
+	}
+
+	// 每个用户只需根据最新的通过数调整一次信任等级
+	seen := make(map[string]bool)
+	for i := range comments {
+		comment := &comments[i]
+		comment.Status = req.Status
+		s.onCommentPublished(c.Request.Context(), comment)
+		s.notificationService.NotifyCommentModeration(c.Request.Context(), comment, "")
+		if seen[comment.UserID] {
+			continue
+		}
+		seen[comment.UserID] = true
+		s.moderationService.RecordDecision(c.Request.Context(), comment.UserID, req.Status)
+	}
+
+	return &BulkUpdateCommentStatusResponse{Updated: int64(len(comments))}, nil
+}
+
+// resolveReplyTo 校验被回复的用户，只能是父评论的作者或同一父评论下其他回复的作者，否则忽略
+func (s *CommentService) resolveReplyTo(parent *model.Comment, replyToID *string) (*string, error) {
+	if replyToID == nil {
+		return nil, nil
+	}
+	if *replyToID == parent.UserID {
+		return replyToID, nil
+	}
+	if _, err := uuid.Parse(*replyToID); err != nil {
+		return nil, nil
+	}
+	var count int64
+	if err := s.db.Model(&model.Comment{}).
+		Where("parent_id = ? AND user_id = ?", parent.ID, *replyToID).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	return replyToID, nil
 }
 
 // DeleteComment 删除评论
@@ -142,10 +289,14 @@ func (s *CommentService) DeleteComment(c *gin.Context, id string, userID string)
 	return nil
 }
 
-// GetComment 获取评论详情
-func (s *CommentService) GetComment(c *gin.Context, id string) (*CommentResponse, error) {
+// GetComment 获取评论详情，可见范围与评论列表一致，管理员可查看任意状态的评论
+func (s *CommentService) GetComment(c *gin.Context, id string, viewerID string) (*CommentResponse, error) {
+	query := s.db.Preload("User").Preload("ReplyTo")
+	if utils.GetUserRoleFromContext(c) != model.UserRoleAdmin {
+		query = visibleComments(query, viewerID)
+	}
 	comment := &model.Comment{}
-	if err := s.db.Preload("User").Preload("ReplyTo").First(comment, "id = ?", id).Error; err != nil {
+	if err := query.First(comment, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrCommentNotFound
 		}
@@ -155,28 +306,28 @@ func (s *CommentService) GetComment(c *gin.Context, id string) (*CommentResponse
 	return s.getCommentResponse(comment)
 }
 
-// ListComments 获取评论列表
-func (s *CommentService) ListComments(c *gin.Context, reviewSlug string, page, pageSize int, status string) ([]*CommentResponse, int64, error) {
-	var total int64
-	var review model.Review
-	
-	// 先通过 slug 获取 review
-	if err := s.db.Where("slug = ?", reviewSlug).First(&review).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, 0, ErrReviewNotFound
-		}
-		return nil, 0, ErrInternal
-	}
-
-	query := s.db.Model(&model.Comment{}).Where("review_id = ? AND parent_id IS NULL", review.ID)
-
-	if status != "" {
-		query = query.Where("status = ?", status)
-	}
+// ListComments 获取评论列表，只返回已通过的评论和当前用户自己待审核的评论
+// 每条顶级评论预加载部分回复，更多回复通过 ListReplies 按游标加载
+func (s *CommentService) ListComments(c *gin.Context, reviewSlug, viewerID string, page, pageSize int) ([]*CommentResponse, int64, error) {
+	reviewID := s.db.Model(&model.Review{}).Select("id").Where("slug = ? AND status = ?", reviewSlug, model.ReviewStatusPublished)
+	query := visibleComments(s.db.Model(&model.Comment{}), viewerID).
+		Where("review_id = (?) AND parent_id IS NULL", reviewID)
 
+	var total int64
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, ErrInternal
 	}
+	if total == 0 {
+		// 没有评论时再区分测评是否存在
+		var count int64
+		if err := s.db.Model(&model.Review{}).Where("slug = ? AND status = ?", reviewSlug, model.ReviewStatusPublished).Count(&count).Error; err != nil {
+			return nil, 0, ErrInternal
+		}
+		if count == 0 {
+			return nil, 0, ErrReviewNotFound
+		}
+		return []*CommentResponse{}, 0, nil
+	}
 
 	var comments []*model.Comment
 	if err := query.Preload("User").Preload("ReplyTo").
@@ -187,15 +338,17 @@ func (s *CommentService) ListComments(c *gin.Context, reviewSlug string, page, p
 		return nil, 0, ErrInternal
 	}
 
-	// 获取每个顶级评论的回复
 	responses := make([]*CommentResponse, len(comments))
 	for i, comment := range comments {
-		response, err := s.getCommentWithReplies(comment)
+		response, err := s.getCommentResponse(comment)
 		if err != nil {
 			return nil, 0, err
 		}
 		responses[i] = response
 	}
+	if err := s.loadReplies(responses, viewerID, DefaultReplyPreview); err != nil {
+		return nil, 0, err
+	}
 
 	return responses, total, nil
 }
@@ -228,40 +381,37 @@ func (s *CommentService) ListAllComments(c *gin.Context, page, pageSize int, sta
 		if err != nil {
 			return nil, 0, err
 		}
+		response.ModerationNote = comment.ModerationNote
 		responses[i] = response
 	}
 
 	return responses, total, nil
 }
 
-// getCommentWithReplies 获取评论及其回复
-func (s *CommentService) getCommentWithReplies(comment *model.Comment) (*CommentResponse, error) {
-	response, err := s.getCommentResponse(comment)
-	if err != nil {
-		return nil, err
+// onCommentPublished 评论公开后推送给正在浏览该测评的连接，并通知被回复和被提及的用户
+func (s *CommentService) onCommentPublished(ctx context.Context, comment *model.Comment) {
+	if comment.Status != model.CommentStatusApproved {
+		return
 	}
-
-	// 获取回复
-	var replies []*model.Comment
-	if err := s.db.Preload("User").Preload("ReplyTo").
-		Where("parent_id = ?", comment.ID).
-		Order("created_at ASC").
-		Find(&replies).Error; err != nil {
-		return nil, ErrInternal
+	s.notificationService.NotifyCommentPublished(ctx, comment)
+	if s.realtimeService != nil {
+		s.realtimeService.Publish(ctx, RealtimeTopic(RealtimeTopicReview, comment.ReviewID), RealtimeEventComment, &commentEvent{
+			ID:       comment.ID,
+			ReviewID: comment.ReviewID,
+			ParentID: comment.ParentID,
+		})
 	}
+}
 
-	if len(replies) > 0 {
-		response.Replies = make([]*CommentResponse, len(replies))
-		for i, reply := range replies {
-			replyResponse, err := s.getCommentResponse(reply)
-			if err != nil {
-				return nil, err
-			}
-			response.Replies[i] = replyResponse
-		}
+// visibleComments 限定为已发布测评下公开可见的评论，登录用户还能看到自己待审核的评论
+func visibleComments(query *gorm.DB, viewerID string) *gorm.DB {
+	query = query.Where("review_id IN (SELECT id FROM reviews WHERE reviews.status = ? AND reviews.deleted_at IS NULL)",
+		model.ReviewStatusPublished)
+	if viewerID == "" {
+		return query.Where("status = ?", model.CommentStatusApproved)
 	}
-
-	return response, nil
+	return query.Where("(status = ? OR (status = ? AND user_id = ?))",
+		model.CommentStatusApproved, model.CommentStatusPending, viewerID)
 }
 
 // getCommentResponse 转换为响应结构
@@ -272,6 +422,8 @@ func (s *CommentService) getCommentResponse(comment *model.Comment) (*CommentRes
 		Content:   comment.Content,
 		Status:    comment.Status,
 		Level:     comment.Level,
+		HelpfulCount:    comment.HelpfulCount,
+		NotHelpfulCount: comment.NotHelpfulCount,
 		CreatedAt: comment.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt: comment.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
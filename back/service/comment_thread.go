@@ -0,0 +1,188 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"beicun/back/model"
+)
+
+// 回复加载数量
+const (
+	DefaultReplyPreview  = 3  // 每条评论随列表预加载的回复数
+	DefaultRepliesPage   = 10 // 加载更多回复的默认数量
+	MaxRepliesPage       = 50
+	maxCommentLevel      = 3 // 评论最多嵌套层级
+	replyCursorSeparator = "|"
+)
+
+var ErrInvalidCursor = errors.New("无效的游标")
+
+// CommentRepliesPage 回复分页结果
+type CommentRepliesPage struct {
+	Replies    []*CommentResponse `json:"replies"`
+	ReplyCount int64              `json:"replyCount"`           // 父评论可见的回复总数
+	NextCursor string             `json:"nextCursor,omitempty"` // 为空表示没有更多回复
+}
+
+// replyRow 回复预加载查询结果
+type replyRow struct {
+	ID       string
+	ParentID string
+	Total    int64
+}
+
+// ListReplies 以游标分页加载评论的回复，每条回复同样预加载其下的部分回复
+func (s *CommentService) ListReplies(c *gin.Context, parentID, viewerID, cursor string, limit int) (*CommentRepliesPage, error) {
+	if limit <= 0 || limit > MaxRepliesPage {
+		limit = DefaultRepliesPage
+	}
+
+	var parent model.Comment
+	if err := visibleComments(s.db, viewerID).Select("id").First(&parent, "id = ?", parentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, ErrInternal
+	}
+
+	query := visibleComments(s.db.Model(&model.Comment{}), viewerID).Where("parent_id = ?", parentID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, ErrInternal
+	}
+
+	if cursor != "" {
+		createdAt, id, err := decodeReplyCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("(created_at, id) > (?, ?)", createdAt, id)
+	}
+
+	var comments []*model.Comment
+	if err := query.Preload("User").Preload("ReplyTo").
+		Order("created_at ASC, id ASC").
+		Limit(limit + 1).
+		Find(&comments).Error; err != nil {
+		return nil, ErrInternal
+	}
+
+	page := &CommentRepliesPage{ReplyCount: total}
+	if len(comments) > limit {
+		comments = comments[:limit]
+		page.NextCursor = encodeReplyCursor(comments[limit-1])
+	}
+
+	page.Replies = make([]*CommentResponse, len(comments))
+	for i, comment := range comments {
+		response, err := s.getCommentResponse(comment)
+		if err != nil {
+			return nil, err
+		}
+		page.Replies[i] = response
+	}
+	if err := s.loadReplies(page.Replies, viewerID, DefaultReplyPreview); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// loadReplies 为一批评论逐层预加载回复，每层只查询一次，每条评论最多加载 limit 条回复
+// 并填充各节点的回复总数，回复未加载完的节点返回加载更多的游标
+func (s *CommentService) loadReplies(parents []*CommentResponse, viewerID string, limit int) error {
+	for len(parents) > 0 {
+		byID := make(map[string]*CommentResponse, len(parents))
+		ids := make([]string, 0, len(parents))
+		for _, parent := range parents {
+			if parent.Level >= maxCommentLevel {
+				continue
+			}
+			byID[parent.ID] = parent
+			ids = append(ids, parent.ID)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		ranked := visibleComments(s.db.Model(&model.Comment{}), viewerID).
+			Select(`id, parent_id,
+				ROW_NUMBER() OVER (PARTITION BY parent_id ORDER BY created_at ASC, id ASC) AS rn,
+				COUNT(*) OVER (PARTITION BY parent_id) AS total`).
+			Where("parent_id IN ?", ids)
+
+		var rows []replyRow
+		if err := s.db.Table("(?) AS ranked", ranked).
+			Select("id", "parent_id", "total").
+			Where("rn <= ?", limit).
+			Scan(&rows).Error; err != nil {
+			return ErrInternal
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		replyIDs := make([]string, len(rows))
+		for i, row := range rows {
+			replyIDs[i] = row.ID
+			byID[row.ParentID].ReplyCount = row.Total
+		}
+
+		var replies []*model.Comment
+		if err := s.db.Preload("User").Preload("ReplyTo").
+			Where("id IN ?", replyIDs).
+			Order("created_at ASC, id ASC").
+			Find(&replies).Error; err != nil {
+			return ErrInternal
+		}
+
+		next := make([]*CommentResponse, 0, len(replies))
+		for _, reply := range replies {
+			parent := byID[*reply.ParentID]
+			response, err := s.getCommentResponse(reply)
+			if err != nil {
+				return err
+			}
+			parent.Replies = append(parent.Replies, response)
+			if int64(len(parent.Replies)) < parent.ReplyCount && len(parent.Replies) == limit {
+				parent.RepliesCursor = encodeReplyCursor(reply)
+			}
+			next = append(next, response)
+		}
+		parents = next
+	}
+	return nil
+}
+
+// encodeReplyCursor 以回复的创建时间和ID生成游标
+func encodeReplyCursor(comment *model.Comment) string {
+	raw := comment.CreatedAt.UTC().Format(time.RFC3339Nano) + replyCursorSeparator + comment.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeReplyCursor 解析游标，ID 不是合法 UUID 时同样视为无效游标，避免数据库报错
+func decodeReplyCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	createdAt, id, ok := strings.Cut(string(raw), replyCursorSeparator)
+	if !ok {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	return t, id, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"beicun/back/model"
+)
+
+func TestReplyCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		createdAt time.Time
+	}{
+		{name: "utc", createdAt: time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)},
+		{name: "nanoseconds kept", createdAt: time.Date(2024, 5, 1, 8, 30, 0, 123456789, time.UTC)},
+		{name: "local time normalized", createdAt: time.Date(2024, 5, 1, 16, 30, 0, 0, time.FixedZone("CST", 8*3600))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment := &model.Comment{ID: "6f1c2a8e-3b4d-4e5f-8a9b-0c1d2e3f4a5b", CreatedAt: tt.createdAt}
+			createdAt, id, err := decodeReplyCursor(encodeReplyCursor(comment))
+			if err != nil {
+				t.Fatalf("decodeReplyCursor() error = %v", err)
+			}
+			if !createdAt.Equal(tt.createdAt) {
+				t.Errorf("createdAt = %v, want %v", createdAt, tt.createdAt)
+			}
+			if id != comment.ID {
+				t.Errorf("id = %q, want %q", id, comment.ID)
+			}
+		})
+	}
+}
+
+func TestDecodeReplyCursorInvalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "empty", cursor: ""},
+		{name: "not base64", cursor: "!!!"},
+		{name: "missing separator", cursor: encode("2024-05-01T08:30:00Z")},
+		{name: "missing id", cursor: encode("2024-05-01T08:30:00Z|")},
+		{name: "id not uuid", cursor: encode("2024-05-01T08:30:00Z|abc")},
+		{name: "bad time", cursor: encode("yesterday|6f1c2a8e-3b4d-4e5f-8a9b-0c1d2e3f4a5b")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := decodeReplyCursor(tt.cursor); err != ErrInvalidCursor {
+				t.Errorf("decodeReplyCursor(%q) error = %v, want %v", tt.cursor, err, ErrInvalidCursor)
+			}
+		})
+	}
+}
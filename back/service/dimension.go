@@ -0,0 +1,200 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"beicun/back/cache"
+	"beicun/back/model"
+)
+
+var (
+	ErrDimensionNotFound = errors.New("评分维度不存在")
+	ErrDimensionExists   = errors.New("评分维度已存在")
+	ErrInvalidScore      = errors.New("维度评分必须在 0 到 10 之间")
+)
+
+type CreateDimensionRequest struct {
+	Key         string `json:"key" binding:"required,min=1,max=50"`
+	Name        string `json:"name" binding:"required,min=1,max=50"`
+	Description string `json:"description" binding:"max=1000"`
+	SortOrder   int    `json:"sortOrder"`
+}
+
+type UpdateDimensionRequest struct {
+	Name        string `json:"name" binding:"omitempty,min=1,max=50"`
+	Description string `json:"description" binding:"max=1000"`
+	SortOrder   *int   `json:"sortOrder"`
+}
+
+// ReviewScoreInput 测评维度评分
+type ReviewScoreInput struct {
+	DimensionID string  `json:"dimensionId" binding:"required,uuid"`
+	Score       float64 `json:"score"`
+}
+
+// ReviewScoreResponse 测评维度评分响应
+type ReviewScoreResponse struct {
+	DimensionID string  `json:"dimensionId"`
+	Key         string  `json:"key"`
+	Name        string  `json:"name"`
+	Score       float64 `json:"score"`
+}
+
+// ProductRadar 产品维度评分雷达图数据
+type ProductRadar struct {
+	ProductID     uint                  `json:"productId"`
+	UtilityTypeID string                `json:"utilityTypeId"`
+	Dimensions    []cache.DimensionStat `json:"dimensions"`
+}
+
+type ScoreDimensionService struct {
+	db *gorm.DB
+}
+
+func NewScoreDimensionService(db *gorm.DB) *ScoreDimensionService {
+	return &ScoreDimensionService{db: db}
+}
+
+// ListDimensions 获取器具类型的评分维度
+func (s *ScoreDimensionService) ListDimensions(c *gin.Context, utilityTypeID string) ([]*model.ScoreDimension, error) {
+	if err := s.db.First(&model.UtilityType{}, "id = ?", utilityTypeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTypeNotFound
+		}
+		return nil, err
+	}
+
+	var dimensions []*model.ScoreDimension
+	if err := s.db.Where("utility_type_id = ?", utilityTypeID).
+		Order("sort_order ASC, created_at ASC").
+		Find(&dimensions).Error; err != nil {
+		return nil, err
+	}
+	return dimensions, nil
+}
+
+// CreateDimension 创建评分维度
+func (s *ScoreDimensionService) CreateDimension(c *gin.Context, utilityTypeID string, req *CreateDimensionRequest) (*model.ScoreDimension, error) {
+	if err := s.db.First(&model.UtilityType{}, "id = ?", utilityTypeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTypeNotFound
+		}
+		return nil, err
+	}
+
+	var count int64
+	if err := s.db.Model(&model.ScoreDimension{}).
+		Where("utility_type_id = ? AND key = ?", utilityTypeID, req.Key).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrDimensionExists
+	}
+
+	dimension := &model.ScoreDimension{
+		UtilityTypeID: utilityTypeID,
+		Key:           req.Key,
+		Name:          req.Name,
+		Description:   req.Description,
+		SortOrder:     req.SortOrder,
+	}
+	if err := s.db.Create(dimension).Error; err != nil {
+		return nil, err
+	}
+	return dimension, nil
+}
+
+// UpdateDimension 更新评分维度
+func (s *ScoreDimensionService) UpdateDimension(c *gin.Context, utilityTypeID, id string, req *UpdateDimensionRequest) (*model.ScoreDimension, error) {
+	dimension := &model.ScoreDimension{}
+	if err := s.db.First(dimension, "id = ? AND utility_type_id = ?", id, utilityTypeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDimensionNotFound
+		}
+		return nil, err
+	}
+
+	if req.Name != "" {
+		dimension.Name = req.Name
+	}
+	if req.Description != "" {
+		dimension.Description = req.Description
+	}
+	if req.SortOrder != nil {
+		dimension.SortOrder = *req.SortOrder
+	}
+
+	if err := s.db.Save(dimension).Error; err != nil {
+		return nil, err
+	}
+	return dimension, nil
+}
+
+// DeleteDimension 删除评分维度，相关测评评分一并删除
+func (s *ScoreDimensionService) DeleteDimension(c *gin.Context, utilityTypeID, id string) error {
+	result := s.db.Delete(&model.ScoreDimension{}, "id = ? AND utility_type_id = ?", id, utilityTypeID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDimensionNotFound
+	}
+	return nil
+}
+
+// GetProductRadar 获取产品各维度的平均评分
+func (s *ScoreDimensionService) GetProductRadar(c *gin.Context, productID uint) (*ProductRadar, error) {
+	var product model.Product
+	if err := s.db.Select("id", "utility_type_id").First(&product, "id = ?", productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	dimensions, err := queryDimensionStats(s.db, product.ID, product.UtilityTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProductRadar{
+		ProductID:     product.ID,
+		UtilityTypeID: product.UtilityTypeID,
+		Dimensions:    dimensions,
+	}, nil
+}
+
+// queryDimensionStats 统计产品在器具类型各维度上的平均评分，只计算已发布的测评
+func queryDimensionStats(db *gorm.DB, productID uint, utilityTypeID string) ([]cache.DimensionStat, error) {
+	stats := make([]cache.DimensionStat, 0)
+	err := db.Raw(`
+		SELECT
+			d.id AS dimension_id,
+			d.key,
+			d.name,
+			COALESCE(ROUND(AVG(s.score)::numeric, 1), 0) AS average,
+			COUNT(s.score) AS count
+		FROM score_dimensions d
+		LEFT JOIN (
+			SELECT rs.dimension_id, rs.score
+			FROM review_scores rs
+			JOIN reviews r ON r.id = rs.review_id
+			WHERE r.product_id = ? AND r.status = ? AND r.deleted_at IS NULL
+		) s ON s.dimension_id = d.id
+		WHERE d.utility_type_id = ?
+		GROUP BY d.id, d.key, d.name, d.sort_order
+		ORDER BY d.sort_order ASC
+	`, productID, model.ReviewStatusPublished, utilityTypeID).Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range stats {
+		stats[i].Max = model.MaxDimensionScore
+	}
+	return stats, nil
+}
@@ -2,10 +2,13 @@ package service
 
 import (
 	"beicun/back/config"
+	"beicun/back/model"
 	"crypto/tls"
 	"fmt"
+	"html"
 	"github.com/wneessen/go-mail"
 	"log"
+	"strings"
 	"time"
 )
 
@@ -127,3 +130,111 @@ func (s *EmailService) SendVerificationCode(to, code, codeType string) error {
 	content := fmt.Sprintf(template, code)
 	return s.SendEmail([]string{to}, subject, content)
 }
+
+// SendReviewModerationResult 发送测评投稿审核结果邮件
+func (s *EmailService) SendReviewModerationResult(to, title string, status model.ReviewStatus, note string) error {
+	var subject, template string
+
+	switch status {
+	case model.ReviewStatusPublished:
+		subject = "您的测评投稿已通过审核"
+		template = `
+		<div style="max-width: 600px; margin: 0 auto; padding: 20px; font-family: Arial, sans-serif;">
+			<h2 style="color: #333;">投稿已发布</h2>
+			<p>您投稿的测评《%s》已通过审核并发布，感谢您的分享！</p>
+			<p style="color: #666; font-size: 14px;">%s</p>
+		</div>`
+	case model.ReviewStatusChangesRequested:
+		subject = "您的测评投稿需要修改"
+		template = `
+		<div style="max-width: 600px; margin: 0 auto; padding: 20px; font-family: Arial, sans-serif;">
+			<h2 style="color: #333;">投稿需要修改</h2>
+			<p>您投稿的测评《%s》需要修改后重新提交。</p>
+			<p style="color: #666; font-size: 14px;">审核意见：%s</p>
+		</div>`
+	case model.ReviewStatusRejected:
+		subject = "您的测评投稿未通过审核"
+		template = `
+		<div style="max-width: 600px; margin: 0 auto; padding: 20px; font-family: Arial, sans-serif;">
+			<h2 style="color: #333;">投稿未通过</h2>
+			<p>很遗憾，您投稿的测评《%s》未通过审核。</p>
+			<p style="color: #666; font-size: 14px;">审核意见：%s</p>
+		</div>`
+	default:
+		return fmt.Errorf("unsupported review status: %s", status)
+	}
+
+	content := fmt.Sprintf(template, html.EscapeString(title), html.EscapeString(note))
+	return s.SendEmail([]string{to}, subject, content)
+}
+
+// SendNotification 发送单条通知邮件
+func (s *EmailService) SendNotification(to string, notification *model.Notification) error {
+	template := `
+		<div style="max-width: 600px; margin: 0 auto; padding: 20px; font-family: Arial, sans-serif;">
+			<h2 style="color: #333;">%s</h2>
+			<p style="white-space: pre-line;">%s</p>
+			<p style="color: #666; font-size: 14px;">如不希望接收此类邮件，可在通知设置中修改通知方式。</p>
+		</div>`
+
+	content := fmt.Sprintf(template, html.EscapeString(notification.Title), html.EscapeString(notification.Content))
+	return s.SendEmail([]string{to}, notification.Title, content)
+}
+
+// SendNotificationDigest 发送未读通知摘要邮件，total 为未读通知总数
+func (s *EmailService) SendNotificationDigest(to, name string, notifications []*model.Notification, total int) error {
+	var items strings.Builder
+	for _, n := range notifications {
+		items.WriteString(fmt.Sprintf(`
+			<li style="margin-bottom: 12px;">
+				<strong>%s</strong>
+				<p style="color: #666; font-size: 14px; margin: 4px 0;">%s</p>
+			</li>`, html.EscapeString(n.Title), html.EscapeString(n.Content)))
+	}
+
+	more := ""
+	if total > len(notifications) {
+		more = fmt.Sprintf(`<p style="color: #666; font-size: 14px;">还有 %d 条未读通知，请登录查看。</p>`, total-len(notifications))
+	}
+
+	subject := fmt.Sprintf("您有 %d 条未读通知", total)
+	template := `
+		<div style="max-width: 600px; margin: 0 auto; padding: 20px; font-family: Arial, sans-serif;">
+			<h2 style="color: #333;">%s，您好</h2>
+			<p>以下是您最近的未读通知：</p>
+			<ul style="padding-left: 20px;">%s
+			</ul>
+			%s
+			<p style="color: #666; font-size: 14px;">如不希望接收此类邮件，可在通知设置中修改通知方式。</p>
+		</div>`
+
+	content := fmt.Sprintf(template, html.EscapeString(name), items.String(), more)
+	return s.SendEmail([]string{to}, subject, content)
+}
+
+// SendDataExportReady 发送个人数据导出完成邮件
+func (s *EmailService) SendDataExportReady(to, name string, expiresAt time.Time) error {
+	template := `
+		<div style="max-width: 600px; margin: 0 auto; padding: 20px; font-family: Arial, sans-serif;">
+			<h2 style="color: #333;">%s，您好</h2>
+			<p>您申请导出的个人数据已打包完成，请登录后在账号设置中下载。</p>
+			<p style="color: #666; font-size: 14px;">下载链接有效期至 %s，过期后文件将被删除。</p>
+		</div>`
+
+	content := fmt.Sprintf(template, html.EscapeString(name), expiresAt.Format("2006-01-02 15:04:05"))
+	return s.SendEmail([]string{to}, "您的个人数据导出已完成", content)
+}
+
+// SendAccountDeletionScheduled 发送账号注销申请确认邮件
+func (s *EmailService) SendAccountDeletionScheduled(to, name string, scheduledAt time.Time) error {
+	template := `
+		<div style="max-width: 600px; margin: 0 auto; padding: 20px; font-family: Arial, sans-serif;">
+			<h2 style="color: #333;">%s，您好</h2>
+			<p>我们已收到您的账号注销申请，账号将于 %s 注销。</p>
+			<p>注销后您的个人信息将被删除，发布过的测评和评论会保留并显示为已注销用户。</p>
+			<p style="color: #666; font-size: 14px;">如果这不是您本人的操作，或您改变了主意，请在此之前登录并撤销注销申请。</p>
+		</div>`
+
+	content := fmt.Sprintf(template, html.EscapeString(name), scheduledAt.Format("2006-01-02 15:04:05"))
+	return s.SendEmail([]string{to}, "账号注销申请确认", content)
+}
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"beicun/back/model"
+)
+
+var (
+	ErrInvalidFollowTarget = errors.New("无效的关注对象类型")
+	ErrFollowTargetMissing = errors.New("关注对象不存在")
+	ErrFollowSelf          = errors.New("不能关注自己")
+)
+
+// FeedItemType 动态类型
+type FeedItemType string
+
+const (
+	FeedItemReview      FeedItemType = "review"       // 关注的作者发布或关注的产品有新测评
+	FeedItemProduct     FeedItemType = "product"      // 关注的品牌上架新产品
+	FeedItemPriceChange FeedItemType = "price_change" // 关注的产品价格变动
+)
+
+// BrandBrief 品牌简要信息
+type BrandBrief struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+	Logo string `json:"logo"`
+}
+
+// FollowResponse 关注记录
+type FollowResponse struct {
+	TargetType model.FollowTargetType `json:"targetType"`
+	TargetID   string                 `json:"targetId"`
+	Brand      *BrandBrief            `json:"brand,omitempty"`
+	Author     *UserBrief             `json:"author,omitempty"`
+	Product    *ProductBrief          `json:"product,omitempty"`
+	CreatedAt  string                 `json:"createdAt"`
+}
+
+// FeedReview 动态中的测评
+type FeedReview struct {
+	ID     string     `json:"id"`
+	Title  string     `json:"title"`
+	Slug   string     `json:"slug"`
+	Cover  string     `json:"cover"`
+	Author *UserBrief `json:"author"`
+}
+
+// FeedPriceChange 动态中的价格变动
+type FeedPriceChange struct {
+	OldPrice float64 `json:"oldPrice"`
+	NewPrice float64 `json:"newPrice"`
+}
+
+// FeedItem 个性化动态条目，根据类型填充对应字段
+type FeedItem struct {
+	Type        FeedItemType     `json:"type"`
+	ID          string           `json:"id"`
+	OccurredAt  string           `json:"occurredAt"`
+	Review      *FeedReview      `json:"review,omitempty"`
+	Product     *ProductBrief    `json:"product,omitempty"`
+	Brand       *BrandBrief      `json:"brand,omitempty"`
+	PriceChange *FeedPriceChange `json:"priceChange,omitempty"`
+}
+
+// feedRow 动态合并查询的结果
+type feedRow struct {
+	Kind       FeedItemType
+	ItemID     string
+	OccurredAt time.Time
+}
+
+type FollowService struct {
+	db *gorm.DB
+}
+
+func NewFollowService(db *gorm.DB) *FollowService {
+	return &FollowService{db: db}
+}
+
+// Follow 关注品牌、作者或产品，重复关注不报错
+func (s *FollowService) Follow(c *gin.Context, userID string, targetType model.FollowTargetType, targetID string) (*FollowResponse, error) {
+	if !targetType.IsValid() {
+		return nil, ErrInvalidFollowTarget
+	}
+	if targetType == model.FollowTargetAuthor && targetID == userID {
+		return nil, ErrFollowSelf
+	}
+	if err := s.checkTarget(targetType, targetID); err != nil {
+		return nil, err
+	}
+
+	follow := &model.Follow{
+		UserID:     userID,
+		TargetType: targetType,
+		TargetID:   targetID,
+	}
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(follow).Error; err != nil {
+		return nil, ErrInternal
+	}
+	if err := s.db.First(follow, "user_id = ? AND target_type = ? AND target_id = ?", userID, targetType, targetID).Error; err != nil {
+		return nil, ErrInternal
+	}
+
+	responses, err := s.getFollowResponses([]*model.Follow{follow})
+	if err != nil {
+		return nil, err
+	}
+	return responses[0], nil
+}
+
+// Unfollow 取消关注，未关注时不报错
+func (s *FollowService) Unfollow(c *gin.Context, userID string, targetType model.FollowTargetType, targetID string) error {
+	if !targetType.IsValid() {
+		return ErrInvalidFollowTarget
+	}
+	if err := s.db.Where("user_id = ? AND target_type = ? AND target_id = ?", userID, targetType, targetID).
+		Delete(&model.Follow{}).Error; err != nil {
+		return ErrInternal
+	}
+	return nil
+}
+
+// checkTarget 检查关注对象是否存在，已封禁和已注销的用户不能被关注
+func (s *FollowService) checkTarget(targetType model.FollowTargetType, targetID string) error {
+	var query *gorm.DB
+	switch targetType {
+	case model.FollowTargetBrand:
+		query = s.db.Model(&model.Brand{}).Where("id = ?", targetID)
+	case model.FollowTargetAuthor:
+		query = s.db.Model(&model.User{}).Where("id = ? AND status NOT IN ?", targetID, model.HiddenUserStatuses)
+	case model.FollowTargetProduct:
+		if _, err := strconv.ParseUint(targetID, 10, 64); err != nil {
+			return ErrFollowTargetMissing
+		}
+		query = s.db.Model(&model.Product{}).Where("id = ?", targetID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return ErrFollowTargetMissing
+	}
+	if count == 0 {
+		return ErrFollowTargetMissing
+	}
+	return nil
+}
+
+// ListFollows 获取用户的关注列表，可按类型过滤，最近关注的在前
+func (s *FollowService) ListFollows(c *gin.Context, userID, targetType string, page, pageSize int) ([]*FollowResponse, int64, error) {
+	query := s.db.Model(&model.Follow{}).Where("user_id = ?", userID)
+	if targetType != "" {
+		if !model.FollowTargetType(targetType).IsValid() {
+			return nil, 0, ErrInvalidFollowTarget
+		}
+		query = query.Where("target_type = ?", targetType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	var follows []*model.Follow
+	if err := query.Order("created_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&follows).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	responses, err := s.getFollowResponses(follows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return responses, total, nil
+}
+
+// getFollowResponses 按类型批量加载关注对象的简要信息，已删除的对象只返回ID
+func (s *FollowService) getFollowResponses(follows []*model.Follow) ([]*FollowResponse, error) {
+	ids := make(map[model.FollowTargetType][]string)
+	for _, follow := range follows {
+		ids[follow.TargetType] = append(ids[follow.TargetType], follow.TargetID)
+	}
+
+	brands, err := s.loadBrandBriefs(ids[model.FollowTargetBrand])
+	if err != nil {
+		return nil, err
+	}
+	authors := make(map[string]*UserBrief)
+	if len(ids[model.FollowTargetAuthor]) > 0 {
+		var users []model.User
+		if err := s.db.Select("id", "name", "avatar").Where("id IN ?", ids[model.FollowTargetAuthor]).Find(&users).Error; err != nil {
+			return nil, ErrInternal
+		}
+		for _, user := range users {
+			authors[user.ID] = &UserBrief{ID: user.ID, Name: user.Name, Avatar: user.Avatar}
+		}
+	}
+	products := make(map[string]*ProductBrief)
+	if len(ids[model.FollowTargetProduct]) > 0 {
+		var list []*model.Product
+		if err := s.db.Where("id IN ?", ids[model.FollowTargetProduct]).Find(&list).Error; err != nil {
+			return nil, ErrInternal
+		}
+		for _, product := range list {
+			brief, err := toProductBrief(product)
+			if err != nil {
+				return nil, ErrInternal
+			}
+			products[strconv.FormatUint(uint64(product.ID), 10)] = brief
+		}
+	}
+
+	responses := make([]*FollowResponse, len(follows))
+	for i, follow := range follows {
+		response := &FollowResponse{
+			TargetType: follow.TargetType,
+			TargetID:   follow.TargetID,
+			CreatedAt:  follow.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+		switch follow.TargetType {
+		case model.FollowTargetBrand:
+			response.Brand = brands[follow.TargetID]
+		case model.FollowTargetAuthor:
+			response.Author = authors[follow.TargetID]
+		case model.FollowTargetProduct:
+			response.Product = products[follow.TargetID]
+		}
+		responses[i] = response
+	}
+	return responses, nil
+}
+
+func (s *FollowService) loadBrandBriefs(ids []string) (map[string]*BrandBrief, error) {
+	briefs := make(map[string]*BrandBrief)
+	if len(ids) == 0 {
+		return briefs, nil
+	}
+	var brands []model.Brand
+	if err := s.db.Select("id", "name", "slug", "logo").Where("id IN ?", ids).Find(&brands).Error; err != nil {
+		return nil, ErrInternal
+	}
+	for _, brand := range brands {
+		briefs[brand.ID] = &BrandBrief{ID: brand.ID, Name: brand.Name, Slug: brand.Slug, Logo: brand.Logo}
+	}
+	return briefs, nil
+}
+
+// GetFeed 获取个性化动态：关注的作者或产品的新测评、关注的品牌的新产品、关注的产品的价格变动，按时间倒序分页
+func (s *FollowService) GetFeed(c *gin.Context, userID string, page, pageSize int) ([]*FeedItem, int64, error) {
+	// 关注对象ID统一以字符串保存，在子查询中转换为目标列的类型，以便使用目标表的索引
+	followed := func(targetType model.FollowTargetType, idType string) *gorm.DB {
+		return s.db.Model(&model.Follow{}).Select("target_id::"+idType).
+			Where("user_id = ? AND target_type = ?", userID, targetType)
+	}
+
+	reviews := s.db.Model(&model.Review{}).
+		Select("'review' AS kind, id::text AS item_id, published_at AS occurred_at").
+		Where("status = ? AND published_at IS NOT NULL", model.ReviewStatusPublished).
+		Where(s.db.Where("user_id IN (?)", followed(model.FollowTargetAuthor, "uuid")).
+			Or("product_id IN (?)", followed(model.FollowTargetProduct, "bigint")))
+	products := s.db.Model(&model.Product{}).
+		Select("'product' AS kind, id::text AS item_id, created_at AS occurred_at").
+		Where("brand_id IN (?)", followed(model.FollowTargetBrand, "uuid"))
+	priceChanges := s.db.Model(&model.ProductPriceChange{}).
+		Select("'price_change' AS kind, id::text AS item_id, created_at AS occurred_at").
+		Where("product_id IN (?)", followed(model.FollowTargetProduct, "bigint")).
+		Where("product_id IN (?)", s.db.Model(&model.Product{}).Select("id"))
+
+	feed := s.db.Table("(? UNION ALL ? UNION ALL ?) AS feed", reviews, products, priceChanges)
+
+	var total int64
+	if err := feed.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	var rows []feedRow
+	if err := feed.Select("kind", "item_id", "occurred_at").
+		Order("occurred_at DESC, item_id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	items, err := s.getFeedItems(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// getFeedItems 按类型批量加载动态条目的详情
+func (s *FollowService) getFeedItems(rows []feedRow) ([]*FeedItem, error) {
+	ids := make(map[FeedItemType][]string)
+	for _, row := range rows {
+		ids[row.Kind] = append(ids[row.Kind], row.ItemID)
+	}
+
+	reviews := make(map[string]*model.Review)
+	if len(ids[FeedItemReview]) > 0 {
+		var list []*model.Review
+		if err := s.db.Preload("Author").Preload("Product").
+			Where("id IN ?", ids[FeedItemReview]).
+			Find(&list).Error; err != nil {
+			return nil, ErrInternal
+		}
+		for _, review := range list {
+			reviews[review.ID] = review
+		}
+	}
+
+	products := make(map[string]*model.Product)
+	if len(ids[FeedItemProduct]) > 0 {
+		var list []*model.Product
+		if err := s.db.Preload("Brand").Where("id IN ?", ids[FeedItemProduct]).Find(&list).Error; err != nil {
+			return nil, ErrInternal
+		}
+		for _, product := range list {
+			products[strconv.FormatUint(uint64(product.ID), 10)] = product
+		}
+	}
+
+	priceChanges := make(map[string]*model.ProductPriceChange)
+	if len(ids[FeedItemPriceChange]) > 0 {
+		var list []*model.ProductPriceChange
+		if err := s.db.Preload("Product").Where("id IN ?", ids[FeedItemPriceChange]).Find(&list).Error; err != nil {
+			return nil, ErrInternal
+		}
+		for _, change := range list {
+			priceChanges[change.ID] = change
+		}
+	}
+
+	items := make([]*FeedItem, 0, len(rows))
+	for _, row := range rows {
+		item := &FeedItem{
+			Type:       row.Kind,
+			ID:         row.ItemID,
+			OccurredAt: row.OccurredAt.Format("2006-01-02 15:04:05"),
+		}
+
+		var product *model.Product
+		switch row.Kind {
+		case FeedItemReview:
+			review, ok := reviews[row.ItemID]
+			if !ok {
+				continue
+			}
+			item.Review = &FeedReview{
+				ID:    review.ID,
+				Title: review.Title,
+				Slug:  review.Slug,
+				Cover: review.Cover,
+				Author: &UserBrief{
+					ID:     review.Author.ID,
+					Name:   review.Author.Name,
+					Avatar: review.Author.Avatar,
+				},
+			}
+			product = review.Product
+		case FeedItemProduct:
+			product = products[row.ItemID]
+			if product == nil {
+				continue
+			}
+			item.Brand = &BrandBrief{
+				ID:   product.Brand.ID,
+				Name: product.Brand.Name,
+				Slug: product.Brand.Slug,
+				Logo: product.Brand.Logo,
+			}
+		case FeedItemPriceChange:
+			change, ok := priceChanges[row.ItemID]
+			if !ok || change.Product == nil {
+				continue
+			}
+			item.PriceChange = &FeedPriceChange{OldPrice: change.OldPrice, NewPrice: change.NewPrice}
+			product = change.Product
+		}
+
+		if product != nil {
+			brief, err := toProductBrief(product)
+			if err != nil {
+				return nil, ErrInternal
+			}
+			item.Product = brief
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
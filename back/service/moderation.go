@@ -0,0 +1,281 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"beicun/back/config"
+	"beicun/back/model"
+	"beicun/back/utils"
+)
+
+// ModerationVerdict 审核结论，严重程度依次递增
+type ModerationVerdict int
+
+const (
+	ModerationApprove ModerationVerdict = iota // 通过
+	ModerationReview                           // 转人工审核
+	ModerationReject                           // 拒绝
+)
+
+// ModerationInput 待审核内容
+type ModerationInput struct {
+	UserID  string // 发布者ID
+	Content string // 内容
+}
+
+// ModerationResult 审核结果
+type ModerationResult struct {
+	Verdict ModerationVerdict
+	Reasons []string // 命中的规则说明
+}
+
+// ModerationProvider 内容审核服务，可接入外部分类服务
+type ModerationProvider interface {
+	// Name 服务名称，记录在审核说明中
+	Name() string
+	// Moderate 审核内容
+	Moderate(ctx context.Context, input *ModerationInput) (*ModerationResult, error)
+}
+
+type ModerationService struct {
+	db        *gorm.DB
+	cfg       *config.Config
+	providers []ModerationProvider
+}
+
+func NewModerationService(db *gorm.DB, cfg *config.Config) *ModerationService {
+	return &ModerationService{
+		db:  db,
+		cfg: cfg,
+		providers: []ModerationProvider{
+			NewSensitiveWordProvider(cfg.Moderation.SensitiveWordsFile),
+			NewSpamProvider(db, cfg),
+		},
+	}
+}
+
+// AddProvider 追加审核服务，按添加顺序执行
+func (s *ModerationService) AddProvider(provider ModerationProvider) {
+	s.providers = append(s.providers, provider)
+}
+
+// Moderate 依次执行所有审核服务，取最严重的结论
+// 审核服务出错时转人工审核，避免放过未经检查的内容
+func (s *ModerationService) Moderate(ctx context.Context, input *ModerationInput) *ModerationResult {
+	result := &ModerationResult{Verdict: ModerationApprove, Reasons: make([]string, 0)}
+	for _, provider := range s.providers {
+		r, err := provider.Moderate(ctx, input)
+		if err != nil {
+			zap.L().Error("内容审核失败", zap.String("provider", provider.Name()), zap.Error(err))
+			r = &ModerationResult{Verdict: ModerationReview, Reasons: []string{"审核服务异常"}}
+		}
+		if r.Verdict > result.Verdict {
+			result.Verdict = r.Verdict
+		}
+		for _, reason := range r.Reasons {
+			result.Reasons = append(result.Reasons, "["+provider.Name()+"] "+reason)
+		}
+		if result.Verdict == ModerationReject {
+			break
+		}
+	}
+	return result
+}
+
+// ModerateComment 审核评论，返回评论状态和审核说明
+// 命中拒绝规则直接拒绝；其余情况只有可信用户且未命中任何规则时自动通过
+func (s *ModerationService) ModerateComment(ctx context.Context, user *model.User, content string) (model.CommentStatus, string) {
+	result := s.Moderate(ctx, &ModerationInput{UserID: user.ID, Content: content})
+	note := strings.Join(result.Reasons, "; ")
+
+	switch {
+	case result.Verdict == ModerationReject:
+		return model.CommentStatusRejected, note
+	case result.Verdict == ModerationApprove && isTrustedCommenter(user):
+		return model.CommentStatusApproved, note
+	default:
+		return model.CommentStatusPending, note
+	}
+}
+
+// isTrustedCommenter 编辑和管理员始终可信
+func isTrustedCommenter(user *model.User) bool {
+	switch user.Role {
+	case model.UserRoleAdmin, model.UserRoleEditor:
+		return true
+	}
+	return user.TrustLevel == model.TrustLevelTrusted
+}
+
+// RecordDecision 根据人工审核结果调整用户信任等级
+// 新用户通过的评论达到阈值后升级为可信用户，可信用户的评论被拒绝后降回新用户
+func (s *ModerationService) RecordDecision(ctx context.Context, userID string, status model.CommentStatus) {
+	var user model.User
+	if err := s.db.WithContext(ctx).Select("id", "trust_level").First(&user, "id = ?", userID).Error; err != nil {
+		zap.L().Error("查询评论用户失败", zap.String("userID", userID), zap.Error(err))
+		return
+	}
+
+	level := user.TrustLevel
+	switch {
+	case status == model.CommentStatusRejected && user.TrustLevel == model.TrustLevelTrusted:
+		level = model.TrustLevelNew
+	case status == model.CommentStatusApproved && user.TrustLevel == model.TrustLevelNew:
+		var approved int64
+		if err := s.db.WithContext(ctx).Model(&model.Comment{}).
+			Where("user_id = ? AND status = ?", userID, model.CommentStatusApproved).
+			Count(&approved).Error; err != nil {
+			zap.L().Error("统计用户通过评论数失败", zap.String("userID", userID), zap.Error(err))
+			return
+		}
+		if approved >= int64(s.cfg.Moderation.TrustedApprovedComments) {
+			level = model.TrustLevelTrusted
+		}
+	}
+	if level == user.TrustLevel {
+		return
+	}
+
+	if err := s.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).
+		Update("trust_level", level).Error; err != nil {
+		zap.L().Error("更新用户信任等级失败", zap.String("userID", userID), zap.Error(err))
+		return
+	}
+	zap.L().Info("用户信任等级已调整", zap.String("userID", userID),
+		zap.String("from", string(user.TrustLevel)), zap.String("to", string(level)))
+}
+
+// SensitiveWordProvider 敏感词审核
+type SensitiveWordProvider struct {
+	reject *utils.WordFilter // 命中直接拒绝
+	review *utils.WordFilter // 命中转人工审核
+}
+
+// NewSensitiveWordProvider 从词典文件加载敏感词，文件不存在时不做过滤
+func NewSensitiveWordProvider(path string) *SensitiveWordProvider {
+	p := &SensitiveWordProvider{
+		reject: utils.NewWordFilter(nil),
+		review: utils.NewWordFilter(nil),
+	}
+	if path == "" {
+		return p
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		zap.L().Warn("加载敏感词词典失败", zap.String("path", path), zap.Error(err))
+		return p
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if word, ok := strings.CutSuffix(line, ",review"); ok {
+			p.review.Add(strings.TrimSpace(word))
+		} else {
+			p.reject.Add(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		zap.L().Warn("读取敏感词词典失败", zap.String("path", path), zap.Error(err))
+	}
+	zap.L().Info("敏感词词典已加载", zap.Int("reject", p.reject.Len()), zap.Int("review", p.review.Len()))
+	return p
+}
+
+func (p *SensitiveWordProvider) Name() string {
+	return "sensitive-word"
+}
+
+func (p *SensitiveWordProvider) Moderate(ctx context.Context, input *ModerationInput) (*ModerationResult, error) {
+	if words := p.reject.Find(input.Content); len(words) > 0 {
+		return &ModerationResult{Verdict: ModerationReject, Reasons: []string{"包含敏感词: " + strings.Join(words, ",")}}, nil
+	}
+	if words := p.review.Find(input.Content); len(words) > 0 {
+		return &ModerationResult{Verdict: ModerationReview, Reasons: []string{"包含待审核词: " + strings.Join(words, ",")}}, nil
+	}
+	return &ModerationResult{Verdict: ModerationApprove}, nil
+}
+
+var (
+	linkPattern    = regexp.MustCompile(`(?i)https?://|www\.`)
+	contactPattern = regexp.MustCompile(`(?i)(qq|微信|vx|wx|电话|手机)\D{0,3}\d{5,}|1[3-9]\d{9}`)
+)
+
+// 同一字符连续重复的最大次数
+const maxRepeatedRunes = 10
+
+// SpamProvider 垃圾评论审核：链接过多、联系方式、字符刷屏和重复发布
+type SpamProvider struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewSpamProvider(db *gorm.DB, cfg *config.Config) *SpamProvider {
+	return &SpamProvider{db: db, cfg: cfg}
+}
+
+func (p *SpamProvider) Name() string {
+	return "spam"
+}
+
+func (p *SpamProvider) Moderate(ctx context.Context, input *ModerationInput) (*ModerationResult, error) {
+	result := &ModerationResult{Verdict: ModerationApprove, Reasons: make([]string, 0)}
+
+	var duplicates int64
+	if err := p.db.WithContext(ctx).Model(&model.Comment{}).
+		Where("user_id = ? AND content = ? AND created_at > ?", input.UserID, input.Content,
+			time.Now().Add(-p.cfg.Moderation.DuplicateWindow)).
+		Count(&duplicates).Error; err != nil {
+		return nil, err
+	}
+	if duplicates > 0 {
+		result.Verdict = ModerationReject
+		result.Reasons = append(result.Reasons, "短时间内重复发布相同内容")
+		return result, nil
+	}
+
+	if links := len(linkPattern.FindAllString(input.Content, -1)); links > p.cfg.Moderation.MaxLinks {
+		result.Verdict = ModerationReview
+		result.Reasons = append(result.Reasons, "包含 "+strconv.Itoa(links)+" 个链接")
+	}
+	if contactPattern.MatchString(input.Content) {
+		result.Verdict = ModerationReview
+		result.Reasons = append(result.Reasons, "疑似包含联系方式")
+	}
+	if hasRepeatedRunes(input.Content, maxRepeatedRunes) {
+		result.Verdict = ModerationReview
+		result.Reasons = append(result.Reasons, "包含大量重复字符")
+	}
+	return result, nil
+}
+
+// hasRepeatedRunes 判断是否有字符连续重复超过 limit 次
+func hasRepeatedRunes(s string, limit int) bool {
+	var last rune
+	count := 0
+	for _, r := range s {
+		if r == last {
+			count++
+			if count > limit {
+				return true
+			}
+		} else {
+			last, count = r, 1
+		}
+	}
+	return false
+}
@@ -0,0 +1,810 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"beicun/back/config"
+	"beicun/back/model"
+	"beicun/back/utils"
+)
+
+// 通知内容摘要的最大字数
+const notificationExcerptLength = 100
+
+// 邮件摘要只包含最近一段时间内的未读通知
+const digestLookback = 7 * 24 * time.Hour
+
+// NotificationResponse 通知响应
+type NotificationResponse struct {
+	ID         string                 `json:"id"`
+	Type       model.NotificationType `json:"type"`
+	Actor      *UserBrief             `json:"actor,omitempty"`
+	TargetType string                 `json:"targetType"`
+	TargetID   string                 `json:"targetId"`
+	Title      string                 `json:"title"`
+	Content    string                 `json:"content"`
+	Data       json.RawMessage        `json:"data,omitempty"`
+	Read       bool                   `json:"read"`
+	CreatedAt  string                 `json:"createdAt"`
+}
+
+var (
+	ErrNotificationNotFound       = errors.New("通知不存在")
+	ErrInvalidNotificationType    = errors.New("无效的通知类型")
+	ErrInvalidNotificationChannel = errors.New("无效的通知方式")
+)
+
+// NotificationSettingsRequest 通知设置，只更新提交的类型
+type NotificationSettingsRequest struct {
+	Preferences model.NotificationPreferences `json:"preferences" binding:"required"` // 通知类型到投递方式（IN_APP、EMAIL、OFF）
+}
+
+// NotificationSettingsResponse 通知设置，包含所有通知类型
+type NotificationSettingsResponse struct {
+	Preferences model.NotificationPreferences `json:"preferences"`
+}
+
+// UnreadCountResponse 未读通知数
+type UnreadCountResponse struct {
+	Total  int64                            `json:"total"`
+	ByType map[model.NotificationType]int64 `json:"byType"`
+}
+
+// MarkReadResponse 标记已读结果
+type MarkReadResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// commentNotificationData 评论通知的跳转数据
+type commentNotificationData struct {
+	ReviewID    string `json:"reviewId"`
+	ReviewSlug  string `json:"reviewSlug"`
+	ReviewTitle string `json:"reviewTitle"`
+	CommentID   string `json:"commentId"`
+}
+
+// reviewNotificationData 测评审核通知的跳转数据
+type reviewNotificationData struct {
+	ReviewID    string             `json:"reviewId"`
+	ReviewSlug  string             `json:"reviewSlug"`
+	ReviewTitle string             `json:"reviewTitle"`
+	Status      model.ReviewStatus `json:"status"`
+}
+
+// reportNotificationData 举报处理通知的跳转数据
+type reportNotificationData struct {
+	CaseID     string                 `json:"caseId"`
+	TargetType model.ReportTargetType `json:"targetType"`
+	TargetID   string                 `json:"targetId"`
+	Status     model.ReportStatus     `json:"status"`
+}
+
+// priceDropNotificationData 降价通知的跳转数据
+type priceDropNotificationData struct {
+	ProductID   uint    `json:"productId"`
+	ProductSlug string  `json:"productSlug"`
+	OldPrice    float64 `json:"oldPrice"`
+	Price       float64 `json:"price"`
+}
+
+// notificationMailer 发送单条通知邮件
+type notificationMailer func(to string, notification *model.Notification) error
+
+type NotificationService struct {
+	db              *gorm.DB
+	cfg             *config.Config
+	emailService    *EmailService
+	realtimeService *RealtimeService
+}
+
+func NewNotificationService(db *gorm.DB, cfg *config.Config, emailService *EmailService, realtimeService *RealtimeService) *NotificationService {
+	return &NotificationService{
+		db:              db,
+		cfg:             cfg,
+		emailService:    emailService,
+		realtimeService: realtimeService,
+	}
+}
+
+// NotifyCommentPublished 评论公开后通知被回复和被提及的用户，每条评论只通知一次
+// 待审核的评论不发送通知，避免垃圾内容打扰用户
+func (s *NotificationService) NotifyCommentPublished(ctx context.Context, comment *model.Comment) {
+	if comment.Status != model.CommentStatusApproved {
+		return
+	}
+
+	// 以通知时间为条件更新，保证重复审核时不会重复通知
+	result := s.db.WithContext(ctx).Model(&model.Comment{}).
+		Where("id = ? AND notified_at IS NULL", comment.ID).
+		UpdateColumn("notified_at", time.Now())
+	if result.Error != nil {
+		zap.L().Error("标记评论通知状态失败", zap.String("commentID", comment.ID), zap.Error(result.Error))
+		return
+	}
+	if result.RowsAffected == 0 {
+		return
+	}
+
+	notifications, err := s.buildCommentNotifications(ctx, comment)
+	if err != nil {
+		zap.L().Error("生成评论通知失败", zap.String("commentID", comment.ID), zap.Error(err))
+		return
+	}
+	if err := s.deliver(ctx, notifications, nil); err != nil {
+		zap.L().Error("保存评论通知失败", zap.String("commentID", comment.ID), zap.Error(err))
+	}
+}
+
+// NotifyCommentModeration 通知评论作者管理员的审核结果，note 为审核说明
+func (s *NotificationService) NotifyCommentModeration(ctx context.Context, comment *model.Comment, note string) {
+	var title string
+	switch comment.Status {
+	case model.CommentStatusApproved:
+		title = "你的评论已通过审核"
+	case model.CommentStatusRejected:
+		title = "你的评论未通过审核"
+	default:
+		return
+	}
+
+	var review model.Review
+	if err := s.db.WithContext(ctx).Select("id", "slug", "title").First(&review, "id = ?", comment.ReviewID).Error; err != nil {
+		zap.L().Error("生成评论审核通知失败", zap.String("commentID", comment.ID), zap.Error(err))
+		return
+	}
+	data, err := json.Marshal(commentNotificationData{
+		ReviewID:    review.ID,
+		ReviewSlug:  review.Slug,
+		ReviewTitle: review.Title,
+		CommentID:   comment.ID,
+	})
+	if err != nil {
+		return
+	}
+
+	content := excerpt(comment.Content, notificationExcerptLength)
+	if note != "" {
+		content = note + "\n" + content
+	}
+	notification := &model.Notification{
+		UserID:     comment.UserID,
+		Type:       model.NotificationModerationResult,
+		TargetType: string(model.ReportTargetComment),
+		TargetID:   comment.ID,
+		Title:      title,
+		Content:    content,
+		Data:       data,
+	}
+	if err := s.deliver(ctx, []*model.Notification{notification}, nil); err != nil {
+		zap.L().Error("保存评论审核通知失败", zap.String("commentID", comment.ID), zap.Error(err))
+	}
+}
+
+// NotifyReviewModeration 通知投稿作者测评的审核结果，通过审核时邮件沿用投稿审核模板
+func (s *NotificationService) NotifyReviewModeration(ctx context.Context, review *model.Review, note string) {
+	var (
+		t     model.NotificationType
+		title string
+	)
+	switch review.Status {
+	case model.ReviewStatusPublished:
+		t, title = model.NotificationReviewApproved, "你的测评投稿已通过审核"
+	case model.ReviewStatusChangesRequested:
+		t, title = model.NotificationModerationResult, "你的测评投稿需要修改"
+	case model.ReviewStatusRejected:
+		t, title = model.NotificationModerationResult, "你的测评投稿未通过审核"
+	default:
+		return
+	}
+
+	data, err := json.Marshal(reviewNotificationData{
+		ReviewID:    review.ID,
+		ReviewSlug:  review.Slug,
+		ReviewTitle: review.Title,
+		Status:      review.Status,
+	})
+	if err != nil {
+		return
+	}
+
+	content := "《" + review.Title + "》"
+	if note != "" {
+		content += "\n" + note
+	}
+	notification := &model.Notification{
+		UserID:     review.UserID,
+		Type:       t,
+		TargetType: string(model.ReportTargetReview),
+		TargetID:   review.ID,
+		Title:      title,
+		Content:    content,
+		Data:       data,
+	}
+
+	reviewTitle, status := review.Title, review.Status
+	err = s.deliver(ctx, []*model.Notification{notification}, func(to string, _ *model.Notification) error {
+		return s.emailService.SendReviewModerationResult(to, reviewTitle, status, note)
+	})
+	if err != nil {
+		zap.L().Error("保存测评审核通知失败", zap.String("reviewID", review.ID), zap.Error(err))
+	}
+}
+
+// NotifyReportHandled 将举报处理结果反馈给该事项的所有举报人
+func (s *NotificationService) NotifyReportHandled(ctx context.Context, reportCase *model.ReportCase) {
+	var title, content string
+	switch reportCase.Status {
+	case model.ReportStatusResolved:
+		title, content = "你的举报已处理", "经核实举报成立，相关内容已下架"
+	case model.ReportStatusDismissed:
+		title, content = "你的举报已处理", "经核实该内容未违反社区规范"
+	default:
+		return
+	}
+	if reportCase.Resolution != "" {
+		content += "：" + reportCase.Resolution
+	}
+
+	var reporterIDs []string
+	if err := s.db.WithContext(ctx).Model(&model.Report{}).
+		Where("case_id = ?", reportCase.ID).
+		Pluck("reporter_id", &reporterIDs).Error; err != nil {
+		zap.L().Error("查询举报人失败", zap.String("caseID", reportCase.ID), zap.Error(err))
+		return
+	}
+	if len(reporterIDs) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(reportNotificationData{
+		CaseID:     reportCase.ID,
+		TargetType: reportCase.TargetType,
+		TargetID:   reportCase.TargetID,
+		Status:     reportCase.Status,
+	})
+	if err != nil {
+		return
+	}
+
+	notifications := make([]*model.Notification, len(reporterIDs))
+	for i, reporterID := range reporterIDs {
+		notifications[i] = &model.Notification{
+			UserID:     reporterID,
+			Type:       model.NotificationModerationResult,
+			TargetType: string(reportCase.TargetType),
+			TargetID:   reportCase.TargetID,
+			Title:      title,
+			Content:    content,
+			Data:       data,
+		}
+	}
+	if err := s.deliver(ctx, notifications, nil); err != nil {
+		zap.L().Error("保存举报处理通知失败", zap.String("caseID", reportCase.ID), zap.Error(err))
+	}
+}
+
+// NotifyPriceDrop 产品降价后通知收藏了该产品的用户
+func (s *NotificationService) NotifyPriceDrop(ctx context.Context, product *model.Product, oldPrice float64) {
+	if product.Price <= 0 || product.Price >= oldPrice {
+		return
+	}
+
+	var userIDs []string
+	if err := s.db.WithContext(ctx).Model(&model.UserFavorite{}).
+		Where("product_id = ?", product.ID).
+		Pluck("user_id", &userIDs).Error; err != nil {
+		zap.L().Error("查询收藏用户失败", zap.Uint("productID", product.ID), zap.Error(err))
+		return
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(priceDropNotificationData{
+		ProductID:   product.ID,
+		ProductSlug: product.Slug,
+		OldPrice:    oldPrice,
+		Price:       product.Price,
+	})
+	if err != nil {
+		return
+	}
+
+	title := "你收藏的 " + product.Name + " 降价了"
+	content := fmt.Sprintf("价格从 ¥%.2f 降至 ¥%.2f", oldPrice, product.Price)
+	notifications := make([]*model.Notification, len(userIDs))
+	for i, userID := range userIDs {
+		notifications[i] = &model.Notification{
+			UserID:     userID,
+			Type:       model.NotificationPriceDrop,
+			TargetType: "product",
+			TargetID:   strconv.FormatUint(uint64(product.ID), 10),
+			Title:      title,
+			Content:    content,
+			Data:       data,
+		}
+	}
+	if err := s.deliver(ctx, notifications, nil); err != nil {
+		zap.L().Error("保存降价通知失败", zap.Uint("productID", product.ID), zap.Error(err))
+	}
+}
+
+// deliver 按接收人的设置投递通知：关闭的类型不保存，选择邮件的非摘要类型立即发送邮件，
+// 摘要类型由定期任务汇总发送。mailer 为空时使用通用通知邮件模板
+func (s *NotificationService) deliver(ctx context.Context, notifications []*model.Notification, mailer notificationMailer) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	userIDs := make([]string, len(notifications))
+	for i, notification := range notifications {
+		userIDs[i] = notification.UserID
+	}
+	preferences, err := s.loadPreferences(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	kept := make([]*model.Notification, 0, len(notifications))
+	emails := make([]*model.Notification, 0)
+	for _, notification := range notifications {
+		channel := preferences[notification.UserID].Channel(notification.Type)
+		if channel == model.NotificationChannelOff {
+			continue
+		}
+		kept = append(kept, notification)
+		if channel == model.NotificationChannelEmail && !notification.Type.IsDigest() {
+			emails = append(emails, notification)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	if err := s.db.WithContext(ctx).Create(&kept).Error; err != nil {
+		return err
+	}
+	if s.realtimeService != nil {
+		for _, notification := range kept {
+			s.realtimeService.Publish(ctx, RealtimeTopic(RealtimeTopicUser, notification.UserID), RealtimeEventNotification, getNotificationResponse(notification))
+		}
+	}
+
+	if len(emails) > 0 && s.emailService != nil {
+		if mailer == nil {
+			mailer = s.emailService.SendNotification
+		}
+		go s.sendEmails(emails, mailer)
+	}
+	return nil
+}
+
+// sendEmails 立即发送通知邮件，发送成功的通知不再进入摘要
+func (s *NotificationService) sendEmails(notifications []*model.Notification, mailer notificationMailer) {
+	userIDs := make([]string, len(notifications))
+	for i, notification := range notifications {
+		userIDs[i] = notification.UserID
+	}
+
+	var users []model.User
+	if err := s.db.Select("id", "email").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		zap.L().Error("查询通知邮件接收人失败", zap.Error(err))
+		return
+	}
+	emails := make(map[string]string, len(users))
+	for _, user := range users {
+		emails[user.ID] = user.Email
+	}
+
+	sent := make([]string, 0, len(notifications))
+	for _, notification := range notifications {
+		to := emails[notification.UserID]
+		if to == "" {
+			continue
+		}
+		if err := mailer(to, notification); err != nil {
+			zap.L().Error("发送通知邮件失败", zap.String("notificationID", notification.ID), zap.Error(err))
+			continue
+		}
+		sent = append(sent, notification.ID)
+	}
+	if len(sent) == 0 {
+		return
+	}
+	if err := s.db.Model(&model.Notification{}).Where("id IN ?", sent).
+		UpdateColumn("emailed_at", time.Now()).Error; err != nil {
+		zap.L().Error("标记通知邮件发送状态失败", zap.Error(err))
+	}
+}
+
+// loadPreferences 批量获取用户的通知设置，未设置过的用户没有对应记录
+func (s *NotificationService) loadPreferences(ctx context.Context, userIDs []string) (map[string]model.NotificationPreferences, error) {
+	var settings []model.NotificationSetting
+	if err := s.db.WithContext(ctx).Where("user_id IN ?", userIDs).Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	preferences := make(map[string]model.NotificationPreferences, len(settings))
+	for _, setting := range settings {
+		preferences[setting.UserID] = setting.Preferences
+	}
+	return preferences, nil
+}
+
+// buildCommentNotifications 生成回复和提及通知，同一用户只收到一条，回复优先于提及
+func (s *NotificationService) buildCommentNotifications(ctx context.Context, comment *model.Comment) ([]*model.Notification, error) {
+	db := s.db.WithContext(ctx)
+
+	recipients := make(map[string]model.NotificationType)
+	order := make([]string, 0)
+	add := func(userID string, t model.NotificationType) {
+		if userID == "" || userID == comment.UserID {
+			return
+		}
+		if _, ok := recipients[userID]; ok {
+			return
+		}
+		recipients[userID] = t
+		order = append(order, userID)
+	}
+
+	if comment.ParentID != nil {
+		var parent model.Comment
+		err := db.Select("user_id").First(&parent, "id = ?", *comment.ParentID).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+		add(parent.UserID, model.NotificationReply)
+	}
+	if comment.ReplyToID != nil {
+		add(*comment.ReplyToID, model.NotificationReply)
+	}
+
+	if names := utils.ParseMentions(comment.Content); len(names) > 0 {
+		var users []model.User
+		if err := db.Select("id", "name").
+			Where("name IN ? AND status NOT IN ?", names, model.HiddenUserStatuses).
+			Find(&users).Error; err != nil {
+			return nil, err
+		}
+		// 重名用户无法确定提及的是谁，不发送通知
+		byName := make(map[string][]string)
+		for _, user := range users {
+			byName[user.Name] = append(byName[user.Name], user.ID)
+		}
+		for _, name := range names {
+			if ids := byName[name]; len(ids) == 1 {
+				add(ids[0], model.NotificationMention)
+			}
+		}
+	}
+	if len(order) == 0 {
+		return nil, nil
+	}
+
+	var actor model.User
+	if err := db.Select("id", "name").First(&actor, "id = ?", comment.UserID).Error; err != nil {
+		return nil, err
+	}
+	var review model.Review
+	if err := db.Select("id", "slug", "title").First(&review, "id = ?", comment.ReviewID).Error; err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(commentNotificationData{
+		ReviewID:    review.ID,
+		ReviewSlug:  review.Slug,
+		ReviewTitle: review.Title,
+		CommentID:   comment.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := make([]*model.Notification, 0, len(order))
+	for _, userID := range order {
+		t := recipients[userID]
+		title := actor.Name + " 回复了你的评论"
+		if t == model.NotificationMention {
+			title = actor.Name + " 在评论中提到了你"
+		}
+		notifications = append(notifications, &model.Notification{
+			UserID:     userID,
+			Type:       t,
+			ActorID:    &comment.UserID,
+			TargetType: string(model.ReportTargetComment),
+			TargetID:   comment.ID,
+			Title:      title,
+			Content:    excerpt(comment.Content, notificationExcerptLength),
+			Data:       data,
+		})
+	}
+	return notifications, nil
+}
+
+// ListNotifications 获取用户的通知列表，最新的在前
+func (s *NotificationService) ListNotifications(c *gin.Context, userID, notificationType string, unreadOnly bool, page, pageSize int) ([]*NotificationResponse, int64, error) {
+	query := s.db.Model(&model.Notification{}).Where("user_id = ?", userID)
+	if notificationType != "" {
+		if !model.NotificationType(notificationType).IsValid() {
+			return nil, 0, ErrInvalidNotificationType
+		}
+		query = query.Where("type = ?", notificationType)
+	}
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	var notifications []*model.Notification
+	if err := query.Preload("Actor").
+		Order("created_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&notifications).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	responses := make([]*NotificationResponse, len(notifications))
+	for i, notification := range notifications {
+		responses[i] = getNotificationResponse(notification)
+	}
+	return responses, total, nil
+}
+
+// GetUnreadCount 获取未读通知总数及各类型的未读数
+func (s *NotificationService) GetUnreadCount(c *gin.Context, userID string) (*UnreadCountResponse, error) {
+	var rows []struct {
+		Type  model.NotificationType
+		Count int64
+	}
+	if err := s.db.Model(&model.Notification{}).
+		Select("type, COUNT(*) AS count").
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Group("type").
+		Scan(&rows).Error; err != nil {
+		return nil, ErrInternal
+	}
+
+	response := &UnreadCountResponse{ByType: make(map[model.NotificationType]int64, len(model.NotificationTypes))}
+	for _, t := range model.NotificationTypes {
+		response.ByType[t] = 0
+	}
+	for _, row := range rows {
+		response.ByType[row.Type] = row.Count
+		response.Total += row.Count
+	}
+	return response, nil
+}
+
+// MarkRead 将一条通知标记为已读，已读的通知保持原阅读时间
+func (s *NotificationService) MarkRead(c *gin.Context, userID, id string) (*NotificationResponse, error) {
+	var notification model.Notification
+	if err := s.db.Preload("Actor").First(&notification, "id = ? AND user_id = ?", id, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotificationNotFound
+		}
+		return nil, ErrInternal
+	}
+
+	if notification.ReadAt == nil {
+		now := time.Now()
+		if err := s.db.Model(&notification).UpdateColumn("read_at", now).Error; err != nil {
+			return nil, ErrInternal
+		}
+		notification.ReadAt = &now
+		s.publishRead(c, userID, []string{notification.ID}, "")
+	}
+	return getNotificationResponse(&notification), nil
+}
+
+// MarkAllRead 将用户的未读通知全部标记为已读，可按类型过滤
+func (s *NotificationService) MarkAllRead(c *gin.Context, userID, notificationType string) (*MarkReadResponse, error) {
+	query := s.db.Model(&model.Notification{}).Where("user_id = ? AND read_at IS NULL", userID)
+	if notificationType != "" {
+		if !model.NotificationType(notificationType).IsValid() {
+			return nil, ErrInvalidNotificationType
+		}
+		query = query.Where("type = ?", notificationType)
+	}
+
+	result := query.UpdateColumn("read_at", time.Now())
+	if result.Error != nil {
+		return nil, ErrInternal
+	}
+	if result.RowsAffected > 0 {
+		s.publishRead(c, userID, nil, notificationType)
+	}
+	return &MarkReadResponse{Updated: result.RowsAffected}, nil
+}
+
+// notificationReadEvent 已读状态变化事件，IDs 为空表示全部已读（Type 不为空时只限该类型）
+type notificationReadEvent struct {
+	IDs  []string               `json:"ids,omitempty"`
+	Type model.NotificationType `json:"type,omitempty"`
+}
+
+// publishRead 通知用户的其他连接同步已读状态
+func (s *NotificationService) publishRead(c *gin.Context, userID string, ids []string, notificationType string) {
+	if s.realtimeService == nil {
+		return
+	}
+	s.realtimeService.Publish(c.Request.Context(), RealtimeTopic(RealtimeTopicUser, userID), RealtimeEventNotificationRead, &notificationReadEvent{
+		IDs:  ids,
+		Type: model.NotificationType(notificationType),
+	})
+}
+
+// GetSettings 获取各类型通知的投递方式，未设置的类型返回默认值
+func (s *NotificationService) GetSettings(c *gin.Context, userID string) (*NotificationSettingsResponse, error) {
+	var setting model.NotificationSetting
+	if err := s.db.Where("user_id = ?", userID).Limit(1).Find(&setting).Error; err != nil {
+		return nil, ErrInternal
+	}
+	return getNotificationSettingsResponse(setting.Preferences), nil
+}
+
+// UpdateSettings 更新通知投递方式，未提交的类型保持原设置
+func (s *NotificationService) UpdateSettings(c *gin.Context, userID string, req *NotificationSettingsRequest) (*NotificationSettingsResponse, error) {
+	for t, channel := range req.Preferences {
+		if !t.IsValid() {
+			return nil, ErrInvalidNotificationType
+		}
+		if !channel.IsValid() {
+			return nil, ErrInvalidNotificationChannel
+		}
+	}
+
+	var setting model.NotificationSetting
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", userID).Limit(1).Find(&setting).Error; err != nil {
+			return err
+		}
+		if setting.Preferences == nil {
+			setting.Preferences = make(model.NotificationPreferences, len(req.Preferences))
+		}
+		for t, channel := range req.Preferences {
+			setting.Preferences[t] = channel
+		}
+		setting.UserID = userID
+		return tx.Save(&setting).Error
+	})
+	if err != nil {
+		return nil, ErrInternal
+	}
+	return getNotificationSettingsResponse(setting.Preferences), nil
+}
+
+// digestTypes 通过邮件摘要发送的通知类型
+func digestTypes() []model.NotificationType {
+	types := make([]model.NotificationType, 0, len(model.NotificationTypes))
+	for _, t := range model.NotificationTypes {
+		if t.IsDigest() {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// digestNotifications 未读、未发送过且接收人选择了邮件方式的摘要类型通知
+// 摘要类型的默认方式为站内通知，因此只需考虑有设置记录的用户
+func digestNotifications(db *gorm.DB) *gorm.DB {
+	return db.Model(&model.Notification{}).
+		Joins("JOIN notification_settings ON notification_settings.user_id = notifications.user_id").
+		Where("notification_settings.preferences ->> notifications.type = ?", model.NotificationChannelEmail).
+		Where("notifications.type IN ?", digestTypes()).
+		Where("notifications.read_at IS NULL AND notifications.emailed_at IS NULL AND notifications.created_at > ?", time.Now().Add(-digestLookback))
+}
+
+// SendDigests 为选择邮件方式的用户汇总发送回复、提及等未读且未发送过的通知
+func (s *NotificationService) SendDigests(ctx context.Context) {
+	db := s.db.WithContext(ctx)
+
+	var userIDs []string
+	if err := digestNotifications(db).
+		Distinct().
+		Pluck("notifications.user_id", &userIDs).Error; err != nil {
+		zap.L().Error("查询待发送通知摘要的用户失败", zap.Error(err))
+		return
+	}
+
+	for _, userID := range userIDs {
+		if err := s.sendDigest(ctx, userID); err != nil {
+			zap.L().Error("发送通知摘要失败", zap.String("userID", userID), zap.Error(err))
+		}
+	}
+}
+
+func (s *NotificationService) sendDigest(ctx context.Context, userID string) error {
+	db := s.db.WithContext(ctx)
+
+	var user model.User
+	if err := db.Select("id", "name", "email").First(&user, "id = ?", userID).Error; err != nil {
+		return err
+	}
+
+	var notifications []*model.Notification
+	if err := digestNotifications(db).
+		Where("notifications.user_id = ?", userID).
+		Order("notifications.created_at DESC").
+		Find(&notifications).Error; err != nil {
+		return err
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(notifications))
+	for i, notification := range notifications {
+		ids[i] = notification.ID
+	}
+
+	items := notifications
+	if len(items) > s.cfg.Notification.DigestMaxItems {
+		items = items[:s.cfg.Notification.DigestMaxItems]
+	}
+	if err := s.emailService.SendNotificationDigest(user.Email, user.Name, items, len(notifications)); err != nil {
+		return err
+	}
+
+	return db.Model(&model.Notification{}).Where("id IN ?", ids).UpdateColumn("emailed_at", time.Now()).Error
+}
+
+// StartDigestWorker 定期发送未读通知邮件摘要
+func (s *NotificationService) StartDigestWorker(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.Notification.DigestInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.SendDigests(ctx)
+			}
+		}
+	}()
+}
+
+func getNotificationSettingsResponse(preferences model.NotificationPreferences) *NotificationSettingsResponse {
+	response := &NotificationSettingsResponse{Preferences: make(model.NotificationPreferences, len(model.NotificationTypes))}
+	for _, t := range model.NotificationTypes {
+		response.Preferences[t] = preferences.Channel(t)
+	}
+	return response
+}
+
+func getNotificationResponse(notification *model.Notification) *NotificationResponse {
+	response := &NotificationResponse{
+		ID:         notification.ID,
+		Type:       notification.Type,
+		TargetType: notification.TargetType,
+		TargetID:   notification.TargetID,
+		Title:      notification.Title,
+		Content:    notification.Content,
+		Data:       notification.Data,
+		Read:       notification.ReadAt != nil,
+		CreatedAt:  notification.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if notification.Actor != nil {
+		response.Actor = &UserBrief{
+			ID:     notification.Actor.ID,
+			Name:   notification.Actor.Name,
+			Avatar: notification.Actor.Avatar,
+		}
+	}
+	return response
+}
@@ -6,25 +6,30 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/gosimple/slug"
 	"gorm.io/gorm"
 )
 
 var (
 	ErrInvalidProduct  = errors.New("无效的产品信息")
 	ErrProductNotFound = errors.New("产品不存在")
+	ErrVariantNotFound = errors.New("产品版本不存在")
 )
 
 type ProductService struct {
-	db *gorm.DB
+	db                  *gorm.DB
+	viewService         *ViewService
+	notificationService *NotificationService
 }
 
-func NewProductService(db *gorm.DB) *ProductService {
+func NewProductService(db *gorm.DB, viewService *ViewService, notificationService *NotificationService) *ProductService {
 	return &ProductService{
-		db: db,
+		db:                  db,
+		viewService:         viewService,
+		notificationService: notificationService,
 	}
 }
 
@@ -39,6 +44,8 @@ type CreateProductRequest struct {
 	ChannelLength    float64          `json:"channelLength" validate:"required,gt=0"`
 	TotalLength      float64          `json:"totalLength" validate:"required,gt=0"`
 	Weight           float64          `json:"weight" validate:"required,gt=0"`
+	LengthUnit       string           `json:"lengthUnit" validate:"omitempty,oneof=mm cm in"`     // 尺寸单位，默认 cm
+	WeightUnit       string           `json:"weightUnit" validate:"omitempty,oneof=g kg oz lb"`  // 重量单位，默认 g
 	Version          string           `json:"version" validate:"required"`
 	IsReversible     bool             `json:"isReversible"`
 	Stimulation      string           `json:"stimulation" validate:"required,oneof=LOW MEDIUM HIGH"`
@@ -71,6 +78,8 @@ type UpdateProductRequest struct {
 	ChannelLength    float64          `json:"channelLength"`
 	TotalLength      float64          `json:"totalLength"`
 	Weight           float64          `json:"weight"`
+	LengthUnit       string           `json:"lengthUnit"` // 修改后未提供的尺寸按新单位换算
+	WeightUnit       string           `json:"weightUnit"` // 修改后未提供的重量按新单位换算
 	Version          string           `json:"version"`
 	IsReversible     *bool            `json:"isReversible"`
 	Stimulation      string           `json:"stimulation"`
@@ -92,15 +101,52 @@ type UpdateProductRequest struct {
 	MaterialTypeID  string           `json:"materialTypeId"`
 }
 
+// CreateVariantRequest 创建产品版本请求
+type CreateVariantRequest struct {
+	Name          string               `json:"name" binding:"required,max=50"`
+	Price         *float64             `json:"price" binding:"omitempty,gte=0"`
+	Height        *float64             `json:"height" binding:"omitempty,gt=0"`
+	Width         *float64             `json:"width" binding:"omitempty,gt=0"`
+	Length        *float64             `json:"length" binding:"omitempty,gt=0"`
+	ChannelLength *float64             `json:"channelLength" binding:"omitempty,gt=0"`
+	TotalLength   *float64             `json:"totalLength" binding:"omitempty,gt=0"`
+	Weight        *float64             `json:"weight" binding:"omitempty,gt=0"`
+	Images        []model.ProductImage `json:"images" binding:"dive"`
+	SortOrder     int                  `json:"sortOrder"`
+}
+
+// UpdateVariantRequest 更新产品版本请求
+type UpdateVariantRequest struct {
+	Name          string               `json:"name" binding:"omitempty,max=50"`
+	Price         *float64             `json:"price" binding:"omitempty,gte=0"`
+	Height        *float64             `json:"height" binding:"omitempty,gt=0"`
+	Width         *float64             `json:"width" binding:"omitempty,gt=0"`
+	Length        *float64             `json:"length" binding:"omitempty,gt=0"`
+	ChannelLength *float64             `json:"channelLength" binding:"omitempty,gt=0"`
+	TotalLength   *float64             `json:"totalLength" binding:"omitempty,gt=0"`
+	Weight        *float64             `json:"weight" binding:"omitempty,gt=0"`
+	Images        []model.ProductImage `json:"images" binding:"dive"`
+	SortOrder     *int                 `json:"sortOrder"`
+	Clear         []string             `json:"clear" binding:"dive,oneof=price height width length channelLength totalLength weight"` // 清除覆盖值、改为沿用产品的字段，先于新值生效
+}
+
+// ProductVariantResponse 产品版本响应
+type ProductVariantResponse struct {
+	model.ProductVariant
+	Images []model.ProductImage `json:"images"` // 版本图片
+}
+
 // ProductResponse 产品响应
 type ProductResponse struct {
 	model.Product
 	Reviews      []model.Review      `json:"reviews,omitempty"` // 添加产品测评
+	Variants     []*ProductVariantResponse `json:"variants,omitempty"` // 产品版本
 	Ratings      []model.Rating      `json:"-"`                 // 隐藏评分详情
 	Tags         []model.ProductTag  `json:"-"`                 // 隐藏标签详情
 	MainImage    []model.MainImage   `json:"mainImage"`         // 主图
 	SalesImage   []model.SalesImage  `json:"salesImage"`        // 销售图
 	ProductImages []model.ProductImage `json:"productImages"`    // 产品图片
+	CollectionCount int64            `json:"collectionCount"`   // 包含该产品的公开收藏夹数
 }
 
 func (s *ProductService) toProductResponse(product *model.Product) (*ProductResponse, error) {
@@ -141,9 +187,79 @@ func (s *ProductService) toProductResponse(product *model.Product) (*ProductResp
 	response.Product.SalesImage = nil
 	response.Product.ProductImages = nil
 
+	// 设置版本数据
+	for i := range product.Variants {
+		variant, err := s.toVariantResponse(&product.Variants[i])
+		if err != nil {
+			return nil, err
+		}
+		response.Variants = append(response.Variants, variant)
+	}
+	response.Product.Variants = nil
+
 	return response, nil
 }
 
+func (s *ProductService) toVariantResponse(variant *model.ProductVariant) (*ProductVariantResponse, error) {
+	var images []model.ProductImage
+	if len(variant.Images) > 0 {
+		if err := json.Unmarshal(variant.Images, &images); err != nil {
+			return nil, fmt.Errorf("解析版本图片数据失败: %v", err)
+		}
+	}
+
+	response := &ProductVariantResponse{
+		ProductVariant: *variant,
+		Images:         images,
+	}
+	response.ProductVariant.Images = nil
+
+	return response, nil
+}
+
+// ConvertUnits 按指定单位制换算响应中的尺寸与重量，system 为空时保持原单位
+func (r *ProductResponse) ConvertUnits(system model.UnitSystem) {
+	if system == "" {
+		return
+	}
+
+	fromLength, toLength := r.LengthUnit, system.LengthUnit()
+	fromWeight, toWeight := r.WeightUnit, system.WeightUnit()
+
+	r.Height = model.ConvertLength(r.Height, fromLength, toLength)
+	r.Width = model.ConvertLength(r.Width, fromLength, toLength)
+	r.Length = model.ConvertLength(r.Length, fromLength, toLength)
+	r.ChannelLength = model.ConvertLength(r.ChannelLength, fromLength, toLength)
+	r.TotalLength = model.ConvertLength(r.TotalLength, fromLength, toLength)
+	r.Weight = model.ConvertWeight(r.Weight, fromWeight, toWeight)
+	r.LengthUnit = toLength
+	r.WeightUnit = toWeight
+
+	convertLength := func(v *float64) *float64 {
+		if v == nil {
+			return nil
+		}
+		converted := model.ConvertLength(*v, fromLength, toLength)
+		return &converted
+	}
+	for _, variant := range r.Variants {
+		variant.Height = convertLength(variant.Height)
+		variant.Width = convertLength(variant.Width)
+		variant.Length = convertLength(variant.Length)
+		variant.ChannelLength = convertLength(variant.ChannelLength)
+		variant.TotalLength = convertLength(variant.TotalLength)
+		if variant.Weight != nil {
+			weight := model.ConvertWeight(*variant.Weight, fromWeight, toWeight)
+			variant.Weight = &weight
+		}
+	}
+}
+
+// preloadVariants 按排序顺序预加载产品版本
+func preloadVariants(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order ASC, created_at ASC")
+}
+
 // CreateProduct 创建产品
 func (s *ProductService) CreateProduct(c *gin.Context, req *CreateProductRequest) (*ProductResponse, error) {
 	// 检查关联数据是否存在
@@ -203,10 +319,25 @@ func (s *ProductService) CreateProduct(c *gin.Context, req *CreateProductRequest
 		return nil, fmt.Errorf("转换产品图数据失败: %v", err)
 	}
 
+	// 默认使用公制单位
+	lengthUnit := model.LengthUnitCM
+	if req.LengthUnit != "" {
+		lengthUnit = model.LengthUnit(req.LengthUnit)
+	}
+	weightUnit := model.WeightUnitG
+	if req.WeightUnit != "" {
+		weightUnit = model.WeightUnit(req.WeightUnit)
+	}
+
+	productSlug, err := s.generateUniqueSlug(req.Name, "")
+	if err != nil {
+		return nil, err
+	}
+
 	// 创建产品
 	product := model.Product{
 		Name:             req.Name,
-		Slug:             slug.Make(req.Name),
+		Slug:             productSlug,
 		RegistrationDate: req.RegistrationDate,
 		BrandID:          req.BrandID,
 		ProductTypeID:    req.ProductTypeID,
@@ -220,6 +351,8 @@ func (s *ProductService) CreateProduct(c *gin.Context, req *CreateProductRequest
 		ChannelLength:    req.ChannelLength,
 		TotalLength:      req.TotalLength,
 		Weight:           req.Weight,
+		LengthUnit:       lengthUnit,
+		WeightUnit:       weightUnit,
 		Version:          req.Version,
 		IsReversible:     req.IsReversible,
 		Stimulation:      model.StimulationLevel(req.Stimulation),
@@ -237,6 +370,10 @@ func (s *ProductService) CreateProduct(c *gin.Context, req *CreateProductRequest
 		UserID:           utils.GetUserIDFromContext(c),
 	}
 
+	if err := product.ValidateDimensions(); err != nil {
+		return nil, err
+	}
+
 	if err := s.db.Create(&product).Error; err != nil {
 		return nil, err
 	}
@@ -254,10 +391,16 @@ func (s *ProductService) UpdateProduct(c *gin.Context, id string, req *UpdatePro
 		return nil, err
 	}
 
+	oldSlug, oldPrice := product.Slug, product.Price
+
 	// 更新基本信息
-	if req.Name != "" {
+	if req.Name != "" && req.Name != product.Name {
+		productSlug, err := s.generateUniqueSlug(req.Name, id)
+		if err != nil {
+			return nil, err
+		}
 		product.Name = req.Name
-		product.Slug = slug.Make(req.Name)
+		product.Slug = productSlug
 	}
 	if !req.RegistrationDate.IsZero() {
 		product.RegistrationDate = req.RegistrationDate
@@ -265,23 +408,48 @@ func (s *ProductService) UpdateProduct(c *gin.Context, id string, req *UpdatePro
 	if req.Price > 0 {
 		product.Price = req.Price
 	}
+	// 只校验本次修改的尺寸字段
+	dimensionFields := make(map[string]bool)
+	// 单位变更时，本次未提供的尺寸和重量由原单位换算为新单位，本次提供的值按新单位理解
+	oldLengthUnit, oldWeightUnit := product.LengthUnit, product.WeightUnit
+	if req.LengthUnit != "" && model.LengthUnit(req.LengthUnit) != product.LengthUnit {
+		product.LengthUnit = model.LengthUnit(req.LengthUnit)
+		for _, v := range []*float64{&product.Height, &product.Width, &product.Length, &product.ChannelLength, &product.TotalLength} {
+			*v = model.ConvertLength(*v, oldLengthUnit, product.LengthUnit)
+		}
+		dimensionFields["lengthUnit"] = true
+	}
+	if req.WeightUnit != "" && model.WeightUnit(req.WeightUnit) != product.WeightUnit {
+		product.WeightUnit = model.WeightUnit(req.WeightUnit)
+		product.Weight = model.ConvertWeight(product.Weight, oldWeightUnit, product.WeightUnit)
+		dimensionFields["weightUnit"] = true
+	}
 	if req.Height > 0 {
 		product.Height = req.Height
+		dimensionFields["height"] = true
 	}
 	if req.Width > 0 {
 		product.Width = req.Width
+		dimensionFields["width"] = true
 	}
 	if req.Length > 0 {
 		product.Length = req.Length
+		dimensionFields["length"] = true
 	}
 	if req.ChannelLength > 0 {
 		product.ChannelLength = req.ChannelLength
+		dimensionFields["channelLength"] = true
 	}
 	if req.TotalLength > 0 {
 		product.TotalLength = req.TotalLength
+		dimensionFields["totalLength"] = true
 	}
 	if req.Weight > 0 {
 		product.Weight = req.Weight
+		dimensionFields["weight"] = true
+	}
+	if err := product.ValidateDimensionFields(dimensionFields); err != nil {
+		return nil, err
 	}
 	if req.Version != "" {
 		product.Version = req.Version
@@ -394,13 +562,64 @@ func (s *ProductService) UpdateProduct(c *gin.Context, id string, req *UpdatePro
 		product.MaterialTypeID = req.MaterialTypeID
 	}
 
-	if err := s.db.Save(&product).Error; err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&product).Error; err != nil {
+			return err
+		}
+		if product.LengthUnit != oldLengthUnit || product.WeightUnit != oldWeightUnit {
+			if err := convertVariantUnits(tx, &product, oldLengthUnit, oldWeightUnit); err != nil {
+				return err
+			}
+		}
+		if product.Price != oldPrice {
+			if err := tx.Create(&model.ProductPriceChange{
+				ProductID: product.ID,
+				OldPrice:  oldPrice,
+				NewPrice:  product.Price,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return recordSlugChange(tx, model.SlugEntityProduct, product.ID, oldSlug, product.Slug)
+	})
+	if err != nil {
 		return nil, err
 	}
+	if s.notificationService != nil {
+		s.notificationService.NotifyPriceDrop(c.Request.Context(), &product, oldPrice)
+	}
 
 	return s.toProductResponse(&product)
 }
 
+// convertVariantUnits 将产品版本覆盖的尺寸和重量换算为产品的新单位
+func convertVariantUnits(tx *gorm.DB, product *model.Product, oldLengthUnit model.LengthUnit, oldWeightUnit model.WeightUnit) error {
+	var variants []model.ProductVariant
+	if err := tx.Where("product_id = ?", product.ID).Find(&variants).Error; err != nil {
+		return err
+	}
+	for i := range variants {
+		variant := &variants[i]
+		for _, v := range []*float64{variant.Height, variant.Width, variant.Length, variant.ChannelLength, variant.TotalLength} {
+			if v != nil {
+				*v = model.ConvertLength(*v, oldLengthUnit, product.LengthUnit)
+			}
+		}
+		if variant.Weight != nil {
+			*variant.Weight = model.ConvertWeight(*variant.Weight, oldWeightUnit, product.WeightUnit)
+		}
+		if err := tx.Save(variant).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateUniqueSlug 生成唯一的产品 slug
+func (s *ProductService) generateUniqueSlug(name, excludeID string) (string, error) {
+	return generateEntitySlug(s.db, &model.Product{}, model.SlugEntityProduct, name, excludeID)
+}
+
 // DeleteProduct 删除产品
 func (s *ProductService) DeleteProduct(c *gin.Context, id string) error {
 	result := s.db.Delete(&model.Product{}, "id = ?", id)
@@ -421,6 +640,7 @@ func (s *ProductService) GetProduct(c *gin.Context, id uint ) (*ProductResponse,
 		Preload("ChannelType").
 		Preload("Brand").
 		Preload("MaterialType").
+		Preload("Variants", preloadVariants).
 		First(&product, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrProductNotFound
@@ -428,7 +648,11 @@ func (s *ProductService) GetProduct(c *gin.Context, id uint ) (*ProductResponse,
 		return nil, err
 	}
 
-	return s.toProductResponse(&product)
+	response, err := s.toProductResponse(&product)
+	if err != nil {
+		return nil, err
+	}
+	return response, s.attachCollectionCounts(response)
 }
 
 // GetProductBySlug 通过 slug 获取产品详情
@@ -439,19 +663,22 @@ func (s *ProductService) GetProductBySlug(c *gin.Context, slug string) (*Product
 		Preload("ChannelType").
 		Preload("Brand").
 		Preload("MaterialType").
+		Preload("Variants", preloadVariants).
 		First(&product, "slug = ?", slug).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrProductNotFound
+			return nil, resolveMovedSlug(s.db, &model.Product{}, model.SlugEntityProduct, slug, ErrProductNotFound)
 		}
 		return nil, err
 	}
 
-	// 增加浏览量
-	if err := s.db.Model(&product).UpdateColumn("view_count", gorm.Expr("view_count + ?", 1)).Error; err != nil {
+	// 记录浏览量
+	s.viewService.RecordView(c, ViewTargetProduct, strconv.FormatUint(uint64(product.ID), 10))
+
+	response, err := s.toProductResponse(&product)
+	if err != nil {
 		return nil, err
 	}
-
-	return s.toProductResponse(&product)
+	return response, s.attachCollectionCounts(response)
 }
 
 
@@ -514,6 +741,200 @@ func (s *ProductService) ListProducts(c *gin.Context, page, pageSize int, filter
 		}
 		responses = append(responses, response)
 	}
+	if err := s.attachCollectionCounts(responses...); err != nil {
+		return nil, 0, err
+	}
 
 	return responses, total, nil
 }
+
+// attachCollectionCounts 填充包含产品的公开收藏夹数
+func (s *ProductService) attachCollectionCounts(responses ...*ProductResponse) error {
+	productIDs := make([]uint, len(responses))
+	for i, response := range responses {
+		productIDs[i] = response.ID
+	}
+	counts, err := countProductCollections(s.db, productIDs)
+	if err != nil {
+		return err
+	}
+	for _, response := range responses {
+		response.CollectionCount = counts[response.ID]
+	}
+	return nil
+}
+
+// ListVariants 获取产品版本列表，system 非空时按该单位制换算尺寸与重量
+func (s *ProductService) ListVariants(c *gin.Context, productID uint, system model.UnitSystem) ([]*ProductVariantResponse, error) {
+	var product model.Product
+	if err := s.db.Select("id", "length_unit", "weight_unit").First(&product, "id = ?", productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	var variants []model.ProductVariant
+	if err := preloadVariants(s.db.Where("product_id = ?", productID)).Find(&variants).Error; err != nil {
+		return nil, err
+	}
+
+	product.Variants = variants
+	response, err := s.toProductResponse(&product)
+	if err != nil {
+		return nil, err
+	}
+	response.ConvertUnits(system)
+
+	if response.Variants == nil {
+		return []*ProductVariantResponse{}, nil
+	}
+	return response.Variants, nil
+}
+
+// CreateVariant 创建产品版本
+func (s *ProductService) CreateVariant(c *gin.Context, productID uint, req *CreateVariantRequest) (*ProductVariantResponse, error) {
+	var product model.Product
+	if err := s.db.First(&product, "id = ?", productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	imagesJSON, err := json.Marshal(req.Images)
+	if err != nil {
+		return nil, fmt.Errorf("转换版本图片数据失败: %v", err)
+	}
+
+	variant := &model.ProductVariant{
+		ProductID:     productID,
+		Name:          req.Name,
+		Price:         req.Price,
+		Height:        req.Height,
+		Width:         req.Width,
+		Length:        req.Length,
+		ChannelLength: req.ChannelLength,
+		TotalLength:   req.TotalLength,
+		Weight:        req.Weight,
+		Images:        imagesJSON,
+		SortOrder:     req.SortOrder,
+	}
+	if err := variant.Validate(); err != nil {
+		return nil, err
+	}
+	if err := variant.ValidateDimensions(&product); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(variant).Error; err != nil {
+		return nil, err
+	}
+
+	return s.toVariantResponse(variant)
+}
+
+// UpdateVariant 更新产品版本
+func (s *ProductService) UpdateVariant(c *gin.Context, productID uint, variantID string, req *UpdateVariantRequest) (*ProductVariantResponse, error) {
+	variant, err := s.GetVariant(c, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var product model.Product
+	if err := s.db.First(&product, "id = ?", productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	for _, field := range req.Clear {
+		switch field {
+		case "price":
+			variant.Price = nil
+		case "height":
+			variant.Height = nil
+		case "width":
+			variant.Width = nil
+		case "length":
+			variant.Length = nil
+		case "channelLength":
+			variant.ChannelLength = nil
+		case "totalLength":
+			variant.TotalLength = nil
+		case "weight":
+			variant.Weight = nil
+		}
+	}
+	if req.Name != "" {
+		variant.Name = req.Name
+	}
+	if req.Price != nil {
+		variant.Price = req.Price
+	}
+	if req.Height != nil {
+		variant.Height = req.Height
+	}
+	if req.Width != nil {
+		variant.Width = req.Width
+	}
+	if req.Length != nil {
+		variant.Length = req.Length
+	}
+	if req.ChannelLength != nil {
+		variant.ChannelLength = req.ChannelLength
+	}
+	if req.TotalLength != nil {
+		variant.TotalLength = req.TotalLength
+	}
+	if req.Weight != nil {
+		variant.Weight = req.Weight
+	}
+	if req.Images != nil {
+		imagesJSON, err := json.Marshal(req.Images)
+		if err != nil {
+			return nil, fmt.Errorf("转换版本图片数据失败: %v", err)
+		}
+		variant.Images = imagesJSON
+	}
+	if req.SortOrder != nil {
+		variant.SortOrder = *req.SortOrder
+	}
+	if err := variant.Validate(); err != nil {
+		return nil, err
+	}
+	if err := variant.ValidateDimensions(&product); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Save(variant).Error; err != nil {
+		return nil, err
+	}
+
+	return s.toVariantResponse(variant)
+}
+
+// DeleteVariant 删除产品版本
+func (s *ProductService) DeleteVariant(c *gin.Context, productID uint, variantID string) error {
+	result := s.db.Delete(&model.ProductVariant{}, "id = ? AND product_id = ?", variantID, productID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVariantNotFound
+	}
+	return nil
+}
+
+// GetVariant 获取产品下的指定版本
+func (s *ProductService) GetVariant(c *gin.Context, productID uint, variantID string) (*model.ProductVariant, error) {
+	var variant model.ProductVariant
+	if err := s.db.First(&variant, "id = ? AND product_id = ?", variantID, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVariantNotFound
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"beicun/back/model"
+	"beicun/back/utils"
+)
+
+var (
+	ErrInvalidReaction = errors.New("无效的反馈类型")
+	ErrReactToOwn      = errors.New("不能对自己的内容进行反馈")
+)
+
+// ReactRequest 反馈请求
+type ReactRequest struct {
+	Type model.ReactionType `json:"type" binding:"required"`
+}
+
+// ReactionSummary 反馈统计
+type ReactionSummary struct {
+	TargetType      model.ReactionTargetType `json:"targetType"`
+	TargetID        string                   `json:"targetId"`
+	HelpfulCount    int                      `json:"helpfulCount"`
+	NotHelpfulCount int                      `json:"notHelpfulCount"`
+	MyReaction      *model.ReactionType      `json:"myReaction,omitempty"`
+}
+
+// reactionTables 可反馈对象的表，计数冗余存储在对象表中
+var reactionTables = map[model.ReactionTargetType]string{
+	model.ReactionTargetReview:  "reviews",
+	model.ReactionTargetComment: "comments",
+}
+
+type ReactionService struct {
+	db *gorm.DB
+}
+
+func NewReactionService(db *gorm.DB) *ReactionService {
+	return &ReactionService{db: db}
+}
+
+// React 对测评或评论进行反馈，重复反馈会覆盖之前的选择
+func (s *ReactionService) React(c *gin.Context, userID string, targetType model.ReactionTargetType, targetID string, reactionType model.ReactionType) (*ReactionSummary, error) {
+	if !reactionType.IsValid() {
+		return nil, ErrInvalidReaction
+	}
+
+	var summary *ReactionSummary
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.lockTarget(tx, targetType, targetID, userID); err != nil {
+			return err
+		}
+
+		reaction := &model.Reaction{
+			UserID:     userID,
+			TargetType: targetType,
+			TargetID:   targetID,
+			Type:       reactionType,
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "target_type"}, {Name: "target_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"type", "updated_at"}),
+		}).Create(reaction).Error; err != nil {
+			return err
+		}
+
+		var err error
+		summary, err = s.refreshCounts(tx, targetType, targetID)
+		if err != nil {
+			return err
+		}
+		summary.MyReaction = &reactionType
+		return nil
+	})
+	if err != nil {
+		return nil, reactionError(err)
+	}
+	return summary, nil
+}
+
+// RemoveReaction 取消反馈
+func (s *ReactionService) RemoveReaction(c *gin.Context, userID string, targetType model.ReactionTargetType, targetID string) (*ReactionSummary, error) {
+	var summary *ReactionSummary
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.lockTarget(tx, targetType, targetID, userID); err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ? AND target_type = ? AND target_id = ?", userID, targetType, targetID).
+			Delete(&model.Reaction{}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		summary, err = s.refreshCounts(tx, targetType, targetID)
+		return err
+	})
+	if err != nil {
+		return nil, reactionError(err)
+	}
+	return summary, nil
+}
+
+// lockTarget 锁定反馈对象，只能对已发布的内容反馈，且不能反馈自己的内容
+func (s *ReactionService) lockTarget(tx *gorm.DB, targetType model.ReactionTargetType, targetID, userID string) error {
+	var target struct {
+		UserID string
+		Status string
+	}
+	err := tx.Table(reactionTables[targetType]).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("user_id", "status").
+		Where("id = ? AND deleted_at IS NULL", targetID).
+		Take(&target).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return targetNotFoundError(targetType)
+		}
+		return err
+	}
+
+	switch targetType {
+	case model.ReactionTargetReview:
+		if target.Status != string(model.ReviewStatusPublished) {
+			return ErrReviewNotFound
+		}
+	case model.ReactionTargetComment:
+		if target.Status != string(model.CommentStatusApproved) {
+			return ErrCommentNotFound
+		}
+	}
+	if target.UserID == userID {
+		return ErrReactToOwn
+	}
+	return nil
+}
+
+// refreshCounts 重新统计反馈数量并更新对象的冗余计数和排序分
+func (s *ReactionService) refreshCounts(tx *gorm.DB, targetType model.ReactionTargetType, targetID string) (*ReactionSummary, error) {
+	var counts struct {
+		Helpful    int
+		NotHelpful int
+	}
+	if err := tx.Model(&model.Reaction{}).
+		Select("COUNT(*) FILTER (WHERE type = ?) AS helpful, COUNT(*) FILTER (WHERE type = ?) AS not_helpful",
+			model.ReactionHelpful, model.ReactionNotHelpful).
+		Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Table(reactionTables[targetType]).
+		Where("id = ?", targetID).
+		UpdateColumns(map[string]interface{}{
+			"helpful_count":     counts.Helpful,
+			"not_helpful_count": counts.NotHelpful,
+			"helpful_score":     utils.WilsonScore(counts.Helpful, counts.NotHelpful),
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	return &ReactionSummary{
+		TargetType:      targetType,
+		TargetID:        targetID,
+		HelpfulCount:    counts.Helpful,
+		NotHelpfulCount: counts.NotHelpful,
+	}, nil
+}
+
+func targetNotFoundError(targetType model.ReactionTargetType) error {
+	if targetType == model.ReactionTargetComment {
+		return ErrCommentNotFound
+	}
+	return ErrReviewNotFound
+}
+
+// reactionError 保留业务错误，其余错误统一为内部错误
+func reactionError(err error) error {
+	switch err {
+	case ErrReviewNotFound, ErrCommentNotFound, ErrReactToOwn:
+		return err
+	}
+	return ErrInternal
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"beicun/back/config"
+	"beicun/back/model"
+)
+
+// 实时推送的主题前缀，主题格式为 "前缀:对象ID"
+const (
+	RealtimeTopicUser   = "user"   // 用户私有事件，如新通知
+	RealtimeTopicReview = "review" // 测评下新公开的评论
+	RealtimeTopicUpload = "upload" // 分片上传进度
+)
+
+// 实时推送的事件类型
+const (
+	RealtimeEventNotification     = "notification"      // 收到新通知
+	RealtimeEventNotificationRead = "notification.read" // 通知已读状态变化，用于多端同步未读数
+	RealtimeEventComment          = "comment"           // 测评下有新公开的评论
+	RealtimeEventUploadProgress   = "upload.progress"   // 上传进度变化
+)
+
+// Redis 频道前缀，所有实例通过模式订阅接收全部事件再分发给本地连接
+const realtimeChannelPrefix = "realtime:"
+
+var (
+	ErrInvalidTopic   = errors.New("无效的订阅主题")
+	ErrTooManyTopics  = errors.New("订阅主题过多")
+	ErrTopicForbidden = errors.New("无权订阅该主题")
+)
+
+// RealtimeEvent 推送给客户端的事件
+type RealtimeEvent struct {
+	Topic string          `json:"topic"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+	Time  string          `json:"time"`
+}
+
+// RealtimeSubscription 一个客户端连接的订阅
+type RealtimeSubscription struct {
+	hub    *RealtimeService
+	topics []string
+	events chan *RealtimeEvent
+	once   sync.Once
+}
+
+// Events 接收事件的通道
+func (sub *RealtimeSubscription) Events() <-chan *RealtimeEvent {
+	return sub.events
+}
+
+// Topics 实际订阅的主题
+func (sub *RealtimeSubscription) Topics() []string {
+	return sub.topics
+}
+
+// Close 取消订阅
+func (sub *RealtimeSubscription) Close() {
+	sub.once.Do(func() {
+		sub.hub.unsubscribe(sub)
+	})
+}
+
+// RealtimeService 基于 Redis 发布订阅的实时推送，支持多实例部署
+// 每个实例只维持一个 Redis 订阅连接，收到的事件按主题分发给本实例的客户端连接
+type RealtimeService struct {
+	db    *gorm.DB
+	redis *redis.Client
+	cfg   *config.Config
+
+	mu          sync.RWMutex
+	subscribers map[string]map[*RealtimeSubscription]struct{}
+}
+
+func NewRealtimeService(db *gorm.DB, redis *redis.Client, cfg *config.Config) *RealtimeService {
+	return &RealtimeService{
+		db:          db,
+		redis:       redis,
+		cfg:         cfg,
+		subscribers: make(map[string]map[*RealtimeSubscription]struct{}),
+	}
+}
+
+// RealtimeTopic 生成主题名称
+func RealtimeTopic(prefix, id string) string {
+	return prefix + ":" + id
+}
+
+// Publish 向主题发布事件，所有实例上订阅了该主题的连接都会收到
+func (s *RealtimeService) Publish(ctx context.Context, topic, eventType string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		zap.L().Error("序列化实时事件失败", zap.String("topic", topic), zap.Error(err))
+		return
+	}
+	event, err := json.Marshal(&RealtimeEvent{
+		Topic: topic,
+		Type:  eventType,
+		Data:  payload,
+		Time:  time.Now().Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		return
+	}
+
+	// 未配置 Redis 时只推送给本实例的连接
+	if s.redis == nil {
+		s.dispatch(event)
+		return
+	}
+	if err := s.redis.Publish(ctx, realtimeChannelPrefix+topic, event).Err(); err != nil {
+		zap.L().Error("发布实时事件失败", zap.String("topic", topic), zap.Error(err))
+	}
+}
+
+// Subscribe 为用户订阅主题，用户私有主题总会被订阅；调用方需在连接结束时关闭订阅
+func (s *RealtimeService) Subscribe(ctx context.Context, userID string, role model.UserRole, topics []string) (*RealtimeSubscription, error) {
+	if len(topics) > s.cfg.Realtime.MaxTopics {
+		return nil, ErrTooManyTopics
+	}
+
+	userTopic := RealtimeTopic(RealtimeTopicUser, userID)
+	seen := map[string]bool{userTopic: true}
+	resolved := []string{userTopic}
+	for _, topic := range topics {
+		topic = strings.TrimSpace(topic)
+		if topic == "" || seen[topic] {
+			continue
+		}
+		if err := s.authorizeTopic(ctx, userID, role, topic); err != nil {
+			return nil, err
+		}
+		seen[topic] = true
+		resolved = append(resolved, topic)
+	}
+
+	sub := &RealtimeSubscription{
+		hub:    s,
+		topics: resolved,
+		events: make(chan *RealtimeEvent, s.cfg.Realtime.ClientBuffer),
+	}
+	s.mu.Lock()
+	for _, topic := range resolved {
+		if s.subscribers[topic] == nil {
+			s.subscribers[topic] = make(map[*RealtimeSubscription]struct{})
+		}
+		s.subscribers[topic][sub] = struct{}{}
+	}
+	s.mu.Unlock()
+	return sub, nil
+}
+
+// authorizeTopic 检查用户能否订阅主题：只能订阅自己的私有主题和已发布的测评，上传进度仅管理员可订阅
+func (s *RealtimeService) authorizeTopic(ctx context.Context, userID string, role model.UserRole, topic string) error {
+	prefix, id, ok := strings.Cut(topic, ":")
+	if !ok || id == "" {
+		return ErrInvalidTopic
+	}
+
+	switch prefix {
+	case RealtimeTopicUser:
+		if id != userID {
+			return ErrTopicForbidden
+		}
+	case RealtimeTopicReview:
+		var count int64
+		if err := s.db.WithContext(ctx).Model(&model.Review{}).
+			Where("id = ? AND status = ?", id, model.ReviewStatusPublished).
+			Count(&count).Error; err != nil {
+			return ErrInvalidTopic
+		}
+		if count == 0 {
+			return ErrInvalidTopic
+		}
+	case RealtimeTopicUpload:
+		if role != model.UserRoleAdmin {
+			return ErrTopicForbidden
+		}
+	default:
+		return ErrInvalidTopic
+	}
+	return nil
+}
+
+func (s *RealtimeService) unsubscribe(sub *RealtimeSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, topic := range sub.topics {
+		delete(s.subscribers[topic], sub)
+		if len(s.subscribers[topic]) == 0 {
+			delete(s.subscribers, topic)
+		}
+	}
+}
+
+// dispatch 将事件分发给本实例订阅了该主题的连接，连接缓冲已满时丢弃事件，避免慢连接阻塞其他连接
+func (s *RealtimeService) dispatch(payload []byte) {
+	var event RealtimeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		zap.L().Error("解析实时事件失败", zap.Error(err))
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for sub := range s.subscribers[event.Topic] {
+		select {
+		case sub.events <- &event:
+		default:
+			zap.L().Warn("实时推送连接缓冲已满，丢弃事件", zap.String("topic", event.Topic), zap.String("type", event.Type))
+		}
+	}
+}
+
+// StartSubscriber 订阅 Redis 上的实时事件并分发给本实例的连接，断线后由 Redis 客户端自动重连
+func (s *RealtimeService) StartSubscriber(ctx context.Context) {
+	if s.redis == nil {
+		return
+	}
+
+	go func() {
+		pubsub := s.redis.PSubscribe(ctx, realtimeChannelPrefix+"*")
+		defer pubsub.Close()
+
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				s.dispatch([]byte(msg.Payload))
+			}
+		}
+	}()
+}
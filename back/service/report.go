@@ -0,0 +1,649 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"beicun/back/cache"
+	"beicun/back/config"
+	"beicun/back/model"
+)
+
+var (
+	ErrInvalidReportReason = errors.New("无效的举报原因")
+	ErrReportOwn           = errors.New("不能举报自己的内容")
+	ErrAlreadyReported     = errors.New("已经举报过该内容")
+	ErrReportCaseNotFound  = errors.New("举报不存在")
+	ErrReportCaseHandled   = errors.New("该举报已处理")
+)
+
+// 举报对象预览的最大字数
+const reportExcerptLength = 200
+
+// CreateReportRequest 举报请求
+type CreateReportRequest struct {
+	Reason      model.ReportReason `json:"reason" binding:"required"`
+	Description string             `json:"description" binding:"max=500"` // 补充说明
+}
+
+// HandleReportRequest 处理举报请求
+type HandleReportRequest struct {
+	Resolution string `json:"resolution" binding:"max=500"` // 处理说明，会展示给举报人
+}
+
+// ReportResponse 举报人视角的举报记录
+type ReportResponse struct {
+	ID          string                 `json:"id"`
+	TargetType  model.ReportTargetType `json:"targetType"`
+	TargetID    string                 `json:"targetId"`
+	Reason      model.ReportReason     `json:"reason"`
+	Description string                 `json:"description"`
+	Status      model.ReportStatus     `json:"status"`               // 处理状态
+	Resolution  string                 `json:"resolution,omitempty"` // 处理说明
+	HandledAt   *string                `json:"handledAt,omitempty"`
+	CreatedAt   string                 `json:"createdAt"`
+}
+
+// ReportTargetBrief 被举报内容预览
+type ReportTargetBrief struct {
+	Title   string     `json:"title,omitempty"` // 测评标题
+	Slug    string     `json:"slug,omitempty"`  // 测评 slug
+	Content string     `json:"content"`         // 内容摘要
+	Status  string     `json:"status"`          // 当前状态
+	Author  *UserBrief `json:"author,omitempty"`
+}
+
+// ReportEntry 单条举报
+type ReportEntry struct {
+	ID          string             `json:"id"`
+	Reporter    *UserBrief         `json:"reporter,omitempty"`
+	Reason      model.ReportReason `json:"reason"`
+	Description string             `json:"description"`
+	CreatedAt   string             `json:"createdAt"`
+}
+
+// ReportCaseResponse 举报处理队列中的事项
+type ReportCaseResponse struct {
+	ID          string                     `json:"id"`
+	TargetType  model.ReportTargetType     `json:"targetType"`
+	TargetID    string                     `json:"targetId"`
+	Status      model.ReportStatus         `json:"status"`
+	ReportCount int                        `json:"reportCount"`
+	Hidden      bool                       `json:"hidden"`  // 是否已自动隐藏
+	Reasons     map[model.ReportReason]int `json:"reasons"` // 各举报原因的人数
+	Target      *ReportTargetBrief         `json:"target,omitempty"`
+	Resolution  string                     `json:"resolution,omitempty"`
+	Handler     *UserBrief                 `json:"handler,omitempty"`
+	HandledAt   *string                    `json:"handledAt,omitempty"`
+	CreatedAt   string                     `json:"createdAt"`
+	UpdatedAt   string                     `json:"updatedAt"`
+	Reports     []*ReportEntry             `json:"reports,omitempty"` // 举报明细，仅详情返回
+}
+
+// reportTables 可举报对象的表
+var reportTables = map[model.ReportTargetType]string{
+	model.ReportTargetReview:  "reviews",
+	model.ReportTargetComment: "comments",
+}
+
+// reportTarget 被举报对象的归属和状态
+type reportTarget struct {
+	UserID string
+	Status string
+}
+
+type ReportService struct {
+	db                  *gorm.DB
+	cfg                 *config.Config
+	moderationService   *ModerationService
+	notificationService *NotificationService
+	cache               cache.Cache
+}
+
+func NewReportService(db *gorm.DB, cfg *config.Config, moderationService *ModerationService, notificationService *NotificationService, cache cache.Cache) *ReportService {
+	return &ReportService{
+		db:                  db,
+		cfg:                 cfg,
+		moderationService:   moderationService,
+		notificationService: notificationService,
+		cache:               cache,
+	}
+}
+
+// CreateReport 举报测评或评论，同一对象的举报人数达到阈值后自动隐藏，等待管理员处理
+func (s *ReportService) CreateReport(c *gin.Context, userID string, targetType model.ReportTargetType, targetID string, req *CreateReportRequest) (*ReportResponse, error) {
+	if !req.Reason.IsValid() {
+		return nil, ErrInvalidReportReason
+	}
+
+	var report *model.Report
+	var reportCase model.ReportCase
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		target, err := lockReportTarget(tx, targetType, targetID)
+		if err != nil {
+			return err
+		}
+		if target.UserID == userID {
+			return ErrReportOwn
+		}
+
+		var reported int64
+		if err := tx.Model(&model.Report{}).
+			Where("reporter_id = ? AND target_type = ? AND target_id = ?", userID, targetType, targetID).
+			Count(&reported).Error; err != nil {
+			return err
+		}
+		if reported > 0 {
+			return ErrAlreadyReported
+		}
+
+		err = tx.Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, model.ReportStatusPending).
+			First(&reportCase).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			reportCase = model.ReportCase{
+				TargetType: targetType,
+				TargetID:   targetID,
+				Status:     model.ReportStatusPending,
+			}
+			err = tx.Create(&reportCase).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		report = &model.Report{
+			CaseID:      reportCase.ID,
+			ReporterID:  userID,
+			TargetType:  targetType,
+			TargetID:    targetID,
+			Reason:      req.Reason,
+			Description: req.Description,
+		}
+		if err := tx.Create(report).Error; err != nil {
+			return err
+		}
+
+		reportCase.ReportCount++
+		if !reportCase.Hidden && reportCase.ReportCount >= s.cfg.Moderation.ReportHideThreshold {
+			if err := hideReportTarget(tx, &reportCase, target.Status); err != nil {
+				return err
+			}
+		}
+		return tx.Save(&reportCase).Error
+	})
+	if err != nil {
+		switch err {
+		case ErrReviewNotFound, ErrCommentNotFound, ErrReportOwn, ErrAlreadyReported:
+			return nil, err
+		}
+		return nil, ErrInternal
+	}
+	// 只有公开的测评可被举报，举报事项已隐藏说明本次举报触发了自动隐藏
+	if reportCase.Hidden {
+		s.invalidateRatingStats(c, &reportCase)
+	}
+
+	return getReportResponse(report, &reportCase), nil
+}
+
+// lockReportTarget 锁定被举报对象，只能举报公开可见的内容
+func lockReportTarget(tx *gorm.DB, targetType model.ReportTargetType, targetID string) (*reportTarget, error) {
+	notFound := ErrReviewNotFound
+	visible := string(model.ReviewStatusPublished)
+	if targetType == model.ReportTargetComment {
+		notFound = ErrCommentNotFound
+		visible = string(model.CommentStatusApproved)
+	}
+
+	var target reportTarget
+	err := tx.Table(reportTables[targetType]).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("user_id", "status").
+		Where("id = ? AND deleted_at IS NULL", targetID).
+		Take(&target).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, notFound
+		}
+		return nil, err
+	}
+	if target.Status != visible {
+		return nil, notFound
+	}
+	return &target, nil
+}
+
+// hideReportTarget 隐藏被举报的内容：评论退回待审核，测评转为归档
+func hideReportTarget(tx *gorm.DB, reportCase *model.ReportCase, status string) error {
+	switch reportCase.TargetType {
+	case model.ReportTargetComment:
+		if err := tx.Model(&model.Comment{}).Where("id = ?", reportCase.TargetID).
+			Update("status", model.CommentStatusPending).Error; err != nil {
+			return err
+		}
+	case model.ReportTargetReview:
+		if err := tx.Model(&model.Review{}).Where("id = ?", reportCase.TargetID).
+			Update("status", model.ReviewStatusArchived).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&model.ReviewStatusLog{
+			ReviewID:   reportCase.TargetID,
+			FromStatus: model.ReviewStatusPublished,
+			ToStatus:   model.ReviewStatusArchived,
+			Note:       "被 " + strconv.Itoa(reportCase.ReportCount) + " 名用户举报，已自动隐藏等待处理",
+		}).Error; err != nil {
+			return err
+		}
+	}
+	reportCase.Hidden = true
+	reportCase.HiddenStatus = status
+	return nil
+}
+
+// ResolveReport 举报成立：评论标记为已拒绝，测评下架归档
+func (s *ReportService) ResolveReport(c *gin.Context, id, adminID string, req *HandleReportRequest) (*ReportCaseResponse, error) {
+	var rejected *model.Comment
+	reportCase, err := s.handleReport(id, adminID, model.ReportStatusResolved, req, func(tx *gorm.DB, reportCase *model.ReportCase) error {
+		switch reportCase.TargetType {
+		case model.ReportTargetComment:
+			var comment model.Comment
+			if err := tx.Select("id", "review_id", "user_id", "content").First(&comment, "id = ?", reportCase.TargetID).Error; err != nil {
+				// 评论已被删除时无需处理
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil
+				}
+				return err
+			}
+			comment.Status = model.CommentStatusRejected
+			rejected = &comment
+			return tx.Model(&model.Comment{}).Where("id = ?", comment.ID).
+				Update("status", model.CommentStatusRejected).Error
+		case model.ReportTargetReview:
+			result := tx.Model(&model.Review{}).
+				Where("id = ? AND status = ?", reportCase.TargetID, model.ReviewStatusPublished).
+				Update("status", model.ReviewStatusArchived)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			return tx.Create(&model.ReviewStatusLog{
+				ReviewID:   reportCase.TargetID,
+				FromStatus: model.ReviewStatusPublished,
+				ToStatus:   model.ReviewStatusArchived,
+				ActorID:    &adminID,
+				Note:       reportNote("举报成立，已下架", req.Resolution),
+			}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if rejected != nil {
+		s.moderationService.RecordDecision(c.Request.Context(), rejected.UserID, model.CommentStatusRejected)
+		s.notificationService.NotifyCommentModeration(c.Request.Context(), rejected, reportNote("因被举报违规已下架", req.Resolution))
+	}
+	s.invalidateRatingStats(c, reportCase)
+	s.notificationService.NotifyReportHandled(c.Request.Context(), reportCase)
+	return s.GetReportCase(c, reportCase.ID)
+}
+
+// DismissReport 举报不成立：恢复被自动隐藏的内容
+func (s *ReportService) DismissReport(c *gin.Context, id, adminID string, req *HandleReportRequest) (*ReportCaseResponse, error) {
+	reportCase, err := s.handleReport(id, adminID, model.ReportStatusDismissed, req, func(tx *gorm.DB, reportCase *model.ReportCase) error {
+		if !reportCase.Hidden {
+			return nil
+		}
+		switch reportCase.TargetType {
+		case model.ReportTargetComment:
+			// 隐藏期间已被管理员审核过的评论保持现状
+			return tx.Model(&model.Comment{}).
+				Where("id = ? AND status = ?", reportCase.TargetID, model.CommentStatusPending).
+				Update("status", reportCase.HiddenStatus).Error
+		case model.ReportTargetReview:
+			result := tx.Model(&model.Review{}).
+				Where("id = ? AND status = ?", reportCase.TargetID, model.ReviewStatusArchived).
+				Update("status", reportCase.HiddenStatus)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			return tx.Create(&model.ReviewStatusLog{
+				ReviewID:   reportCase.TargetID,
+				FromStatus: model.ReviewStatusArchived,
+				ToStatus:   model.ReviewStatus(reportCase.HiddenStatus),
+				ActorID:    &adminID,
+				Note:       reportNote("举报不成立，已恢复", req.Resolution),
+			}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reportCase.Hidden {
+		s.invalidateRatingStats(c, reportCase)
+	}
+	s.notificationService.NotifyReportHandled(c.Request.Context(), reportCase)
+	return s.GetReportCase(c, reportCase.ID)
+}
+
+// handleReport 锁定待处理的举报事项，执行处理动作并记录处理结果
+func (s *ReportService) handleReport(id, adminID string, status model.ReportStatus, req *HandleReportRequest, action func(tx *gorm.DB, reportCase *model.ReportCase) error) (*model.ReportCase, error) {
+	var reportCase model.ReportCase
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reportCase, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReportCaseNotFound
+			}
+			return err
+		}
+		if reportCase.Status != model.ReportStatusPending {
+			return ErrReportCaseHandled
+		}
+
+		if err := action(tx, &reportCase); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		reportCase.Status = status
+		reportCase.Resolution = req.Resolution
+		reportCase.HandledBy = &adminID
+		reportCase.HandledAt = &now
+		return tx.Save(&reportCase).Error
+	})
+	if err != nil {
+		switch err {
+		case ErrReportCaseNotFound, ErrReportCaseHandled:
+			return nil, err
+		}
+		return nil, ErrInternal
+	}
+	return &reportCase, nil
+}
+
+// invalidateRatingStats 被举报测评的发布状态变化后清除所属产品的评分统计缓存
+func (s *ReportService) invalidateRatingStats(ctx context.Context, reportCase *model.ReportCase) {
+	if reportCase.TargetType != model.ReportTargetReview {
+		return
+	}
+	var review model.Review
+	if err := s.db.Unscoped().Select("product_id").First(&review, "id = ?", reportCase.TargetID).Error; err != nil {
+		return
+	}
+	if err := s.cache.DeleteRatingStats(ctx, review.ProductID); err != nil {
+		zap.L().Warn("清除评分统计缓存失败", zap.Uint("productID", review.ProductID), zap.Error(err))
+	}
+}
+
+func reportNote(action, resolution string) string {
+	if resolution == "" {
+		return action
+	}
+	return action + "：" + resolution
+}
+
+// ListReportCases 获取举报处理队列，举报人数多的优先
+func (s *ReportService) ListReportCases(c *gin.Context, status string, targetType string, page, pageSize int) ([]*ReportCaseResponse, int64, error) {
+	query := s.db.Model(&model.ReportCase{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	var cases []*model.ReportCase
+	if err := query.Preload("Handler").
+		Order("report_count DESC, created_at ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&cases).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	responses, err := s.getReportCaseResponses(cases)
+	if err != nil {
+		return nil, 0, err
+	}
+	return responses, total, nil
+}
+
+// GetReportCase 获取举报事项详情，包括每条举报
+func (s *ReportService) GetReportCase(c *gin.Context, id string) (*ReportCaseResponse, error) {
+	reportCase := &model.ReportCase{}
+	if err := s.db.Preload("Handler").Preload("Reports", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Preload("Reports.Reporter").First(reportCase, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReportCaseNotFound
+		}
+		return nil, ErrInternal
+	}
+
+	responses, err := s.getReportCaseResponses([]*model.ReportCase{reportCase})
+	if err != nil {
+		return nil, err
+	}
+	response := responses[0]
+	response.Reports = make([]*ReportEntry, len(reportCase.Reports))
+	for i, report := range reportCase.Reports {
+		response.Reports[i] = &ReportEntry{
+			ID: report.ID,
+			Reporter: &UserBrief{
+				ID:     report.Reporter.ID,
+				Name:   report.Reporter.Name,
+				Avatar: report.Reporter.Avatar,
+			},
+			Reason:      report.Reason,
+			Description: report.Description,
+			CreatedAt:   report.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+	return response, nil
+}
+
+// ListMyReports 获取当前用户的举报记录及处理结果
+func (s *ReportService) ListMyReports(c *gin.Context, userID string, page, pageSize int) ([]*ReportResponse, int64, error) {
+	query := s.db.Model(&model.Report{}).Where("reporter_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	var reports []*model.Report
+	if err := query.Order("created_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&reports).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	caseIDs := make([]string, len(reports))
+	for i, report := range reports {
+		caseIDs[i] = report.CaseID
+	}
+	var cases []model.ReportCase
+	if len(caseIDs) > 0 {
+		if err := s.db.Where("id IN ?", caseIDs).Find(&cases).Error; err != nil {
+			return nil, 0, ErrInternal
+		}
+	}
+	casesByID := make(map[string]*model.ReportCase, len(cases))
+	for i := range cases {
+		casesByID[cases[i].ID] = &cases[i]
+	}
+
+	responses := make([]*ReportResponse, len(reports))
+	for i, report := range reports {
+		responses[i] = getReportResponse(report, casesByID[report.CaseID])
+	}
+	return responses, total, nil
+}
+
+// getReportCaseResponses 批量转换举报事项，一次性查询举报原因统计和被举报内容
+func (s *ReportService) getReportCaseResponses(cases []*model.ReportCase) ([]*ReportCaseResponse, error) {
+	responses := make([]*ReportCaseResponse, len(cases))
+	if len(cases) == 0 {
+		return responses, nil
+	}
+
+	caseIDs := make([]string, len(cases))
+	targetIDs := map[model.ReportTargetType][]string{}
+	for i, reportCase := range cases {
+		caseIDs[i] = reportCase.ID
+		targetIDs[reportCase.TargetType] = append(targetIDs[reportCase.TargetType], reportCase.TargetID)
+	}
+
+	var reasonRows []struct {
+		CaseID string
+		Reason model.ReportReason
+		Count  int
+	}
+	if err := s.db.Model(&model.Report{}).
+		Select("case_id, reason, COUNT(*) AS count").
+		Where("case_id IN ?", caseIDs).
+		Group("case_id, reason").
+		Scan(&reasonRows).Error; err != nil {
+		return nil, ErrInternal
+	}
+	reasons := make(map[string]map[model.ReportReason]int)
+	for _, row := range reasonRows {
+		if reasons[row.CaseID] == nil {
+			reasons[row.CaseID] = make(map[model.ReportReason]int)
+		}
+		reasons[row.CaseID][row.Reason] = row.Count
+	}
+
+	targets, err := s.loadReportTargets(targetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, reportCase := range cases {
+		response := &ReportCaseResponse{
+			ID:          reportCase.ID,
+			TargetType:  reportCase.TargetType,
+			TargetID:    reportCase.TargetID,
+			Status:      reportCase.Status,
+			ReportCount: reportCase.ReportCount,
+			Hidden:      reportCase.Hidden,
+			Reasons:     reasons[reportCase.ID],
+			Target:      targets[string(reportCase.TargetType)+":"+reportCase.TargetID],
+			Resolution:  reportCase.Resolution,
+			HandledAt:   formatOptionalTime(reportCase.HandledAt),
+			CreatedAt:   reportCase.CreatedAt.Format("2006-01-02 15:04:05"),
+			UpdatedAt:   reportCase.UpdatedAt.Format("2006-01-02 15:04:05"),
+		}
+		if response.Reasons == nil {
+			response.Reasons = map[model.ReportReason]int{}
+		}
+		if reportCase.Handler != nil {
+			response.Handler = &UserBrief{
+				ID:     reportCase.Handler.ID,
+				Name:   reportCase.Handler.Name,
+				Avatar: reportCase.Handler.Avatar,
+			}
+		}
+		responses[i] = response
+	}
+	return responses, nil
+}
+
+// loadReportTargets 批量查询被举报的内容，已删除的内容同样返回以便追溯
+func (s *ReportService) loadReportTargets(ids map[model.ReportTargetType][]string) (map[string]*ReportTargetBrief, error) {
+	targets := make(map[string]*ReportTargetBrief)
+
+	if reviewIDs := ids[model.ReportTargetReview]; len(reviewIDs) > 0 {
+		var reviews []model.Review
+		if err := s.db.Unscoped().Preload("Author").
+			Select("id", "user_id", "title", "slug", "content", "status").
+			Where("id IN ?", reviewIDs).
+			Find(&reviews).Error; err != nil {
+			return nil, ErrInternal
+		}
+		for _, review := range reviews {
+			targets[string(model.ReportTargetReview)+":"+review.ID] = &ReportTargetBrief{
+				Title:   review.Title,
+				Slug:    review.Slug,
+				Content: excerpt(review.Content, reportExcerptLength),
+				Status:  string(review.Status),
+				Author: &UserBrief{
+					ID:     review.Author.ID,
+					Name:   review.Author.Name,
+					Avatar: review.Author.Avatar,
+				},
+			}
+		}
+	}
+
+	if commentIDs := ids[model.ReportTargetComment]; len(commentIDs) > 0 {
+		var comments []model.Comment
+		if err := s.db.Unscoped().Preload("User").
+			Select("id", "user_id", "content", "status").
+			Where("id IN ?", commentIDs).
+			Find(&comments).Error; err != nil {
+			return nil, ErrInternal
+		}
+		for _, comment := range comments {
+			targets[string(model.ReportTargetComment)+":"+comment.ID] = &ReportTargetBrief{
+				Content: excerpt(comment.Content, reportExcerptLength),
+				Status:  string(comment.Status),
+				Author: &UserBrief{
+					ID:     comment.User.ID,
+					Name:   comment.User.Name,
+					Avatar: comment.User.Avatar,
+				},
+			}
+		}
+	}
+	return targets, nil
+}
+
+func getReportResponse(report *model.Report, reportCase *model.ReportCase) *ReportResponse {
+	response := &ReportResponse{
+		ID:          report.ID,
+		TargetType:  report.TargetType,
+		TargetID:    report.TargetID,
+		Reason:      report.Reason,
+		Description: report.Description,
+		Status:      model.ReportStatusPending,
+		CreatedAt:   report.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if reportCase != nil {
+		response.Status = reportCase.Status
+		response.Resolution = reportCase.Resolution
+		response.HandledAt = formatOptionalTime(reportCase.HandledAt)
+	}
+	return response
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format("2006-01-02 15:04:05")
+	return &formatted
+}
+
+// excerpt 截取前 n 个字符
+func excerpt(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "…"
+}
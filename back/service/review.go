@@ -1,13 +1,18 @@
 package service
 
 import (
+	"beicun/back/cache"
+	"beicun/back/config"
 	"beicun/back/model"
+	"beicun/back/utils"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"github.com/lib/pq"
 	"github.com/gin-gonic/gin"
-	"github.com/gosimple/slug"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
@@ -20,20 +25,42 @@ type CreateReviewRequest struct {
 	Title       string   `json:"title" binding:"required"`
 	Cover       string   `json:"cover" binding:"required"`
 	ProductID   uint     `json:"productId" binding:"required"`
+	VariantID   *string  `json:"variantId,omitempty" binding:"omitempty,uuid"`
 	Content     string   `json:"content" binding:"required"`
 	Pros        []string `json:"pros" binding:"required,min=1"`
 	Cons        []string `json:"cons" binding:"required,min=1"`
 	Conclusion  string   `json:"conclusion" binding:"required"`
+	Scores      []ReviewScoreInput `json:"scores,omitempty" binding:"omitempty,dive"` // 维度评分
 }
 
 type UpdateReviewRequest struct {
 	Title       string   `json:"title,omitempty"`
 	Cover       string   `json:"cover,omitempty"`
+	VariantID   *string  `json:"variantId,omitempty" binding:"omitempty,len=0|uuid"` // 传空字符串表示取消关联版本
 	Content     string   `json:"content,omitempty"`
 	Pros        []string `json:"pros,omitempty"`
 	Cons        []string `json:"cons,omitempty"`
 	Conclusion  string   `json:"conclusion,omitempty"`
-	Status      string   `json:"status,omitempty"`
+	RevisionNote string  `json:"revisionNote,omitempty"` // 修订说明
+	Scores      []ReviewScoreInput `json:"scores,omitempty" binding:"omitempty,dive"` // 维度评分，传入时整体替换
+}
+
+// 测评列表排序方式
+const (
+	ReviewSortLatest  = "latest"  // 最新发布
+	ReviewSortHelpful = "helpful" // 最有帮助
+)
+
+// reviewOrder 根据排序方式返回排序语句，未指定时使用默认排序
+func reviewOrder(sort, fallback string) string {
+	switch sort {
+	case ReviewSortLatest:
+		return "published_at DESC NULLS LAST, created_at DESC"
+	case ReviewSortHelpful:
+		// Wilson 下界抑制少量反馈带来的波动
+		return "helpful_score DESC, helpful_count DESC, created_at DESC"
+	}
+	return fallback
 }
 
 type ReviewResponse struct {
@@ -44,15 +71,26 @@ type ReviewResponse struct {
 	Status        model.ReviewStatus `json:"status"`
 	ProductID     uint        `json:"productId"`
 	Product       *ProductBrief  `json:"product,omitempty"`
+	VariantID     *string        `json:"variantId,omitempty"`
+	Variant       *VariantBrief  `json:"variant,omitempty"`
 	UserID        string         `json:"userId"`
 	Author        *UserBrief     `json:"author,omitempty"`
-	Content       string         `json:"content"`
+	ReviewerID    *string        `json:"reviewerId,omitempty"`
+	Reviewer      *UserBrief     `json:"reviewer,omitempty"`
+	Content       string         `json:"content"`                // Markdown 原文
+	ContentHTML   string         `json:"contentHtml"`            // 渲染并净化后的 HTML
+	TOC           []utils.TOCItem `json:"toc"`                   // 正文目录
+	ReadingTime   int            `json:"readingTime"`            // 预计阅读时长（分钟）
 	Pros          []string       `json:"pros"`
 	Cons          []string       `json:"cons"`
 	Conclusion    string         `json:"conclusion"`
+	Scores        []ReviewScoreResponse `json:"scores"`
 	Views         int            `json:"views"`
+	HelpfulCount    int          `json:"helpfulCount"`    // 有帮助数
+	NotHelpfulCount int          `json:"notHelpfulCount"` // 没帮助数
 	IsRecommended bool           `json:"isRecommended"`
 	PublishedAt   *string        `json:"publishedAt,omitempty"`
+	ScheduledAt   *string        `json:"scheduledAt,omitempty"`
 	CreatedAt     string         `json:"createdAt"`
 	UpdatedAt     string         `json:"updatedAt"`
 }
@@ -68,6 +106,12 @@ type ProductBrief struct {
 	ViewCount        int                  `json:"viewCount"`
 }
 
+// VariantBrief 产品版本简要信息
+type VariantBrief struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 type UserBrief struct {
 	ID     string `json:"id"`
 	Name   string `json:"name"`
@@ -76,46 +120,89 @@ type UserBrief struct {
 
 type ReviewService struct {
 	db *gorm.DB
+	cfg *config.Config
 	productService *ProductService
+	viewService *ViewService
+	notificationService *NotificationService
+	cache cache.Cache
 }
 
-func NewReviewService(db *gorm.DB, productService *ProductService) *ReviewService {
-	return &ReviewService{db: db, productService: productService}
+func NewReviewService(db *gorm.DB, cfg *config.Config, productService *ProductService, viewService *ViewService, notificationService *NotificationService, cache cache.Cache) *ReviewService {
+	return &ReviewService{db: db, cfg: cfg, productService: productService, viewService: viewService, notificationService: notificationService, cache: cache}
 }
 
 // CreateReview 创建测评
 func (s *ReviewService) CreateReview(c *gin.Context, userID string, req *CreateReviewRequest) (*ReviewResponse, error) {
+	review, err := s.newReview(c, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	review.Status = model.ReviewStatusDraft
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+		if err := s.saveReviewScores(tx, review, req.Scores); err != nil {
+			return err
+		}
+		return s.createRevision(tx, review, userID, "初始版本")
+	})
+	if err != nil {
+		return nil, ErrInternal
+	}
+
+	return s.getReviewResponse(review)
+}
+
+// newReview 校验请求并构建测评
+func (s *ReviewService) newReview(c *gin.Context, userID string, req *CreateReviewRequest) (*model.Review, error) {
 	// 检查产品是否存在
 	var product model.Product
 	if err := s.db.First(&product, "id = ?", req.ProductID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrReviewNotFound
+			return nil, ErrProductNotFound
 		}
 		return nil, ErrInternal
 	}
 
+	// 检查版本是否属于该产品
+	if req.VariantID != nil {
+		if _, err := s.productService.GetVariant(c, req.ProductID, *req.VariantID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.validateReviewScores(&product, req.Scores); err != nil {
+		return nil, err
+	}
+
+	reviewSlug, err := s.generateReviewSlug(req.Title, "")
+	if err != nil {
+		return nil, ErrInternal
+	}
+
 	review := &model.Review{
 		Title:       req.Title,
 		Cover:       req.Cover,
-		Slug:        slug.Make(req.Title),
+		Slug:        reviewSlug,
 		ProductID:   req.ProductID,
+		VariantID:   req.VariantID,
 		UserID:      userID,
 		Content:     req.Content,
 		Pros:        req.Pros,  // pq.StringArray 会自动处理类型转换
 		Cons:        req.Cons,  // pq.StringArray 会自动处理类型转换
 		Conclusion:  req.Conclusion,
-		Status:      model.ReviewStatusPending,
 	}
-
-	if err := s.db.Create(review).Error; err != nil {
+	if err := renderReviewContent(s.db, review); err != nil {
 		return nil, ErrInternal
 	}
 
-	return s.getReviewResponse(review)
+	return review, nil
 }
 
 // UpdateReview 更新测评
-func (s *ReviewService) UpdateReview(c *gin.Context, id string, req *UpdateReviewRequest) (*ReviewResponse, error) {
+func (s *ReviewService) UpdateReview(c *gin.Context, id string, userID string, req *UpdateReviewRequest) (*ReviewResponse, error) {
 	review := &model.Review{}
 	if err := s.db.First(review, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -123,16 +210,46 @@ func (s *ReviewService) UpdateReview(c *gin.Context, id string, req *UpdateRevie
 		}
 		return nil, ErrInternal
 	}
+	if err := checkReviewAccess(c, review, userID); err != nil {
+		return nil, err
+	}
+	if req.Scores != nil {
+		var product model.Product
+		if err := s.db.Select("id", "utility_type_id").First(&product, "id = ?", review.ProductID).Error; err != nil {
+			return nil, ErrInternal
+		}
+		if err := s.validateReviewScores(&product, req.Scores); err != nil {
+			return nil, err
+		}
+	}
+	before := *review
 
-	if req.Title != "" {
+	if req.Title != "" && req.Title != review.Title {
+		reviewSlug, err := s.generateReviewSlug(req.Title, review.ID)
+		if err != nil {
+			return nil, ErrInternal
+		}
 		review.Title = req.Title
-		review.Slug = slug.Make(req.Title)
+		review.Slug = reviewSlug
 	}
 	if req.Cover != "" {
 		review.Cover = req.Cover
 	}
-	if req.Content != "" {
+	if req.VariantID != nil {
+		if *req.VariantID == "" {
+			review.VariantID = nil
+		} else {
+			if _, err := s.productService.GetVariant(c, review.ProductID, *req.VariantID); err != nil {
+				return nil, err
+			}
+			review.VariantID = req.VariantID
+		}
+	}
+	if req.Content != "" && req.Content != review.Content {
 		review.Content = req.Content
+		if err := renderReviewContent(s.db, review); err != nil {
+			return nil, ErrInternal
+		}
 	}
 	if len(req.Pros) > 0 {
 		review.Pros = pq.StringArray(req.Pros)
@@ -143,19 +260,47 @@ func (s *ReviewService) UpdateReview(c *gin.Context, id string, req *UpdateRevie
 	if req.Conclusion != "" {
 		review.Conclusion = req.Conclusion
 	}
-	if req.Status != "" {
-		review.Status = model.ReviewStatus(req.Status)
-	}
 
-	if err := s.db.Save(review).Error; err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(review).Error; err != nil {
+			return err
+		}
+		if req.Scores != nil {
+			if err := s.saveReviewScores(tx, review, req.Scores); err != nil {
+				return err
+			}
+		}
+		if err := recordSlugChange(tx, model.SlugEntityReview, review.ID, before.Slug, review.Slug); err != nil {
+			return err
+		}
+		if !revisionContentChanged(&before, review) {
+			return nil
+		}
+		// 修订功能上线前创建的测评先补录原始内容
+		if err := s.ensureInitialRevision(tx, &before); err != nil {
+			return err
+		}
+		return s.createRevision(tx, review, userID, req.RevisionNote)
+	})
+	if err != nil {
 		return nil, ErrInternal
 	}
+	if req.Scores != nil && review.Status == model.ReviewStatusPublished {
+		s.invalidateRatingStats(c, review.ProductID)
+	}
 
 	return s.getReviewResponse(review)
 }
 
 // DeleteReview 删除测评
 func (s *ReviewService) DeleteReview(c *gin.Context, id string) error {
+	var review model.Review
+	if err := s.db.Select("id", "product_id", "status").First(&review, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrReviewNotFound
+		}
+		return ErrInternal
+	}
 	result := s.db.Delete(&model.Review{}, "id = ?", id)
 	if result.Error != nil {
 		return ErrInternal
@@ -163,22 +308,35 @@ func (s *ReviewService) DeleteReview(c *gin.Context, id string) error {
 	if result.RowsAffected == 0 {
 		return ErrReviewNotFound
 	}
+	if review.Status == model.ReviewStatusPublished {
+		s.invalidateRatingStats(c, review.ProductID)
+	}
 	return nil
 }
 
+// invalidateRatingStats 清除产品评分统计缓存，维度评分只统计已发布的测评
+func (s *ReviewService) invalidateRatingStats(ctx context.Context, productID uint) {
+	if err := s.cache.DeleteRatingStats(ctx, productID); err != nil {
+		zap.L().Warn("清除评分统计缓存失败", zap.Uint("productID", productID), zap.Error(err))
+	}
+}
+
 // GetReview 获取测评详情
 func (s *ReviewService) GetReview(c *gin.Context, id string) (*ReviewResponse, error) {
 	review := &model.Review{}
-	if err := s.db.Preload("Product").Preload("Author").First(review, "id = ?", id).Error; err != nil {
+	if err := s.db.Preload("Product").Preload("Variant").Preload("Author").Preload("Scores.Dimension").Preload("Reviewer").First(review, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrReviewNotFound
 		}
 		return nil, ErrInternal
 	}
+	if !canViewReview(c, review) {
+		return nil, ErrReviewNotFound
+	}
 
-	// 增加浏览量
-	if err := s.db.Model(review).UpdateColumn("views", gorm.Expr("views + ?", 1)).Error; err != nil {
-		return nil, ErrInternal
+	// 只记录已发布测评的浏览量
+	if review.Status == model.ReviewStatusPublished {
+		s.viewService.RecordView(c, ViewTargetReview, review.ID)
 	}
 
 	return s.getReviewResponse(review)
@@ -187,30 +345,32 @@ func (s *ReviewService) GetReview(c *gin.Context, id string) (*ReviewResponse, e
 // GetReviewBySlug 通过 slug 获取测评详情
 func (s *ReviewService) GetReviewBySlug(c *gin.Context, slug string) (*ReviewResponse, error) {
 	review := &model.Review{}
-	if err := s.db.Preload("Product").Preload("Author").First(review, "slug = ?", slug).Error; err != nil {
+	if err := s.db.Preload("Product").Preload("Variant").Preload("Author").Preload("Scores.Dimension").First(review, "slug = ?", slug).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrReviewNotFound
+			return nil, s.resolveMovedReviewSlug(slug, func(r *model.Review) bool { return canViewReview(c, r) })
 		}
 		return nil, ErrInternal
 	}
+	if !canViewReview(c, review) {
+		return nil, ErrReviewNotFound
+	}
 
-	// 增加浏览量
-	if err := s.db.Model(review).UpdateColumn("views", gorm.Expr("views + ?", 1)).Error; err != nil {
-		return nil, ErrInternal
+	// 只记录已发布测评的浏览量
+	if review.Status == model.ReviewStatusPublished {
+		s.viewService.RecordView(c, ViewTargetReview, review.ID)
 	}
 
 	return s.getReviewResponse(review)
 }
 
-// ListReviews 获取测评列表
-func (s *ReviewService) ListReviews(c *gin.Context, page, pageSize int, status string) ([]*ReviewResponse, int64, error) {
+// ListReviews 获取测评列表，只有编辑和管理员可以按状态筛选，其他用户只能看到已发布的测评
+func (s *ReviewService) ListReviews(c *gin.Context, page, pageSize int, status, sort string) ([]*ReviewResponse, int64, error) {
 	var total int64
 	query := s.db.Model(&model.Review{})
 
-	if status != "" {
+	if status != "" && isReviewEditor(c) {
 		query = query.Where("status = ?", status)
 	} else {
-		// 默认只显示已发布的测评
 		query = query.Where("status = ?", model.ReviewStatusPublished)
 	}
 
@@ -219,8 +379,8 @@ func (s *ReviewService) ListReviews(c *gin.Context, page, pageSize int, status s
 	}
 
 	var reviews []*model.Review
-	if err := query.Preload("Product").Preload("Author").
-		Order("is_recommended DESC, created_at DESC").
+	if err := query.Preload("Product").Preload("Variant").Preload("Author").Preload("Scores.Dimension").
+		Order(reviewOrder(sort, "is_recommended DESC, created_at DESC")).
 		Offset((page - 1) * pageSize).
 		Limit(pageSize).
 		Find(&reviews).Error; err != nil {
@@ -239,8 +399,49 @@ func (s *ReviewService) ListReviews(c *gin.Context, page, pageSize int, status s
 	return responses, total, nil
 }
 
+// isReviewEditor 当前用户是否为编辑或管理员
+func isReviewEditor(c *gin.Context) bool {
+	switch utils.GetUserRoleFromContext(c) {
+	case model.UserRoleAdmin, model.UserRoleEditor:
+		return true
+	}
+	return false
+}
+
+// canViewReview 已发布的测评公开可见；草稿、审核中、已归档（含举报自动隐藏）等状态只有作者、编辑和管理员可见
+func canViewReview(c *gin.Context, review *model.Review) bool {
+	if review.Status == model.ReviewStatusPublished || isReviewEditor(c) {
+		return true
+	}
+	userID := utils.GetUserIDFromContext(c)
+	return userID != "" && review.UserID == userID
+}
+
+// resolveMovedReviewSlug 在 slug 历史中查找测评，测评对当前用户不可见时按不存在处理，避免泄露未发布测评的新 slug
+func (s *ReviewService) resolveMovedReviewSlug(slug string, visible func(*model.Review) bool) error {
+	var history model.SlugHistory
+	if err := s.db.Where("entity_type = ? AND slug = ?", model.SlugEntityReview, slug).First(&history).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrReviewNotFound
+		}
+		return ErrInternal
+	}
+
+	var review model.Review
+	if err := s.db.Select("id", "slug", "status", "user_id").First(&review, "id = ?", history.EntityID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrReviewNotFound
+		}
+		return ErrInternal
+	}
+	if !visible(&review) {
+		return ErrReviewNotFound
+	}
+	return &SlugMovedError{EntityType: model.SlugEntityReview, Slug: review.Slug}
+}
+
 // ListProductReviews 获取产品的测评列表
-func (s *ReviewService) ListProductReviews(c *gin.Context, productID uint, page, pageSize int) ([]*ReviewResponse, int64, error) {
+func (s *ReviewService) ListProductReviews(c *gin.Context, productID uint, variantID, sort string, page, pageSize int) ([]*ReviewResponse, int64, error) {
 	// 检查产品是否存在
 	var product model.Product
 	if err := s.db.First(&product, "id = ?", productID).Error; err != nil {
@@ -250,33 +451,17 @@ func (s *ReviewService) ListProductReviews(c *gin.Context, productID uint, page,
 		return nil, 0, ErrInternal
 	}
 
-	var total int64
-	query := s.db.Model(&model.Review{}).
-		Where("product_id = ? AND status = ?", productID, model.ReviewStatusPublished)
-
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, ErrInternal
+	query := s.db.Model(&model.Review{}).Where("product_id = ?", productID)
+	if variantID != "" {
+		query = query.Where("variant_id = ?", variantID)
 	}
 
-	var reviews []*model.Review
-	if err := query.Preload("Product").Preload("Author").
-		Order("is_recommended DESC, created_at DESC").
-		Offset((page - 1) * pageSize).
-		Limit(pageSize).
-		Find(&reviews).Error; err != nil {
-		return nil, 0, ErrInternal
-	}
-
-	responses := make([]*ReviewResponse, len(reviews))
-	for i, review := range reviews {
-		response, err := s.getReviewResponse(review)
-		if err != nil {
-			return nil, 0, ErrInternal
-		}
-		responses[i] = response
-	}
+	return s.listPublishedReviews(query, reviewOrder(sort, "is_recommended DESC, created_at DESC"), page, pageSize)
+}
 
-	return responses, total, nil
+// generateReviewSlug 生成唯一的测评 slug
+func (s *ReviewService) generateReviewSlug(title, excludeID string) (string, error) {
+	return generateEntitySlug(s.db, &model.Review{}, model.SlugEntityReview, title, excludeID)
 }
 
 // GetProductByReviewSlug 通过测评 slug 获取产品详情
@@ -285,7 +470,7 @@ func (s *ReviewService) GetProductByReviewSlug(c *gin.Context, slug string) (*Pr
 	review := &model.Review{}
 	if err := s.db.First(review, "slug = ?", slug).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrReviewNotFound
+			return nil, s.resolveMovedReviewSlug(slug, func(r *model.Review) bool { return r.Status == model.ReviewStatusPublished })
 		}
 		return nil, ErrInternal
 	}
@@ -313,30 +498,79 @@ func (s *ReviewService) getReviewResponse(review *model.Review) (*ReviewResponse
 		Slug:          review.Slug,
 		Status:        review.Status,
 		ProductID:     review.ProductID,
+		VariantID:     review.VariantID,
 		UserID:        review.UserID,
+		ReviewerID:    review.ReviewerID,
 		Content:       review.Content,
+		ContentHTML:   review.ContentHTML,
+		TOC:           make([]utils.TOCItem, 0),
+		ReadingTime:   review.ReadingTime,
 		Pros:          review.Pros,
 		Cons:          review.Cons,
 		Conclusion:    review.Conclusion,
 		Views:         review.Views,
+		HelpfulCount:    review.HelpfulCount,
+		NotHelpfulCount: review.NotHelpfulCount,
 		IsRecommended: review.IsRecommended,
 		CreatedAt:     review.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:     review.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
 
+	if len(review.TOC) > 0 {
+		if err := json.Unmarshal(review.TOC, &response.TOC); err != nil {
+			return nil, err
+		}
+	}
+
 	if review.PublishedAt != nil {
 		publishedAt := review.PublishedAt.Format("2006-01-02 15:04:05")
 		response.PublishedAt = &publishedAt
 	}
 
+	if review.ScheduledAt != nil {
+		scheduledAt := review.ScheduledAt.Format("2006-01-02 15:04:05")
+		response.ScheduledAt = &scheduledAt
+	}
+
+	if review.Reviewer != nil && review.Reviewer.ID != "" {
+		response.Reviewer = &UserBrief{
+			ID:     review.Reviewer.ID,
+			Name:   review.Reviewer.Name,
+			Avatar: review.Reviewer.Avatar,
+		}
+	}
+
 	if review.Product != nil && review.Product.ID != 0 {
-		productBrief, err := s.toProductBrief(review.Product)
+		productBrief, err := toProductBrief(review.Product)
 		if err != nil {
 			return nil, err
 		}
 		response.Product = productBrief
 	}
 
+	if review.Variant != nil && review.Variant.ID != "" {
+		response.Variant = &VariantBrief{
+			ID:   review.Variant.ID,
+			Name: review.Variant.Name,
+		}
+	}
+
+	// 未预加载维度评分时单独查询
+	if review.Scores == nil {
+		if err := s.db.Preload("Dimension").Where("review_id = ?", review.ID).Find(&review.Scores).Error; err != nil {
+			return nil, err
+		}
+	}
+	response.Scores = make([]ReviewScoreResponse, 0, len(review.Scores))
+	for _, score := range review.Scores {
+		response.Scores = append(response.Scores, ReviewScoreResponse{
+			DimensionID: score.DimensionID,
+			Key:         score.Dimension.Key,
+			Name:        score.Dimension.Name,
+			Score:       score.Score,
+		})
+	}
+
 	if review.Author.ID != "" {
 		response.Author = &UserBrief{
 			ID:     review.Author.ID,
@@ -348,7 +582,7 @@ func (s *ReviewService) getReviewResponse(review *model.Review) (*ReviewResponse
 	return response, nil
 }
 
-func (s *ReviewService) toProductBrief(product *model.Product) (*ProductBrief, error) {
+func toProductBrief(product *model.Product) (*ProductBrief, error) {
 	var mainImages []model.MainImage
 	
 	if len(product.MainImage) > 0 {
@@ -367,3 +601,62 @@ func (s *ReviewService) toProductBrief(product *model.Product) (*ProductBrief, e
 		ViewCount:     product.ViewCount,
 	}, nil
 }
+
+// validateReviewScores 校验维度评分：维度必须属于产品的器具类型，且分数在有效范围内
+func (s *ReviewService) validateReviewScores(product *model.Product, scores []ReviewScoreInput) error {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(scores))
+	seen := make(map[string]bool, len(scores))
+	for _, score := range scores {
+		if score.Score < model.MinDimensionScore || score.Score > model.MaxDimensionScore {
+			return ErrInvalidScore
+		}
+		if seen[score.DimensionID] {
+			continue
+		}
+		seen[score.DimensionID] = true
+		ids = append(ids, score.DimensionID)
+	}
+
+	var count int64
+	if err := s.db.Model(&model.ScoreDimension{}).
+		Where("id IN ? AND utility_type_id = ?", ids, product.UtilityTypeID).
+		Count(&count).Error; err != nil {
+		return ErrInternal
+	}
+	if count != int64(len(ids)) {
+		return ErrDimensionNotFound
+	}
+	return nil
+}
+
+// saveReviewScores 整体替换测评的维度评分
+func (s *ReviewService) saveReviewScores(tx *gorm.DB, review *model.Review, scores []ReviewScoreInput) error {
+	if err := tx.Where("review_id = ?", review.ID).Delete(&model.ReviewScore{}).Error; err != nil {
+		return err
+	}
+
+	review.Scores = make([]model.ReviewScore, 0, len(scores))
+	seen := make(map[string]bool, len(scores))
+	for _, score := range scores {
+		if seen[score.DimensionID] {
+			continue
+		}
+		seen[score.DimensionID] = true
+		review.Scores = append(review.Scores, model.ReviewScore{
+			ReviewID:    review.ID,
+			DimensionID: score.DimensionID,
+			Score:       math.Round(score.Score*10) / 10,
+		})
+	}
+	if len(review.Scores) == 0 {
+		return nil
+	}
+	if err := tx.Create(&review.Scores).Error; err != nil {
+		return err
+	}
+	return tx.Preload("Dimension").Where("review_id = ?", review.ID).Find(&review.Scores).Error
+}
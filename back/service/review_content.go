@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"beicun/back/model"
+	"beicun/back/utils"
+)
+
+// fileImagePrefix Markdown 中引用已上传文件的前缀，如 ![](file:<文件ID>)
+const fileImagePrefix = "file:"
+
+// renderReviewContent 渲染测评正文，图片引用替换为文件地址并补充宽高
+func renderReviewContent(db *gorm.DB, review *model.Review) error {
+	images, err := resolveContentImages(db, utils.MarkdownImageDestinations(review.Content))
+	if err != nil {
+		return err
+	}
+
+	rendered, err := utils.RenderMarkdown(review.Content, func(dest string) *utils.MarkdownImage {
+		return images[dest]
+	})
+	if err != nil {
+		return err
+	}
+
+	toc, err := json.Marshal(rendered.TOC)
+	if err != nil {
+		return err
+	}
+
+	review.ContentHTML = rendered.HTML
+	review.TOC = toc
+	review.ReadingTime = rendered.ReadingTime
+	return nil
+}
+
+// resolveContentImages 批量查询正文引用的文件，支持文件ID、file:文件ID 和文件URL三种写法
+func resolveContentImages(db *gorm.DB, dests []string) (map[string]*utils.MarkdownImage, error) {
+	images := make(map[string]*utils.MarkdownImage)
+	if len(dests) == 0 {
+		return images, nil
+	}
+
+	ids := make([]string, 0, len(dests))
+	for _, dest := range dests {
+		id := strings.TrimPrefix(dest, fileImagePrefix)
+		if _, err := uuid.Parse(id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	var files []model.File
+	query := db.Select("id", "url", "width", "height").Where("url IN ?", dests)
+	if len(ids) > 0 {
+		query = query.Or("id IN ?", ids)
+	}
+	if err := query.Find(&files).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range files {
+		image := &utils.MarkdownImage{
+			URL:    files[i].URL,
+			Width:  files[i].Width,
+			Height: files[i].Height,
+		}
+		images[files[i].URL] = image
+		images[files[i].ID] = image
+		images[fileImagePrefix+files[i].ID] = image
+	}
+	return images, nil
+}
+
+// renderBackfillBatchSize 每批补充渲染的测评数量
+const renderBackfillBatchSize = 100
+
+// StartRenderBackfill 在后台为渲染功能上线前保存的测评补充渲染结果，读取接口不再写入数据库
+func (s *ReviewService) StartRenderBackfill(ctx context.Context) {
+	go func() {
+		lastID := ""
+		for {
+			var reviews []model.Review
+			if err := s.db.WithContext(ctx).Unscoped().Select("id", "content").
+				Where("(content_html IS NULL OR content_html = '') AND content <> '' AND id > ?", lastID).
+				Order("id").Limit(renderBackfillBatchSize).Find(&reviews).Error; err != nil {
+				zap.L().Error("查询待渲染测评失败", zap.Error(err))
+				return
+			}
+			for i := range reviews {
+				s.backfillRenderedContent(ctx, &reviews[i])
+			}
+			if len(reviews) < renderBackfillBatchSize {
+				return
+			}
+			lastID = reviews[len(reviews)-1].ID
+		}
+	}()
+}
+
+// backfillRenderedContent 渲染并保存单篇测评，期间被编辑过的测评已有渲染结果，不会被覆盖
+func (s *ReviewService) backfillRenderedContent(ctx context.Context, review *model.Review) {
+	if err := renderReviewContent(s.db.WithContext(ctx), review); err != nil {
+		zap.L().Error("渲染测评正文失败", zap.String("reviewID", review.ID), zap.Error(err))
+		return
+	}
+	if err := s.db.WithContext(ctx).Unscoped().Model(&model.Review{}).
+		Where("id = ? AND (content_html IS NULL OR content_html = '')", review.ID).
+		UpdateColumns(map[string]interface{}{
+			"content_html": review.ContentHTML,
+			"toc":          review.TOC,
+			"reading_time": review.ReadingTime,
+		}).Error; err != nil {
+		zap.L().Error("保存测评渲染结果失败", zap.String("reviewID", review.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"beicun/back/model"
+	"beicun/back/utils"
+)
+
+// 相关测评数量
+const (
+	DefaultRelatedReviews = 6
+	MaxRelatedReviews     = 20
+)
+
+// ReviewRelation 相关测评与当前测评的关联方式
+type ReviewRelation string
+
+const (
+	ReviewRelationProduct     ReviewRelation = "product"     // 同一产品
+	ReviewRelationBrand       ReviewRelation = "brand"       // 同一品牌
+	ReviewRelationUtilityType ReviewRelation = "utilityType" // 同一器具类型
+)
+
+// RelatedReview 相关测评
+type RelatedReview struct {
+	*ReviewResponse
+	Relation ReviewRelation `json:"relation"`
+}
+
+// AuthorStats 作者统计
+type AuthorStats struct {
+	ReviewCount     int64   `json:"reviewCount"`               // 已发布测评数
+	ProductCount    int64   `json:"productCount"`              // 测评过的产品数
+	TotalViews      int64   `json:"totalViews"`                // 测评总浏览量
+	LastPublishedAt *string `json:"lastPublishedAt,omitempty"` // 最近发布时间
+}
+
+// AuthorProfile 作者公开信息
+type AuthorProfile struct {
+	UserBrief
+	Bio      *string      `json:"bio,omitempty"`      // 作者设为不公开时为空
+	JoinedAt *string      `json:"joinedAt,omitempty"` // 作者设为不公开时为空
+	Stats    *AuthorStats `json:"stats,omitempty"`    // 作者设为不公开时为空
+}
+
+// AuthorPage 作者主页
+type AuthorPage struct {
+	Author  *AuthorProfile `json:"author"`
+	Reviews utils.PageData `json:"reviews"`
+}
+
+// relatedReviewRow 相关测评查询结果
+type relatedReviewRow struct {
+	ID        string
+	Relevance int
+}
+
+// GetRelatedReviews 获取相关测评，依次为同产品、同品牌、同器具类型，同一层级内按时间衰减后的浏览量排序
+func (s *ReviewService) GetRelatedReviews(c *gin.Context, id string, limit int) ([]*RelatedReview, error) {
+	review := &model.Review{}
+	if err := s.db.Preload("Product").First(review, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, ErrInternal
+	}
+	// 与测评详情一致，未发布或被隐藏的测评不对外暴露
+	if review.Status != model.ReviewStatusPublished {
+		return nil, ErrReviewNotFound
+	}
+	if review.Product == nil {
+		return []*RelatedReview{}, nil
+	}
+	if limit <= 0 || limit > MaxRelatedReviews {
+		limit = DefaultRelatedReviews
+	}
+
+	var rows []relatedReviewRow
+	if err := s.db.Table("reviews").
+		Select(`reviews.id, CASE
+			WHEN reviews.product_id = ? THEN 3
+			WHEN products.brand_id = ? THEN 2
+			ELSE 1
+		END AS relevance`, review.ProductID, review.Product.BrandID).
+		Joins("JOIN products ON products.id = reviews.product_id AND products.deleted_at IS NULL").
+		Where("reviews.id != ? AND reviews.status = ? AND reviews.deleted_at IS NULL", review.ID, model.ReviewStatusPublished).
+		Where("reviews.product_id = ? OR products.brand_id = ? OR products.utility_type_id = ?",
+			review.ProductID, review.Product.BrandID, review.Product.UtilityTypeID).
+		Order("relevance DESC").
+		// 浏览量随发布时间衰减，新测评更容易排在前面
+		Order("(reviews.views + 1) / POWER(EXTRACT(EPOCH FROM NOW() - COALESCE(reviews.published_at, reviews.created_at)) / 3600 + 2, 1.5) DESC").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, ErrInternal
+	}
+	if len(rows) == 0 {
+		return []*RelatedReview{}, nil
+	}
+
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	var reviews []*model.Review
+	if err := s.db.Preload("Product").Preload("Variant").Preload("Author").Preload("Scores.Dimension").
+		Where("id IN ?", ids).
+		Find(&reviews).Error; err != nil {
+		return nil, ErrInternal
+	}
+	byID := make(map[string]*model.Review, len(reviews))
+	for _, r := range reviews {
+		byID[r.ID] = r
+	}
+
+	related := make([]*RelatedReview, 0, len(rows))
+	for _, row := range rows {
+		r, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		response, err := s.getReviewResponse(r)
+		if err != nil {
+			return nil, ErrInternal
+		}
+		related = append(related, &RelatedReview{
+			ReviewResponse: response,
+			Relation:       relevanceRelation(row.Relevance),
+		})
+	}
+	return related, nil
+}
+
+func relevanceRelation(relevance int) ReviewRelation {
+	switch relevance {
+	case 3:
+		return ReviewRelationProduct
+	case 2:
+		return ReviewRelationBrand
+	default:
+		return ReviewRelationUtilityType
+	}
+}
+
+// GetAuthorPage 获取作者主页，包括作者信息、统计和已发布的测评，简介、注册时间和统计遵循作者的主页可见性设置
+func (s *ReviewService) GetAuthorPage(c *gin.Context, userID, viewerID string, viewerRole model.UserRole, page, pageSize int) (*AuthorPage, error) {
+	var user model.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrInternal
+	}
+	if user.Status.IsHidden() {
+		return nil, ErrUserNotFound
+	}
+
+	reviews, total, err := s.listPublishedReviews(
+		s.db.Model(&model.Review{}).Where("user_id = ?", userID),
+		"published_at DESC, created_at DESC", page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	author := &AuthorProfile{
+		UserBrief: UserBrief{
+			ID:     user.ID,
+			Name:   user.Name,
+			Avatar: user.Avatar,
+		},
+	}
+	visible := profileVisibility(&user, viewerID, viewerRole)
+	if visible(model.ProfileFieldBio) {
+		author.Bio = &user.Bio
+	}
+	if visible(model.ProfileFieldJoinDate) {
+		joinedAt := user.CreatedAt.Format("2006-01-02 15:04:05")
+		author.JoinedAt = &joinedAt
+	}
+	if visible(model.ProfileFieldStats) {
+		var stats struct {
+			ReviewCount     int64
+			ProductCount    int64
+			TotalViews      int64
+			LastPublishedAt *string
+		}
+		if err := s.db.Model(&model.Review{}).
+			Select(`COUNT(*) AS review_count,
+				COUNT(DISTINCT product_id) AS product_count,
+				COALESCE(SUM(views), 0) AS total_views,
+				TO_CHAR(MAX(published_at), 'YYYY-MM-DD HH24:MI:SS') AS last_published_at`).
+			Where("user_id = ? AND status = ?", userID, model.ReviewStatusPublished).
+			Scan(&stats).Error; err != nil {
+			return nil, ErrInternal
+		}
+		author.Stats = &AuthorStats{
+			ReviewCount:     stats.ReviewCount,
+			ProductCount:    stats.ProductCount,
+			TotalViews:      stats.TotalViews,
+			LastPublishedAt: stats.LastPublishedAt,
+		}
+	}
+
+	return &AuthorPage{
+		Author: author,
+		Reviews: utils.PageData{
+			List:     reviews,
+			Total:    total,
+			Page:     page,
+			PageSize: pageSize,
+		},
+	}, nil
+}
+
+// listPublishedReviews 分页查询已发布的测评
+func (s *ReviewService) listPublishedReviews(query *gorm.DB, order string, page, pageSize int) ([]*ReviewResponse, int64, error) {
+	query = query.Where("status = ?", model.ReviewStatusPublished)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	var reviews []*model.Review
+	if err := query.Preload("Product").Preload("Variant").Preload("Author").Preload("Scores.Dimension").
+		Order(order).
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&reviews).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	responses := make([]*ReviewResponse, len(reviews))
+	for i, review := range reviews {
+		response, err := s.getReviewResponse(review)
+		if err != nil {
+			return nil, 0, ErrInternal
+		}
+		responses[i] = response
+	}
+
+	return responses, total, nil
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"beicun/back/model"
+	"beicun/back/utils"
+)
+
+var (
+	ErrRevisionNotFound = errors.New("修订版本不存在")
+)
+
+// ReviewRevisionSummary 修订版本摘要
+type ReviewRevisionSummary struct {
+	ID        string     `json:"id"`
+	Version   int        `json:"version"`
+	Title     string     `json:"title"`
+	Note      string     `json:"note"`
+	Editor    *UserBrief `json:"editor,omitempty"`
+	CreatedAt string     `json:"createdAt"`
+}
+
+// ReviewRevisionResponse 修订版本详情
+type ReviewRevisionResponse struct {
+	ReviewRevisionSummary
+	ReviewID   string   `json:"reviewId"`
+	Content    string   `json:"content"`
+	Pros       []string `json:"pros"`
+	Cons       []string `json:"cons"`
+	Conclusion string   `json:"conclusion"`
+}
+
+// ReviewRevisionDiff 两个修订版本之间的差异
+type ReviewRevisionDiff struct {
+	ReviewID   string           `json:"reviewId"`
+	From       int              `json:"from"`
+	To         int              `json:"to"`
+	Title      []utils.DiffLine `json:"title"`
+	Content    []utils.DiffLine `json:"content"`
+	Pros       []utils.DiffLine `json:"pros"`
+	Cons       []utils.DiffLine `json:"cons"`
+	Conclusion []utils.DiffLine `json:"conclusion"`
+}
+
+// ListRevisions 获取测评的修订历史，按版本号倒序
+func (s *ReviewService) ListRevisions(c *gin.Context, reviewID string) ([]*ReviewRevisionSummary, error) {
+	if err := s.db.First(&model.Review{}, "id = ?", reviewID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, ErrInternal
+	}
+
+	var revisions []*model.ReviewRevision
+	if err := s.db.Preload("Editor").
+		Where("review_id = ?", reviewID).
+		Order("version DESC").
+		Find(&revisions).Error; err != nil {
+		return nil, ErrInternal
+	}
+
+	summaries := make([]*ReviewRevisionSummary, len(revisions))
+	for i, revision := range revisions {
+		summaries[i] = toRevisionSummary(revision)
+	}
+	return summaries, nil
+}
+
+// GetRevision 获取指定修订版本
+func (s *ReviewService) GetRevision(c *gin.Context, reviewID string, version int) (*ReviewRevisionResponse, error) {
+	revision, err := s.findRevision(reviewID, version)
+	if err != nil {
+		return nil, err
+	}
+	return toRevisionResponse(revision), nil
+}
+
+// DiffRevisions 比较两个修订版本
+func (s *ReviewService) DiffRevisions(c *gin.Context, reviewID string, from, to int) (*ReviewRevisionDiff, error) {
+	fromRevision, err := s.findRevision(reviewID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.findRevision(reviewID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReviewRevisionDiff{
+		ReviewID:   reviewID,
+		From:       from,
+		To:         to,
+		Title:      utils.DiffLines(fromRevision.Title, toRevision.Title),
+		Content:    utils.DiffLines(fromRevision.Content, toRevision.Content),
+		Pros:       utils.DiffLines(strings.Join(fromRevision.Pros, "\n"), strings.Join(toRevision.Pros, "\n")),
+		Cons:       utils.DiffLines(strings.Join(fromRevision.Cons, "\n"), strings.Join(toRevision.Cons, "\n")),
+		Conclusion: utils.DiffLines(fromRevision.Conclusion, toRevision.Conclusion),
+	}, nil
+}
+
+// RollbackReview 将测评回滚到指定版本，回滚本身会生成一个新的修订版本
+func (s *ReviewService) RollbackReview(c *gin.Context, reviewID string, version int, userID string) (*ReviewResponse, error) {
+	revision, err := s.findRevision(reviewID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &model.Review{}
+	if err := s.db.First(review, "id = ?", reviewID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, ErrInternal
+	}
+	if err := checkReviewAccess(c, review, userID); err != nil {
+		return nil, err
+	}
+
+	oldSlug := review.Slug
+	if revision.Title != review.Title {
+		reviewSlug, err := s.generateReviewSlug(revision.Title, review.ID)
+		if err != nil {
+			return nil, ErrInternal
+		}
+		review.Title = revision.Title
+		review.Slug = reviewSlug
+	}
+	review.Content = revision.Content
+	review.Pros = revision.Pros
+	review.Cons = revision.Cons
+	review.Conclusion = revision.Conclusion
+	if err := renderReviewContent(s.db, review); err != nil {
+		return nil, ErrInternal
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(review).Error; err != nil {
+			return err
+		}
+		if err := recordSlugChange(tx, model.SlugEntityReview, review.ID, oldSlug, review.Slug); err != nil {
+			return err
+		}
+		return s.createRevision(tx, review, userID, fmt.Sprintf("回滚至版本 %d", version))
+	})
+	if err != nil {
+		return nil, ErrInternal
+	}
+
+	return s.getReviewResponse(review)
+}
+
+// findRevision 查询指定版本
+func (s *ReviewService) findRevision(reviewID string, version int) (*model.ReviewRevision, error) {
+	revision := &model.ReviewRevision{}
+	if err := s.db.Preload("Editor").
+		First(revision, "review_id = ? AND version = ?", reviewID, version).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, ErrInternal
+	}
+	return revision, nil
+}
+
+// ensureInitialRevision 为尚无修订记录的测评保存当前内容作为初始版本
+func (s *ReviewService) ensureInitialRevision(tx *gorm.DB, review *model.Review) error {
+	var count int64
+	if err := tx.Model(&model.ReviewRevision{}).Where("review_id = ?", review.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return s.createRevision(tx, review, review.UserID, "初始版本")
+}
+
+// createRevision 以测评当前内容生成新的修订版本
+func (s *ReviewService) createRevision(tx *gorm.DB, review *model.Review, editorID, note string) error {
+	var latest int
+	if err := tx.Model(&model.ReviewRevision{}).
+		Where("review_id = ?", review.ID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	revision := &model.ReviewRevision{
+		ReviewID:   review.ID,
+		Version:    latest + 1,
+		Title:      review.Title,
+		Content:    review.Content,
+		Pros:       review.Pros,
+		Cons:       review.Cons,
+		Conclusion: review.Conclusion,
+		EditorID:   editorID,
+		Note:       note,
+	}
+	return tx.Create(revision).Error
+}
+
+// revisionContentChanged 判断测评正文相关字段是否发生变化
+func revisionContentChanged(before, after *model.Review) bool {
+	return before.Title != after.Title ||
+		before.Content != after.Content ||
+		before.Conclusion != after.Conclusion ||
+		strings.Join(before.Pros, "\n") != strings.Join(after.Pros, "\n") ||
+		strings.Join(before.Cons, "\n") != strings.Join(after.Cons, "\n")
+}
+
+func toRevisionSummary(revision *model.ReviewRevision) *ReviewRevisionSummary {
+	summary := &ReviewRevisionSummary{
+		ID:        revision.ID,
+		Version:   revision.Version,
+		Title:     revision.Title,
+		Note:      revision.Note,
+		CreatedAt: revision.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if revision.Editor.ID != "" {
+		summary.Editor = &UserBrief{
+			ID:     revision.Editor.ID,
+			Name:   revision.Editor.Name,
+			Avatar: revision.Editor.Avatar,
+		}
+	}
+	return summary
+}
+
+func toRevisionResponse(revision *model.ReviewRevision) *ReviewRevisionResponse {
+	return &ReviewRevisionResponse{
+		ReviewRevisionSummary: *toRevisionSummary(revision),
+		ReviewID:              revision.ReviewID,
+		Content:               revision.Content,
+		Pros:                  revision.Pros,
+		Cons:                  revision.Cons,
+		Conclusion:            revision.Conclusion,
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"beicun/back/model"
+)
+
+var (
+	ErrEmailNotVerified          = errors.New("邮箱未验证，无法投稿")
+	ErrUserBlocked               = errors.New("账号已被封禁")
+	ErrSubmissionQuotaExceeded   = errors.New("今日投稿数量已达上限")
+	ErrTooManyPendingSubmissions = errors.New("待审核的投稿过多，请等待审核后再投稿")
+	ErrReviewNotEditable         = errors.New("当前状态下无法编辑测评")
+)
+
+// SubmitReview 普通用户投稿测评，投稿直接进入审核队列
+func (s *ReviewService) SubmitReview(c *gin.Context, userID string, req *CreateReviewRequest) (*ReviewResponse, error) {
+	var user model.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrInternal
+	}
+	if user.Status == model.UserStatusBlocked {
+		return nil, ErrUserBlocked
+	}
+	if !user.IsEmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
+	if err := s.checkSubmissionQuota(userID, ""); err != nil {
+		return nil, err
+	}
+
+	review, err := s.newReview(c, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	review.Status = model.ReviewStatusInReview
+	review.IsCommunity = true
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+		if err := s.saveReviewScores(tx, review, req.Scores); err != nil {
+			return err
+		}
+		if err := s.createRevision(tx, review, userID, "用户投稿"); err != nil {
+			return err
+		}
+		return tx.Create(&model.ReviewStatusLog{
+			ReviewID:   review.ID,
+			FromStatus: model.ReviewStatusDraft,
+			ToStatus:   model.ReviewStatusInReview,
+			ActorID:    &userID,
+			Note:       "用户投稿",
+		}).Error
+	})
+	if err != nil {
+		return nil, ErrInternal
+	}
+
+	return s.getReviewResponse(review)
+}
+
+// checkSubmissionQuota 检查用户投稿配额，excludeID 为重新提交的测评ID，不计入待审核数量。
+// 每日配额按当天（服务器本地时间零点起）进入审核中状态的次数统计，退回后重新提交同样占用配额
+func (s *ReviewService) checkSubmissionQuota(userID, excludeID string) error {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var today int64
+	if err := s.db.Model(&model.ReviewStatusLog{}).
+		Joins("JOIN reviews ON reviews.id = review_status_logs.review_id").
+		Where("reviews.user_id = ? AND reviews.is_community = ?", userID, true).
+		Where("review_status_logs.to_status = ? AND review_status_logs.created_at >= ?",
+			model.ReviewStatusInReview, startOfDay).
+		Count(&today).Error; err != nil {
+		return ErrInternal
+	}
+	if today >= int64(s.cfg.Review.SubmissionDailyQuota) {
+		return ErrSubmissionQuotaExceeded
+	}
+
+	var pending int64
+	query := s.db.Model(&model.Review{}).
+		Where("user_id = ? AND is_community = ? AND status IN ?", userID, true,
+			[]model.ReviewStatus{model.ReviewStatusInReview, model.ReviewStatusChangesRequested})
+	if excludeID != "" {
+		query = query.Where("id != ?", excludeID)
+	}
+	if err := query.Count(&pending).Error; err != nil {
+		return ErrInternal
+	}
+	if pending >= int64(s.cfg.Review.MaxPendingSubmissions) {
+		return ErrTooManyPendingSubmissions
+	}
+
+	return nil
+}
+
+// ListModerationQueue 获取待审核的用户投稿，按提交时间先后排列
+func (s *ReviewService) ListModerationQueue(c *gin.Context, page, pageSize int) ([]*ReviewResponse, int64, error) {
+	var total int64
+	query := s.db.Model(&model.Review{}).
+		Where("is_community = ? AND status = ?", true, model.ReviewStatusInReview)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	var reviews []*model.Review
+	if err := query.Preload("Product").Preload("Variant").Preload("Author").Preload("Scores.Dimension").Preload("Reviewer").
+		Order("updated_at ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&reviews).Error; err != nil {
+		return nil, 0, ErrInternal
+	}
+
+	responses := make([]*ReviewResponse, len(reviews))
+	for i, review := range reviews {
+		response, err := s.getReviewResponse(review)
+		if err != nil {
+			return nil, 0, ErrInternal
+		}
+		responses[i] = response
+	}
+
+	return responses, total, nil
+}
+
+// notifyModerationResult 通知投稿作者审核结果
+func (s *ReviewService) notifyModerationResult(review *model.Review, note string) {
+	if !review.IsCommunity || s.notificationService == nil {
+		return
+	}
+	s.notificationService.NotifyReviewModeration(context.Background(), review, note)
+}
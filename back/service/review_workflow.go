@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"beicun/back/model"
+	"beicun/back/utils"
+)
+
+var (
+	ErrInvalidTransition = errors.New("不允许的状态变更")
+	ErrReviewForbidden   = errors.New("无权操作该测评")
+	ErrInvalidSchedule   = errors.New("定时发布时间必须晚于当前时间")
+	ErrInvalidReviewer   = errors.New("审核人必须是编辑或管理员")
+)
+
+// TransitionReviewRequest 测评状态变更请求
+type TransitionReviewRequest struct {
+	Status      model.ReviewStatus `json:"status" binding:"required"`
+	Note        string             `json:"note"`                  // 编辑备注
+	ScheduledAt *time.Time         `json:"scheduledAt,omitempty"` // 定时发布时间，状态为 SCHEDULED 时必填
+}
+
+// AssignReviewerRequest 指派审核人请求
+type AssignReviewerRequest struct {
+	ReviewerID *string `json:"reviewerId" binding:"omitempty,uuid"` // 为空表示取消指派
+}
+
+// ReviewStatusLogResponse 状态变更记录
+type ReviewStatusLogResponse struct {
+	ID         string             `json:"id"`
+	FromStatus model.ReviewStatus `json:"fromStatus"`
+	ToStatus   model.ReviewStatus `json:"toStatus"`
+	Note       string             `json:"note"`
+	Actor      *UserBrief         `json:"actor,omitempty"`
+	CreatedAt  string             `json:"createdAt"`
+}
+
+// canActAs 判断当前用户在该测评流程中是否可以作为指定参与方操作
+// 管理员可执行所有操作；作者处理自己的测评；编辑可审核被指派的测评，以及未指派审核人的用户投稿
+func canActAs(c *gin.Context, review *model.Review, userID string, actor model.ReviewActor) bool {
+	role := utils.GetUserRoleFromContext(c)
+	if role == model.UserRoleAdmin {
+		return true
+	}
+	switch actor {
+	case model.ReviewActorAuthor:
+		return review.UserID == userID
+	case model.ReviewActorReviewer:
+		if role != model.UserRoleEditor {
+			return false
+		}
+		if review.ReviewerID != nil {
+			return *review.ReviewerID == userID
+		}
+		return review.IsCommunity
+	}
+	return false
+}
+
+// checkReviewAccess 检查用户是否可以编辑该测评
+func checkReviewAccess(c *gin.Context, review *model.Review, userID string) error {
+	if !canActAs(c, review, userID, model.ReviewActorAuthor) && !canActAs(c, review, userID, model.ReviewActorReviewer) {
+		return ErrReviewForbidden
+	}
+	// 普通用户只能在审核完成前编辑自己的投稿
+	if utils.GetUserRoleFromContext(c) == model.UserRoleUser && !review.Status.EditableByCommunityAuthor() {
+		return ErrReviewNotEditable
+	}
+	return nil
+}
+
+// TransitionReview 变更测评状态
+func (s *ReviewService) TransitionReview(c *gin.Context, id string, userID string, req *TransitionReviewRequest) (*ReviewResponse, error) {
+	review := &model.Review{}
+	if err := s.db.First(review, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, ErrInternal
+	}
+
+	actor, ok := review.Status.TransitionActor(req.Status)
+	if !ok {
+		return nil, ErrInvalidTransition
+	}
+	if !canActAs(c, review, userID, actor) {
+		return nil, ErrReviewForbidden
+	}
+	// 作者重新提交投稿同样受投稿配额限制
+	if review.IsCommunity && req.Status == model.ReviewStatusInReview && review.UserID == userID {
+		if err := s.checkSubmissionQuota(userID, review.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	from := review.Status
+	review.Status = req.Status
+	switch req.Status {
+	case model.ReviewStatusScheduled:
+		if req.ScheduledAt == nil || !req.ScheduledAt.After(time.Now()) {
+			return nil, ErrInvalidSchedule
+		}
+		review.ScheduledAt = req.ScheduledAt
+	case model.ReviewStatusPublished:
+		stampPublishedAt(review)
+	default:
+		review.ScheduledAt = nil
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(review).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.ReviewStatusLog{
+			ReviewID:   review.ID,
+			FromStatus: from,
+			ToStatus:   review.Status,
+			ActorID:    &userID,
+			Note:       req.Note,
+		}).Error
+	})
+	if err != nil {
+		return nil, ErrInternal
+	}
+	if from == model.ReviewStatusPublished || review.Status == model.ReviewStatusPublished {
+		s.invalidateRatingStats(c, review.ProductID)
+	}
+
+	s.notifyModerationResult(review, req.Note)
+
+	return s.getReviewResponse(review)
+}
+
+// AssignReviewer 指派测评审核人
+func (s *ReviewService) AssignReviewer(c *gin.Context, id string, req *AssignReviewerRequest) (*ReviewResponse, error) {
+	review := &model.Review{}
+	if err := s.db.First(review, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, ErrInternal
+	}
+
+	if req.ReviewerID != nil && *req.ReviewerID != "" {
+		var reviewer model.User
+		if err := s.db.First(&reviewer, "id = ?", *req.ReviewerID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrUserNotFound
+			}
+			return nil, ErrInternal
+		}
+		if reviewer.Role != model.UserRoleEditor && reviewer.Role != model.UserRoleAdmin {
+			return nil, ErrInvalidReviewer
+		}
+		review.ReviewerID = req.ReviewerID
+	} else {
+		review.ReviewerID = nil
+	}
+
+	if err := s.db.Model(review).Update("reviewer_id", review.ReviewerID).Error; err != nil {
+		return nil, ErrInternal
+	}
+
+	if err := s.db.Preload("Reviewer").First(review, "id = ?", id).Error; err != nil {
+		return nil, ErrInternal
+	}
+	return s.getReviewResponse(review)
+}
+
+// ListStatusLogs 获取测评状态变更记录
+func (s *ReviewService) ListStatusLogs(c *gin.Context, id string) ([]*ReviewStatusLogResponse, error) {
+	if err := s.db.First(&model.Review{}, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, ErrInternal
+	}
+
+	var logs []*model.ReviewStatusLog
+	if err := s.db.Preload("Actor").
+		Where("review_id = ?", id).
+		Order("created_at DESC").
+		Find(&logs).Error; err != nil {
+		return nil, ErrInternal
+	}
+
+	responses := make([]*ReviewStatusLogResponse, len(logs))
+	for i, log := range logs {
+		responses[i] = &ReviewStatusLogResponse{
+			ID:         log.ID,
+			FromStatus: log.FromStatus,
+			ToStatus:   log.ToStatus,
+			Note:       log.Note,
+			CreatedAt:  log.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+		if log.Actor != nil {
+			responses[i].Actor = &UserBrief{
+				ID:     log.Actor.ID,
+				Name:   log.Actor.Name,
+				Avatar: log.Actor.Avatar,
+			}
+		}
+	}
+	return responses, nil
+}
+
+// PublishScheduled 发布已到定时发布时间的测评
+func (s *ReviewService) PublishScheduled(ctx context.Context) {
+	var reviews []*model.Review
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND scheduled_at <= ?", model.ReviewStatusScheduled, time.Now()).
+		Find(&reviews).Error; err != nil {
+		zap.L().Error("查询定时发布测评失败", zap.Error(err))
+		return
+	}
+
+	for _, review := range reviews {
+		review.Status = model.ReviewStatusPublished
+		stampPublishedAt(review)
+
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// 以状态为条件更新，避免与人工操作冲突
+			result := tx.Model(&model.Review{}).
+				Where("id = ? AND status = ?", review.ID, model.ReviewStatusScheduled).
+				Updates(map[string]interface{}{
+					"status":       review.Status,
+					"published_at": review.PublishedAt,
+					"scheduled_at": nil,
+				})
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			return tx.Create(&model.ReviewStatusLog{
+				ReviewID:   review.ID,
+				FromStatus: model.ReviewStatusScheduled,
+				ToStatus:   model.ReviewStatusPublished,
+				Note:       "定时发布",
+			}).Error
+		})
+		if err != nil {
+			zap.L().Error("定时发布测评失败",
+				zap.String("reviewID", review.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		s.invalidateRatingStats(ctx, review.ProductID)
+		s.notifyModerationResult(review, "")
+	}
+}
+
+// StartPublishScheduler 定期检查并发布定时测评
+func (s *ReviewService) StartPublishScheduler(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.PublishScheduled(ctx)
+			}
+		}
+	}()
+}
+
+// stampPublishedAt 首次发布时记录发布时间
+func stampPublishedAt(review *model.Review) {
+	if review.PublishedAt == nil {
+		now := time.Now()
+		review.PublishedAt = &now
+	}
+	review.ScheduledAt = nil
+}
@@ -29,8 +29,9 @@ func (s *SearchService) SearchProducts(query string) ([]model.Product, error) {
 // SearchReviews 搜索测评
 func (s *SearchService) SearchReviews(query string) ([]model.Review, error) {
 	var reviews []model.Review
-	err := s.db.Where("title ILIKE ? OR unboxing ILIKE ? OR experience ILIKE ? OR maintenance ILIKE ? OR conclusion ILIKE ?",
-		"%"+query+"%", "%"+query+"%", "%"+query+"%", "%"+query+"%", "%"+query+"%").
+	err := s.db.Where("status = ?", model.ReviewStatusPublished).
+		Where("title ILIKE ? OR unboxing ILIKE ? OR experience ILIKE ? OR maintenance ILIKE ? OR conclusion ILIKE ?",
+			"%"+query+"%", "%"+query+"%", "%"+query+"%", "%"+query+"%", "%"+query+"%").
 		Preload("Product").
 		Preload("User").
 		Find(&reviews).Error
@@ -45,11 +46,23 @@ func (s *SearchService) SearchBrands(query string) ([]model.Brand, error) {
 	return brands, err
 }
 
-// SearchUsers 搜索用户
+// SearchUsers 搜索用户（管理员），按名称或邮箱匹配
 func (s *SearchService) SearchUsers(query string) ([]model.User, error) {
 	var users []model.User
-	err := s.db.Where("name ILIKE ? OR email ILIKE ? OR phone ILIKE ?",
-		"%"+query+"%", "%"+query+"%", "%"+query+"%").
+	err := s.db.Where("name ILIKE ? OR email ILIKE ?",
+		"%"+query+"%", "%"+query+"%").
+		Find(&users).Error
+	return users, err
+}
+
+// SearchPublicUsers 按名称搜索用户，只返回公开信息，不包含邮箱等联系方式
+func (s *SearchService) SearchPublicUsers(query string) ([]*UserBrief, error) {
+	users := []*UserBrief{}
+	err := s.db.Model(&model.User{}).
+		Select("id", "name", "avatar").
+		Where("name ILIKE ? AND status NOT IN ?", "%"+query+"%", model.HiddenUserStatuses).
+		Order("name").
+		Limit(20).
 		Find(&users).Error
 	return users, err
 }
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"beicun/back/model"
+	"beicun/back/utils"
+)
+
+// SlugMovedError slug 已变更，调用方应重定向到当前 slug
+type SlugMovedError struct {
+	EntityType model.SlugEntityType
+	Slug       string
+}
+
+func (e *SlugMovedError) Error() string {
+	return "链接已变更"
+}
+
+// SlugRedirect 旧 slug 的重定向信息
+type SlugRedirect struct {
+	Redirect   bool                 `json:"redirect"`
+	EntityType model.SlugEntityType `json:"entityType"`
+	Slug       string               `json:"slug"`
+}
+
+// generateEntitySlug 为实体生成唯一 slug，当前 slug 和其他实体的历史 slug 均视为已占用
+// excludeID 为空表示新建实体
+func generateEntitySlug(db *gorm.DB, entity interface{}, entityType model.SlugEntityType, name, excludeID string) (string, error) {
+	return utils.UniqueSlug(name, string(entityType), func(candidate string) (bool, error) {
+		var count int64
+		// 回收站中的记录仍占用唯一索引，需一并检查
+		query := db.Unscoped().Model(entity).Where("slug = ?", candidate)
+		if excludeID != "" {
+			query = query.Where("id != ?", excludeID)
+		}
+		if err := query.Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+
+		// 实体可以改回自己用过的 slug
+		query = db.Model(&model.SlugHistory{}).Where("entity_type = ? AND slug = ?", entityType, candidate)
+		if excludeID != "" {
+			query = query.Where("entity_id != ?", excludeID)
+		}
+		if err := query.Count(&count).Error; err != nil {
+			return false, err
+		}
+		return count > 0, nil
+	})
+}
+
+// recordSlugChange 记录实体的旧 slug
+func recordSlugChange(tx *gorm.DB, entityType model.SlugEntityType, entityID interface{}, oldSlug, newSlug string) error {
+	if oldSlug == "" || oldSlug == newSlug {
+		return nil
+	}
+	id := fmt.Sprint(entityID)
+
+	// 改回曾经使用的 slug 时，该 slug 不再是历史记录
+	if err := tx.Where("entity_type = ? AND entity_id = ? AND slug = ?", entityType, id, newSlug).
+		Delete(&model.SlugHistory{}).Error; err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Model(&model.SlugHistory{}).
+		Where("entity_type = ? AND slug = ?", entityType, oldSlug).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return tx.Create(&model.SlugHistory{
+		EntityType: entityType,
+		Slug:       oldSlug,
+		EntityID:   id,
+	}).Error
+}
+
+// resolveMovedSlug 在 slug 历史中查找实体，找到时返回 SlugMovedError，否则返回 notFound
+func resolveMovedSlug(db *gorm.DB, entity interface{}, entityType model.SlugEntityType, slug string, notFound error) error {
+	var history model.SlugHistory
+	if err := db.Where("entity_type = ? AND slug = ?", entityType, slug).First(&history).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return notFound
+		}
+		return err
+	}
+
+	var current struct {
+		Slug string
+	}
+	if err := db.Model(entity).Select("slug").Where("id = ?", history.EntityID).Take(&current).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return notFound
+		}
+		return err
+	}
+	return &SlugMovedError{EntityType: entityType, Slug: current.Slug}
+}
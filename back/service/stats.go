@@ -91,7 +91,7 @@ func (s *StatsService) GetDashboardStats(c *gin.Context) (*DashboardStats, error
 	s.db.Model(&model.User{}).Count(&stats.TotalUsers)
 	s.db.Model(&model.Review{}).Count(&stats.TotalReviews)
 	s.db.Model(&model.Comment{}).Count(&stats.TotalComments)
-	s.db.Model(&model.Review{}).Where("status = ?", model.ReviewStatusPending).Count(&stats.PendingReviews)
+	s.db.Model(&model.Review{}).Where("status = ?", model.ReviewStatusInReview).Count(&stats.PendingReviews)
 	s.db.Model(&model.Comment{}).Where("status = ?", model.CommentStatusPending).Count(&stats.PendingComments)
 
 	// 获取热门产品
@@ -499,15 +499,18 @@ func (s *StatsService) GetProductStats(c *gin.Context) (*cache.ProductStats, err
 	return stats, nil
 }
 
-// GetRatingStats 获取产品评分统计数据
-func (s *StatsService) GetRatingStats(c *gin.Context, productID uint) (*cache.RatingStats, error) {
+// GetRatingStats 获取产品评分统计数据，variantID 非空时只统计针对该版本的评分，版本统计不缓存
+func (s *StatsService) GetRatingStats(c *gin.Context, productID uint, variantID string) (*cache.RatingStats, error) {
 	// 尝试从缓存获取
-	if stats, err := s.cache.GetRatingStats(c, productID); err == nil && stats != nil {
-		return stats, nil
+	if variantID == "" {
+		if stats, err := s.cache.GetRatingStats(c, productID); err == nil && stats != nil {
+			return stats, nil
+		}
 	}
 
 	var stats cache.RatingStats
 	stats.ProductID = productID
+	stats.VariantID = variantID
 
 	// 获取产品名称
 	var product model.Product
@@ -516,24 +519,38 @@ func (s *StatsService) GetRatingStats(c *gin.Context, productID uint) (*cache.Ra
 	}
 	stats.ProductName = product.Name
 
+	filter, args := "r.product_id = ?", []interface{}{productID}
+	if variantID != "" {
+		var count int64
+		if err := s.db.Model(&model.ProductVariant{}).
+			Where("id = ? AND product_id = ?", variantID, productID).
+			Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, ErrVariantNotFound
+		}
+		filter += " AND r.variant_id = ?"
+		args = append(args, variantID)
+	}
+
 	// 获取评分统计
 	row := s.db.Raw(`
 		SELECT 
-			AVG(CAST(rating AS FLOAT)) as average_rating,
+			COALESCE(AVG(CAST(rating AS FLOAT)), 0) as average_rating,
 			COUNT(*) as total_ratings
-		FROM reviews
-		WHERE product_id = ?
-	`, productID).Row()
+		FROM ratings r
+		WHERE `+filter, args...).Row()
 	row.Scan(&stats.AverageRating, &stats.TotalRatings)
 
 	// 获取各评分数量
 	stats.RatingCounts = make(map[int]int64)
 	rows, err := s.db.Raw(`
-		SELECT rating, COUNT(*) as count
-		FROM reviews
-		WHERE product_id = ?
-		GROUP BY rating
-	`, productID).Rows()
+		SELECT CAST(ROUND(rating) AS INT) as rating, COUNT(*) as count
+		FROM ratings r
+		WHERE `+filter+`
+		GROUP BY 1
+	`, args...).Rows()
 	if err == nil {
 		defer rows.Close()
 		for rows.Next() {
@@ -550,16 +567,16 @@ func (s *StatsService) GetRatingStats(c *gin.Context, productID uint) (*cache.Ra
 		SELECT 
 			r.id,
 			r.user_id,
-			u.username,
-			r.rating,
-			r.comment,
+			u.name,
+			CAST(ROUND(r.rating) AS INT),
+			COALESCE(r.reason, ''),
 			r.created_at
-		FROM reviews r
+		FROM ratings r
 		JOIN users u ON r.user_id = u.id
-		WHERE r.product_id = ?
+		WHERE `+filter+`
 		ORDER BY r.created_at DESC
 		LIMIT 10
-	`, productID).Rows()
+	`, args...).Rows()
 	if err == nil {
 		defer rows.Close()
 		for rows.Next() {
@@ -570,8 +587,17 @@ func (s *StatsService) GetRatingStats(c *gin.Context, productID uint) (*cache.Ra
 		}
 	}
 
+	// 获取维度评分雷达图数据
+	dimensions, err := queryDimensionStats(s.db, productID, product.UtilityTypeID)
+	if err != nil {
+		return nil, err
+	}
+	stats.Dimensions = dimensions
+
 	// 设置缓存
-	s.cache.SetRatingStats(c, productID, &stats)
+	if variantID == "" {
+		s.cache.SetRatingStats(c, productID, &stats)
+	}
 
 	return &stats, nil
 }
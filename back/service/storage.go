@@ -12,7 +12,6 @@ import (
 
 	"beicun/back/config"
 	"beicun/back/model"
-	"beicun/back/utils"
 )
 
 // 文件相关错误
@@ -229,12 +228,16 @@ func (s *StorageService) MoveFile(c *gin.Context, id string, targetFolderID stri
 // DeleteFolder 删除文件夹
 func (s *StorageService) DeleteFolder(c *gin.Context, id string) error {
 	return s.db.Transaction(func(tx *gorm.DB) error {
-		// 删除文件夹
-		if err := tx.Delete(&model.Folder{}, "id = ?", id).Error; err != nil {
+		// 删除文件夹下的所有文件（移入回收站）
+		if err := tx.Delete(&model.File{}, "folder_id = ?", id).Error; err != nil {
 			return err
 		}
-		// 删除文件夹下的所有文件
-		if err := tx.Delete(&model.File{}, "folder_id = ?", id).Error; err != nil {
+		// 回收站中的文件脱离原文件夹，恢复后位于根目录
+		if err := tx.Unscoped().Model(&model.File{}).Where("folder_id = ?", id).Update("folder_id", nil).Error; err != nil {
+			return err
+		}
+		// 删除文件夹
+		if err := tx.Delete(&model.Folder{}, "id = ?", id).Error; err != nil {
 			return err
 		}
 		return nil
@@ -289,12 +292,7 @@ func (s *StorageService) DeleteFile(c *gin.Context, id string) error {
 		return err
 	}
 
-	// 删除物理文件
-	if err := utils.DeleteFile(file.Path); err != nil {
-		return err
-	}
-
-	// 删除数据库记录
+	// 软删除数据库记录，物理文件在回收站清理时删除
 	return s.db.Delete(&file).Error
 }
 
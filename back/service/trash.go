@@ -0,0 +1,415 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"beicun/back/config"
+	"beicun/back/model"
+)
+
+// 回收站相关错误
+var (
+	ErrInvalidTrashType   = errors.New("不支持的回收站类型")
+	ErrTrashItemNotFound  = errors.New("回收站中不存在该记录")
+	ErrTrashFileConflict  = errors.New("已存在内容相同的文件，无法恢复")
+	ErrTrashParentInTrash = errors.New("所属的上级记录仍在回收站中，请先恢复上级记录")
+	ErrTrashBrandInUse    = errors.New("品牌下仍有产品或子品牌，无法彻底删除")
+)
+
+// TrashType 回收站记录类型
+type TrashType string
+
+const (
+	TrashTypeProduct TrashType = "product" // 产品
+	TrashTypeUser    TrashType = "user"    // 用户
+	TrashTypeReview  TrashType = "review"  // 测评
+	TrashTypeComment TrashType = "comment" // 评论
+	TrashTypeBrand   TrashType = "brand"   // 品牌
+	TrashTypeFile    TrashType = "file"    // 文件
+)
+
+// trashEntity 支持软删除的实体
+type trashEntity struct {
+	model      interface{}
+	table      string
+	nameColumn string
+}
+
+// trashEntities 可进入回收站的实体，顺序即彻底删除的顺序（先删依赖方）
+var trashEntities = []struct {
+	Type   TrashType
+	Entity trashEntity
+}{
+	{TrashTypeComment, trashEntity{&model.Comment{}, "comments", "LEFT(content, 100)"}},
+	{TrashTypeReview, trashEntity{&model.Review{}, "reviews", "title"}},
+	{TrashTypeFile, trashEntity{&model.File{}, "files", "name"}},
+	{TrashTypeProduct, trashEntity{&model.Product{}, "products", "name"}},
+	{TrashTypeBrand, trashEntity{&model.Brand{}, "brands", "name"}},
+	{TrashTypeUser, trashEntity{&model.User{}, "users", "name"}},
+}
+
+// trashParents 恢复前需确认未被删除的上级记录
+var trashParents = map[TrashType]struct {
+	column string
+	table  string
+}{
+	TrashTypeComment: {"review_id", "reviews"},
+	TrashTypeReview:  {"product_id", "products"},
+	TrashTypeProduct: {"brand_id", "brands"},
+	TrashTypeBrand:   {"parent_id", "brands"},
+}
+
+// trashChildren 彻底删除前需先行删除的下级记录，避免级联删除时遗漏关联数据的清理
+var trashChildren = map[TrashType]struct {
+	Type   TrashType
+	column string
+}{
+	TrashTypeReview:  {TrashTypeComment, "review_id"},
+	TrashTypeProduct: {TrashTypeReview, "product_id"},
+}
+
+// brandInUseCondition 品牌仍被产品或子品牌引用（外键为 RESTRICT，无法彻底删除）
+const brandInUseCondition = "(EXISTS (SELECT 1 FROM products WHERE products.brand_id = brands.id)" +
+	" OR EXISTS (SELECT 1 FROM brands AS sub_brands WHERE sub_brands.parent_id = brands.id))"
+
+// TrashItem 回收站记录
+type TrashItem struct {
+	ID        string    `json:"id"`
+	Type      TrashType `json:"type"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deletedAt"`
+	PurgeAt   time.Time `json:"purgeAt"` // 自动彻底删除时间
+}
+
+type TrashService struct {
+	db             *gorm.DB
+	cfg            *config.Config
+	accountService *AccountService
+	logger         *zap.Logger
+}
+
+func NewTrashService(db *gorm.DB, cfg *config.Config, accountService *AccountService, logger *zap.Logger) *TrashService {
+	return &TrashService{
+		db:             db,
+		cfg:            cfg,
+		accountService: accountService,
+		logger:         logger,
+	}
+}
+
+// getTrashEntity 根据类型获取实体
+func getTrashEntity(t TrashType) (trashEntity, error) {
+	for _, e := range trashEntities {
+		if e.Type == t {
+			return e.Entity, nil
+		}
+	}
+	return trashEntity{}, ErrInvalidTrashType
+}
+
+// retention 回收站保留时长
+func (s *TrashService) retention() time.Duration {
+	return time.Duration(s.cfg.Trash.RetentionDays) * 24 * time.Hour
+}
+
+// ListTrash 获取回收站列表，type 为空时返回所有类型
+func (s *TrashService) ListTrash(c *gin.Context, t TrashType, page, pageSize int) ([]*TrashItem, int64, error) {
+	var selects []string
+	for _, e := range trashEntities {
+		if t != "" && e.Type != t {
+			continue
+		}
+		selects = append(selects,
+			"SELECT CAST(id AS TEXT) AS id, '"+string(e.Type)+"' AS type, CAST("+e.Entity.nameColumn+" AS TEXT) AS name, deleted_at"+
+				" FROM "+e.Entity.table+" WHERE deleted_at IS NOT NULL")
+	}
+	if len(selects) == 0 {
+		return nil, 0, ErrInvalidTrashType
+	}
+	union := strings.Join(selects, " UNION ALL ")
+
+	var total int64
+	if err := s.db.Raw("SELECT COUNT(*) FROM (" + union + ") AS trash").Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []*TrashItem
+	if err := s.db.Raw("SELECT * FROM ("+union+") AS trash ORDER BY deleted_at DESC LIMIT ? OFFSET ?",
+		pageSize, (page-1)*pageSize).Scan(&items).Error; err != nil {
+		return nil, 0, err
+	}
+
+	retention := s.retention()
+	for _, item := range items {
+		item.PurgeAt = item.DeletedAt.Add(retention)
+	}
+
+	return items, total, nil
+}
+
+// Restore 从回收站恢复记录
+func (s *TrashService) Restore(c *gin.Context, t TrashType, id string) error {
+	entity, err := getTrashEntity(t)
+	if err != nil {
+		return err
+	}
+
+	// 回收站中的文件不占用MD5唯一索引，恢复前确认没有内容相同的文件
+	if t == TrashTypeFile {
+		var count int64
+		if err := s.db.Model(&model.File{}).
+			Where("md5 = (?)", s.db.Unscoped().Model(&model.File{}).Select("md5").Where("CAST(id AS TEXT) = ?", id)).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrTrashFileConflict
+		}
+	}
+
+	// 上级记录仍在回收站中时，恢复后的记录无法被访问
+	if parent, ok := trashParents[t]; ok {
+		var count int64
+		if err := s.db.Table(entity.table+" AS child").
+			Joins("JOIN "+parent.table+" AS parent ON parent.id = child."+parent.column).
+			Where("CAST(child.id AS TEXT) = ? AND parent.deleted_at IS NOT NULL", id).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrTrashParentInTrash
+		}
+	}
+
+	result := s.db.Unscoped().Model(entity.model).
+		Where("CAST(id AS TEXT) = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTrashItemNotFound
+	}
+	return nil
+}
+
+// Purge 彻底删除回收站中的记录
+func (s *TrashService) Purge(c *gin.Context, t TrashType, id string) error {
+	entity, err := getTrashEntity(t)
+	if err != nil {
+		return err
+	}
+
+	if t == TrashTypeBrand {
+		var count int64
+		if err := s.db.Unscoped().Model(&model.Brand{}).
+			Where("CAST(id AS TEXT) = ? AND "+brandInUseCondition, id).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrTrashBrandInUse
+		}
+	}
+
+	rows, err := s.purge(c, s.db.Where("CAST(id AS TEXT) = ?", id), t, entity)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTrashItemNotFound
+	}
+	return nil
+}
+
+// PurgeExpired 彻底删除超过保留期的记录
+func (s *TrashService) PurgeExpired(ctx context.Context) {
+	cutoff := time.Now().Add(-s.retention())
+	for _, e := range trashEntities {
+		rows, err := s.purge(ctx, s.db.WithContext(ctx).Where("deleted_at < ?", cutoff), e.Type, e.Entity)
+		if err != nil {
+			s.logger.Error("清理回收站失败",
+				zap.String("type", string(e.Type)),
+				zap.Error(err),
+			)
+			continue
+		}
+		if rows > 0 {
+			s.logger.Info("清理回收站完成",
+				zap.String("type", string(e.Type)),
+				zap.Int64("rows", rows),
+			)
+		}
+	}
+}
+
+// StartPurgeWorker 定期清理过期的回收站记录
+func (s *TrashService) StartPurgeWorker(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.Trash.PurgeInterval)
+		defer ticker.Stop()
+
+		s.PurgeExpired(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.PurgeExpired(ctx)
+			}
+		}
+	}()
+}
+
+// purge 彻底删除满足条件的已删除记录，文件同时删除物理文件，用户改为匿名化
+func (s *TrashService) purge(ctx context.Context, query *gorm.DB, t TrashType, entity trashEntity) (int64, error) {
+	query = query.Unscoped().Where("deleted_at IS NOT NULL")
+
+	// 仍被引用的品牌暂不删除，待产品和子品牌清理后再处理
+	if t == TrashTypeBrand {
+		var inUse []string
+		if err := query.Session(&gorm.Session{}).Model(&model.Brand{}).
+			Where(brandInUseCondition).
+			Pluck("CAST(id AS TEXT)", &inUse).Error; err != nil {
+			return 0, err
+		}
+		if len(inUse) > 0 {
+			s.logger.Warn("品牌仍被引用，跳过彻底删除", zap.Strings("brandIDs", inUse))
+		}
+		query = query.Where("NOT " + brandInUseCondition)
+	}
+
+	if t == TrashTypeFile {
+		var files []model.File
+		if err := query.Session(&gorm.Session{}).Find(&files).Error; err != nil {
+			return 0, err
+		}
+		if len(files) == 0 {
+			return 0, nil
+		}
+		ids := make([]string, 0, len(files))
+		for _, f := range files {
+			ids = append(ids, f.ID)
+		}
+		if err := s.db.Unscoped().Delete(&model.File{}, "id IN ?", ids).Error; err != nil {
+			return 0, err
+		}
+		for _, f := range files {
+			if err := os.Remove(f.Path); err != nil && !os.IsNotExist(err) {
+				s.logger.Warn("删除物理文件失败",
+					zap.String("path", f.Path),
+					zap.Error(err),
+				)
+			}
+		}
+		return int64(len(files)), nil
+	}
+
+	var ids []string
+	if err := query.Session(&gorm.Session{}).Model(entity.model).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	// 删除用户会级联删除其测评、评论和评分，改为与注销账号相同的匿名化处理
+	if t == TrashTypeUser {
+		for _, id := range ids {
+			if err := s.accountService.anonymizeUser(ctx, id, nil); err != nil {
+				return 0, err
+			}
+		}
+		return int64(len(ids)), nil
+	}
+
+	// 记录与关联数据在同一事务中按ID删除
+	var rows int64
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 期间被恢复的记录不再删除
+		if err := tx.Unscoped().Model(entity.model).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND deleted_at IS NOT NULL", ids).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		var err error
+		rows, err = purgeRows(tx, t, ids)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
+// purgeRows 在事务中按ID彻底删除记录（不论是否已软删除），下级记录和关联数据一并删除
+func purgeRows(tx *gorm.DB, t TrashType, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	entity, err := getTrashEntity(t)
+	if err != nil {
+		return 0, err
+	}
+
+	if child, ok := trashChildren[t]; ok {
+		childEntity, err := getTrashEntity(child.Type)
+		if err != nil {
+			return 0, err
+		}
+		var childIDs []string
+		if err := tx.Unscoped().Model(childEntity.model).
+			Where(child.column+" IN ?", ids).
+			Pluck("id", &childIDs).Error; err != nil {
+			return 0, err
+		}
+		if _, err := purgeRows(tx, child.Type, childIDs); err != nil {
+			return 0, err
+		}
+	}
+
+	result := tx.Unscoped().Where("id IN ?", ids).Delete(entity.model)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	// 彻底删除后释放其历史 slug
+	switch t {
+	case TrashTypeReview, TrashTypeProduct, TrashTypeBrand:
+		if err := tx.Where("entity_type = ? AND entity_id IN ?", string(t), ids).
+			Delete(&model.SlugHistory{}).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	// 彻底删除后清理对应的反馈和举报记录
+	switch t {
+	case TrashTypeReview, TrashTypeComment:
+		for _, related := range []interface{}{&model.Reaction{}, &model.Report{}, &model.ReportCase{}} {
+			if err := tx.Where("target_type = ? AND target_id IN ?", string(t), ids).
+				Delete(related).Error; err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	// 彻底删除后清理对该对象的关注
+	switch t {
+	case TrashTypeProduct, TrashTypeBrand:
+		if err := tx.Where("target_type = ? AND target_id IN ?", model.FollowTargetType(t), ids).
+			Delete(&model.Follow{}).Error; err != nil {
+			return 0, err
+		}
+	}
+	return result.RowsAffected, nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"errors"
@@ -38,14 +39,16 @@ type UploadService struct {
 	logger *zap.Logger
 	cfg    *config.StorageConfig
 	uploadInfos sync.Map
+	realtimeService *RealtimeService
 }
 
 // NewUploadService 创建上传服务
-func NewUploadService(db *gorm.DB, logger *zap.Logger, cfg *config.StorageConfig) *UploadService {
+func NewUploadService(db *gorm.DB, logger *zap.Logger, cfg *config.StorageConfig, realtimeService *RealtimeService) *UploadService {
 	return &UploadService{
 		db:     db,
 		logger: logger,
 		cfg:    cfg,
+		realtimeService: realtimeService,
 	}
 }
 
@@ -596,12 +599,36 @@ func (s *UploadService) getUploadInfo(fileID string) (*model.FileUploadInfo, err
 	return nil, fmt.Errorf("上传信息不存在")
 }
 
-// saveUploadInfo 保存上传信息
+// saveUploadInfo 保存上传信息，并向订阅了该上传的连接推送最新进度
 func (s *UploadService) saveUploadInfo(fileID string, info *model.FileUploadInfo) error {
 	s.uploadInfos.Store(fileID, info)
+	s.publishProgress(fileID, info)
 	return nil
 }
 
+// publishProgress 推送上传进度，客户端无需轮询 GetUploadProgress
+func (s *UploadService) publishProgress(fileID string, info *model.FileUploadInfo) {
+	if s.realtimeService == nil {
+		return
+	}
+
+	progress := float64(0)
+	if info.Size > 0 {
+		progress = float64(info.UploadedSize) / float64(info.Size) * 100
+	}
+	s.realtimeService.Publish(context.Background(), RealtimeTopic(RealtimeTopicUpload, fileID), RealtimeEventUploadProgress, &model.FileUploadStatus{
+		FileID:       fileID,
+		Status:       info.Status,
+		TotalSize:    info.Size,
+		UploadedSize: info.UploadedSize,
+		ChunkSize:    info.ChunkSize,
+		ChunksCount:  info.ChunkCount,
+		Progress:     progress,
+		LastUpdated:  info.UpdatedAt.Unix(),
+		Error:        info.ErrorMessage,
+	})
+}
+
 // deleteUploadInfo 删除上传信息
 func (s *UploadService) deleteUploadInfo(fileID string) {
 	s.uploadInfos.Delete(fileID)
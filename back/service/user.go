@@ -98,7 +98,7 @@ func (s *UserService) ListCurrentUserFavorites(c *gin.Context, userID string, pa
 }
 
 // AddToFavorites 添加收藏
-func (s *UserService) AddToFavorites(c *gin.Context, userID, productID string) error {
+func (s *UserService) AddToFavorites(c *gin.Context, userID string, productID uint) error {
 	// 检查产品是否存在
 	var product model.Product
 	if err := s.db.WithContext(c).First(&product, "id = ?", productID).Error; err != nil {
@@ -123,7 +123,7 @@ func (s *UserService) AddToFavorites(c *gin.Context, userID, productID string) e
 }
 
 // RemoveFromFavorites 取消收藏
-func (s *UserService) RemoveFromFavorites(c *gin.Context, userID, productID string) error {
+func (s *UserService) RemoveFromFavorites(c *gin.Context, userID string, productID uint) error {
 	return s.db.WithContext(c).Where("user_id = ? AND product_id = ?", userID, productID).
 		Delete(&model.UserFavorite{}).Error
 }
@@ -175,3 +175,15 @@ func (s *UserService) DeleteUser(c *gin.Context, userID string) error {
 func (s *UserService) UpdateUserStatus(c *gin.Context, userID string, status model.UserStatus) error {
 	return s.db.WithContext(c).Model(&model.User{}).Where("id = ?", userID).Update("status", status).Error
 }
+
+// UpdateUserTrustLevel 更新用户评论信任等级（管理员）
+func (s *UserService) UpdateUserTrustLevel(c *gin.Context, userID string, level model.TrustLevel) error {
+	result := s.db.WithContext(c).Model(&model.User{}).Where("id = ?", userID).Update("trust_level", level)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
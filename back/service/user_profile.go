@@ -0,0 +1,189 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"beicun/back/model"
+)
+
+var (
+	ErrInvalidProfileField      = errors.New("无效的主页字段")
+	ErrInvalidProfileVisibility = errors.New("无效的可见性设置")
+	ErrFavoritesHidden          = errors.New("该用户未公开收藏")
+)
+
+// PublicProfileResponse 用户公开主页，未公开的字段不返回
+type PublicProfileResponse struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	Avatar          string  `json:"avatar"`
+	Bio             *string `json:"bio,omitempty"`
+	ReviewCount     *int64  `json:"reviewCount,omitempty"`   // 已发布的测评数
+	CommentCount    *int64  `json:"commentCount,omitempty"`  // 已公开的评论数
+	FollowerCount   *int64  `json:"followerCount,omitempty"` // 关注该作者的用户数
+	JoinedAt        *string `json:"joinedAt,omitempty"`
+	FavoritesPublic bool    `json:"favoritesPublic"` // 是否可查看收藏列表
+}
+
+// ProfilePrivacyRequest 主页可见性设置，只更新提交的字段
+type ProfilePrivacyRequest struct {
+	Privacy model.ProfilePrivacy `json:"privacy" binding:"required"` // 字段到可见性（PUBLIC、PRIVATE）
+}
+
+// ProfilePrivacyResponse 主页可见性设置，包含所有字段
+type ProfilePrivacyResponse struct {
+	Privacy model.ProfilePrivacy `json:"privacy"`
+}
+
+// GetPublicProfile 获取用户公开主页，本人和管理员可以看到全部字段
+func (s *UserService) GetPublicProfile(c *gin.Context, userID, viewerID string, viewerRole model.UserRole) (*PublicProfileResponse, error) {
+	user, err := s.getPublicUser(c, userID)
+	if err != nil {
+		return nil, err
+	}
+	visible := profileVisibility(user, viewerID, viewerRole)
+
+	profile := &PublicProfileResponse{
+		ID:              user.ID,
+		Name:            user.Name,
+		Avatar:          user.Avatar,
+		FavoritesPublic: visible(model.ProfileFieldFavorites),
+	}
+	if visible(model.ProfileFieldBio) {
+		profile.Bio = &user.Bio
+	}
+	if visible(model.ProfileFieldJoinDate) {
+		joinedAt := user.CreatedAt.Format("2006-01-02 15:04:05")
+		profile.JoinedAt = &joinedAt
+	}
+	if visible(model.ProfileFieldStats) {
+		var reviews, comments, followers int64
+		db := s.db.WithContext(c)
+		if err := db.Model(&model.Review{}).
+			Where("user_id = ? AND status = ?", user.ID, model.ReviewStatusPublished).
+			Count(&reviews).Error; err != nil {
+			return nil, ErrInternal
+		}
+		if err := db.Model(&model.Comment{}).
+			Where("user_id = ? AND status = ?", user.ID, model.CommentStatusApproved).
+			Count(&comments).Error; err != nil {
+			return nil, ErrInternal
+		}
+		if err := db.Model(&model.Follow{}).
+			Where("target_type = ? AND target_id = ?", model.FollowTargetAuthor, user.ID).
+			Count(&followers).Error; err != nil {
+			return nil, ErrInternal
+		}
+		profile.ReviewCount, profile.CommentCount, profile.FollowerCount = &reviews, &comments, &followers
+	}
+	return profile, nil
+}
+
+// ListPublicFavorites 获取用户公开的收藏列表
+func (s *UserService) ListPublicFavorites(c *gin.Context, userID, viewerID string, viewerRole model.UserRole, page, pageSize int) ([]*ProductBrief, int64, error) {
+	user, err := s.getPublicUser(c, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !profileVisibility(user, viewerID, viewerRole)(model.ProfileFieldFavorites) {
+		return nil, 0, ErrFavoritesHidden
+	}
+
+	products, total, err := s.ListCurrentUserFavorites(c, user.ID, page, pageSize)
+	if err != nil {
+		return nil, 0, ErrInternal
+	}
+	briefs := make([]*ProductBrief, len(products))
+	for i := range products {
+		brief, err := toProductBrief(&products[i])
+		if err != nil {
+			return nil, 0, ErrInternal
+		}
+		briefs[i] = brief
+	}
+	return briefs, total, nil
+}
+
+// GetProfilePrivacy 获取主页可见性设置，未设置的字段返回默认值
+func (s *UserService) GetProfilePrivacy(c *gin.Context, userID string) (*ProfilePrivacyResponse, error) {
+	var user model.User
+	if err := s.db.WithContext(c).Select("id", "privacy").First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrInternal
+	}
+	return getProfilePrivacyResponse(user.Privacy), nil
+}
+
+// UpdateProfilePrivacy 更新主页可见性设置，未提交的字段保持原设置
+func (s *UserService) UpdateProfilePrivacy(c *gin.Context, userID string, req *ProfilePrivacyRequest) (*ProfilePrivacyResponse, error) {
+	for field, visibility := range req.Privacy {
+		if !field.IsValid() {
+			return nil, ErrInvalidProfileField
+		}
+		if !visibility.IsValid() {
+			return nil, ErrInvalidProfileVisibility
+		}
+	}
+
+	var user model.User
+	err := s.db.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "privacy").First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+		if user.Privacy == nil {
+			user.Privacy = make(model.ProfilePrivacy, len(req.Privacy))
+		}
+		for field, visibility := range req.Privacy {
+			user.Privacy[field] = visibility
+		}
+		return tx.Model(&user).Select("privacy").Updates(&user).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrInternal
+	}
+	return getProfilePrivacyResponse(user.Privacy), nil
+}
+
+// getPublicUser 获取可公开展示的用户，已封禁和已注销的用户视为不存在
+func (s *UserService) getPublicUser(c *gin.Context, userID string) (*model.User, error) {
+	var user model.User
+	if err := s.db.WithContext(c).
+		Where("id = ? AND status NOT IN ?", userID, model.HiddenUserStatuses).
+		First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrInternal
+	}
+	return &user, nil
+}
+
+// profileVisibility 返回判断字段对访问者是否可见的函数
+func profileVisibility(user *model.User, viewerID string, viewerRole model.UserRole) func(model.ProfileField) bool {
+	full := viewerID == user.ID || viewerRole == model.UserRoleAdmin
+	return func(field model.ProfileField) bool {
+		return full || user.Privacy.IsPublic(field)
+	}
+}
+
+func getProfilePrivacyResponse(privacy model.ProfilePrivacy) *ProfilePrivacyResponse {
+	response := &ProfilePrivacyResponse{Privacy: make(model.ProfilePrivacy, len(model.ProfileFields))}
+	for _, field := range model.ProfileFields {
+		if privacy.IsPublic(field) {
+			response.Privacy[field] = model.ProfileVisibilityPublic
+		} else {
+			response.Privacy[field] = model.ProfileVisibilityPrivate
+		}
+	}
+	return response
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"beicun/back/config"
+	"beicun/back/utils"
+)
+
+// ViewTarget 统计浏览量的实体
+type ViewTarget string
+
+const (
+	ViewTargetProduct ViewTarget = "product"
+	ViewTargetReview  ViewTarget = "review"
+)
+
+// viewColumns 实体对应的表和浏览量字段
+var viewColumns = map[ViewTarget]struct {
+	table  string
+	column string
+}{
+	ViewTargetProduct: {"products", "view_count"},
+	ViewTargetReview:  {"reviews", "views"},
+}
+
+const (
+	viewSeenKeyPrefix    = "views:seen:"    // 访客去重标记
+	viewPendingKeyPrefix = "views:pending:" // 待写入的浏览量
+	viewFlushLockKey     = "views:flush:lock"
+)
+
+// releaseLockScript 仅在锁仍由自己持有时释放，避免锁过期后误删其他实例的锁
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// takeHashScript 原子地取出并删除待写入的浏览量
+var takeHashScript = redis.NewScript(`
+local counts = redis.call("HGETALL", KEYS[1])
+redis.call("DEL", KEYS[1])
+return counts
+`)
+
+// defaultBotUserAgents 常见爬虫和脚本的 User-Agent 关键字
+var defaultBotUserAgents = []string{
+	"bot", "spider", "crawl", "slurp", "curl", "wget", "python", "go-http-client",
+	"java/", "okhttp", "headless", "lighthouse", "facebookexternalhit", "preview",
+}
+
+type ViewService struct {
+	db     *gorm.DB
+	redis  *redis.Client
+	cfg    *config.Config
+	botUAs []string
+}
+
+func NewViewService(db *gorm.DB, redis *redis.Client, cfg *config.Config) *ViewService {
+	botUAs := append([]string{}, defaultBotUserAgents...)
+	for _, ua := range cfg.Views.BotUserAgents {
+		botUAs = append(botUAs, strings.ToLower(ua))
+	}
+	return &ViewService{
+		db:     db,
+		redis:  redis,
+		cfg:    cfg,
+		botUAs: botUAs,
+	}
+}
+
+// RecordView 记录一次浏览，同一访客在去重窗口内只计一次，爬虫不计数
+// 浏览量先缓存在 Redis 中，由后台任务定期写入数据库
+func (s *ViewService) RecordView(c *gin.Context, target ViewTarget, id string) {
+	if s == nil || s.isBot(c.Request.UserAgent()) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	seenKey := viewSeenKeyPrefix + string(target) + ":" + id + ":" + visitorID(c)
+	first, err := s.redis.SetNX(ctx, seenKey, 1, s.cfg.Views.DedupWindow).Result()
+	if err != nil {
+		zap.L().Warn("记录浏览去重标记失败", zap.String("key", seenKey), zap.Error(err))
+		return
+	}
+	if !first {
+		return
+	}
+
+	if err := s.redis.HIncrBy(ctx, viewPendingKeyPrefix+string(target), id, 1).Err(); err != nil {
+		zap.L().Warn("缓存浏览量失败", zap.String("target", string(target)), zap.String("id", id), zap.Error(err))
+	}
+}
+
+// isBot 根据 User-Agent 判断是否为爬虫，空 User-Agent 同样视为爬虫
+func (s *ViewService) isBot(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+	for _, keyword := range s.botUAs {
+		if strings.Contains(ua, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// visitorID 登录用户使用用户ID，匿名访客使用 IP 和 User-Agent 的摘要
+func visitorID(c *gin.Context) string {
+	if userID := utils.GetUserIDFromContext(c); userID != "" {
+		return "u:" + userID
+	}
+	sum := sha1.Sum([]byte(c.ClientIP() + "|" + c.Request.UserAgent()))
+	return "a:" + hex.EncodeToString(sum[:])
+}
+
+// FlushViews 将缓存的浏览量汇总写入数据库
+func (s *ViewService) FlushViews(ctx context.Context) {
+	// 多实例部署时只允许一个实例写入
+	token := uuid.NewString()
+	locked, err := s.redis.SetNX(ctx, viewFlushLockKey, token, s.cfg.Views.FlushInterval).Result()
+	if err != nil {
+		zap.L().Error("获取浏览量写入锁失败", zap.Error(err))
+		return
+	}
+	if !locked {
+		return
+	}
+	defer func() {
+		if err := releaseLockScript.Run(ctx, s.redis, []string{viewFlushLockKey}, token).Err(); err != nil {
+			zap.L().Warn("释放浏览量写入锁失败", zap.Error(err))
+		}
+	}()
+
+	for target := range viewColumns {
+		if err := s.flushTarget(ctx, target); err != nil {
+			zap.L().Error("写入浏览量失败", zap.String("target", string(target)), zap.Error(err))
+		}
+	}
+}
+
+// flushTarget 写入单类实体的浏览量
+// 写入前先从 Redis 中取出并删除待写入数据，写入期间的新浏览累加到新的待写入键；
+// 写入失败时将本批数据加回，宁可漏计也不会重复计数
+func (s *ViewService) flushTarget(ctx context.Context, target ViewTarget) error {
+	pendingKey := viewPendingKeyPrefix + string(target)
+	values, err := takeHashScript.Run(ctx, s.redis, []string{pendingKey}).StringSlice()
+	if err != nil {
+		return err
+	}
+	counts := make(map[string]int64, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		count, err := strconv.ParseInt(values[i+1], 10, 64)
+		if err != nil {
+			continue
+		}
+		counts[values[i]] = count
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	columns := viewColumns[target]
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, count := range counts {
+			if err := tx.Table(columns.table).
+				Where("id = ?", id).
+				UpdateColumn(columns.column, gorm.Expr(columns.column+" + ?", count)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		pipe := s.redis.Pipeline()
+		for id, count := range counts {
+			pipe.HIncrBy(ctx, pendingKey, id, count)
+		}
+		if _, restoreErr := pipe.Exec(ctx); restoreErr != nil {
+			zap.L().Error("恢复待写入浏览量失败", zap.String("target", string(target)), zap.Error(restoreErr))
+		}
+		return err
+	}
+	return nil
+}
+
+// StartFlushWorker 定期将缓存的浏览量写入数据库
+func (s *ViewService) StartFlushWorker(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.Views.FlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.FlushViews(ctx)
+			}
+		}
+	}()
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"beicun/back/model"
 	"errors"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -91,6 +92,14 @@ func ClearUserContext(c *gin.Context) {
 	c.Set(UserKey, nil)
 }
 
+// GetUnitSystem 获取请求期望的单位制，优先读取 units 查询参数，其次读取 Accept-Units 请求头
+func GetUnitSystem(c *gin.Context) model.UnitSystem {
+	if units := c.Query("units"); units != "" {
+		return model.ParseUnitSystem(strings.ToLower(units))
+	}
+	return model.ParseUnitSystem(strings.ToLower(strings.TrimSpace(c.GetHeader("Accept-Units"))))
+}
+
 // RequireRole 检查用户是否具有指定角色
 func RequireRole(c *gin.Context, role model.UserRole) error {
 	userRole := GetUserRoleFromContext(c)
@@ -0,0 +1,111 @@
+package utils
+
+import "strings"
+
+// DiffOp 差异操作类型
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"  // 未变化
+	DiffInsert DiffOp = "insert" // 新增
+	DiffDelete DiffOp = "delete" // 删除
+)
+
+// DiffLine 行级差异
+type DiffLine struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// MaxDiffCells 行级差异计算允许的最大矩阵规模（去掉首尾相同行后的行数乘积），
+// 超出时不再计算最长公共子序列，直接将差异部分整体标记为删除和新增
+const MaxDiffCells = 4000000
+
+// DiffLines 基于最长公共子序列计算两段文本的行级差异
+func DiffLines(a, b string) []DiffLine {
+	x := splitLines(a)
+	y := splitLines(b)
+	lines := make([]DiffLine, 0, len(x)+len(y))
+
+	// 首尾相同的行不参与最长公共子序列计算
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+	for _, line := range x[:prefix] {
+		lines = append(lines, DiffLine{Op: DiffEqual, Text: line})
+	}
+	lines = append(lines, diffMiddle(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])...)
+	for _, line := range x[len(x)-suffix:] {
+		lines = append(lines, DiffLine{Op: DiffEqual, Text: line})
+	}
+
+	return lines
+}
+
+// diffMiddle 计算去掉首尾相同行后剩余部分的差异
+func diffMiddle(x, y []string) []DiffLine {
+	n, m := len(x), len(y)
+	lines := make([]DiffLine, 0, n+m)
+
+	// 超出规模限制时整体替换，避免 O(n·m) 内存占用过大
+	if n*m > MaxDiffCells {
+		for _, line := range x {
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: line})
+		}
+		for _, line := range y {
+			lines = append(lines, DiffLine{Op: DiffInsert, Text: line})
+		}
+		return lines
+	}
+
+	// lcs[i*(m+1)+j] 表示 x[i:] 与 y[j:] 的最长公共子序列长度
+	width := m + 1
+	lcs := make([]int32, (n+1)*width)
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else if lcs[(i+1)*width+j] >= lcs[i*width+j+1] {
+				lcs[i*width+j] = lcs[(i+1)*width+j]
+			} else {
+				lcs[i*width+j] = lcs[i*width+j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case x[i] == y[j]:
+			lines = append(lines, DiffLine{Op: DiffEqual, Text: x[i]})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: x[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: DiffInsert, Text: y[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		lines = append(lines, DiffLine{Op: DiffDelete, Text: x[i]})
+	}
+	for ; j < m; j++ {
+		lines = append(lines, DiffLine{Op: DiffInsert, Text: y[j]})
+	}
+
+	return lines
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []DiffLine
+	}{
+		{
+			name: "both empty",
+			want: []DiffLine{},
+		},
+		{
+			name: "identical",
+			a:    "a\nb",
+			b:    "a\nb",
+			want: []DiffLine{{DiffEqual, "a"}, {DiffEqual, "b"}},
+		},
+		{
+			name: "insert into empty",
+			b:    "a\nb",
+			want: []DiffLine{{DiffInsert, "a"}, {DiffInsert, "b"}},
+		},
+		{
+			name: "delete everything",
+			a:    "a\nb",
+			want: []DiffLine{{DiffDelete, "a"}, {DiffDelete, "b"}},
+		},
+		{
+			name: "change middle line",
+			a:    "a\nb\nc",
+			b:    "a\nx\nc",
+			want: []DiffLine{{DiffEqual, "a"}, {DiffDelete, "b"}, {DiffInsert, "x"}, {DiffEqual, "c"}},
+		},
+		{
+			name: "insert and delete around common lines",
+			a:    "a\nb\nc\nd",
+			b:    "b\nc\ne\nd",
+			want: []DiffLine{{DiffDelete, "a"}, {DiffEqual, "b"}, {DiffEqual, "c"}, {DiffInsert, "e"}, {DiffEqual, "d"}},
+		},
+		{
+			name: "crlf treated as lf",
+			a:    "a\r\nb",
+			b:    "a\nb",
+			want: []DiffLine{{DiffEqual, "a"}, {DiffEqual, "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffLines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesOverLimit(t *testing.T) {
+	// 首尾相同行不计入规模，中间部分超过限制时整体替换
+	n := 2100
+	x := make([]string, n)
+	y := make([]string, n)
+	for i := range x {
+		x[i] = "old" + strings.Repeat("a", i%7)
+		y[i] = "new" + strings.Repeat("b", i%5)
+	}
+	a := "head\n" + strings.Join(x, "\n") + "\ntail"
+	b := "head\n" + strings.Join(y, "\n") + "\ntail"
+
+	got := DiffLines(a, b)
+	if len(got) != 2*n+2 {
+		t.Fatalf("len(DiffLines()) = %d, want %d", len(got), 2*n+2)
+	}
+	if got[0] != (DiffLine{DiffEqual, "head"}) || got[len(got)-1] != (DiffLine{DiffEqual, "tail"}) {
+		t.Errorf("common prefix/suffix not kept: first %v, last %v", got[0], got[len(got)-1])
+	}
+	for i, line := range got[1 : n+1] {
+		if line.Op != DiffDelete || line.Text != x[i] {
+			t.Fatalf("line %d = %v, want delete %q", i+1, line, x[i])
+		}
+	}
+	for i, line := range got[n+1 : 2*n+1] {
+		if line.Op != DiffInsert || line.Text != y[i] {
+			t.Fatalf("line %d = %v, want insert %q", n+1+i, line, y[i])
+		}
+	}
+}
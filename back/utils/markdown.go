@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"bytes"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// 阅读速度
+const (
+	cjkCharsPerMinute = 400 // 中日韩文字每分钟阅读字数
+	wordsPerMinute    = 200 // 其他语言每分钟阅读词数
+)
+
+// TOCItem 目录项
+type TOCItem struct {
+	Level int    `json:"level"` // 标题级别 1-6
+	ID    string `json:"id"`    // 锚点ID
+	Text  string `json:"text"`  // 标题文本
+}
+
+// MarkdownImage 图片引用解析结果
+type MarkdownImage struct {
+	URL    string
+	Width  *int
+	Height *int
+}
+
+// RenderedMarkdown Markdown 渲染结果
+type RenderedMarkdown struct {
+	HTML        string    // 净化后的 HTML
+	TOC         []TOCItem // 目录
+	ReadingTime int       // 预计阅读时长（分钟）
+}
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	// 允许原始 HTML，统一交给白名单净化
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+var markdownPolicy = newMarkdownPolicy()
+
+// newMarkdownPolicy 在用户内容白名单基础上放开目录锚点、代码高亮和任务列表
+func newMarkdownPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}
+
+// RenderMarkdown 渲染并净化 Markdown，resolveImage 用于将图片引用替换为实际地址，返回 nil 表示保持原样
+func RenderMarkdown(source string, resolveImage func(dest string) *MarkdownImage) (*RenderedMarkdown, error) {
+	src := []byte(source)
+	doc := markdown.Parser().Parse(text.NewReader(src))
+
+	result := &RenderedMarkdown{TOC: make([]TOCItem, 0)}
+	ids := make(map[string]bool)
+	var plain strings.Builder
+
+	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading:
+			title := nodeText(node, src)
+			id, _ := UniqueSlug(title, "section", func(candidate string) (bool, error) {
+				return ids[candidate], nil
+			})
+			ids[id] = true
+			node.SetAttributeString("id", []byte(id))
+			result.TOC = append(result.TOC, TOCItem{Level: node.Level, ID: id, Text: title})
+		case *ast.Image:
+			if resolveImage == nil {
+				break
+			}
+			if image := resolveImage(string(node.Destination)); image != nil {
+				node.Destination = []byte(image.URL)
+				if image.Width != nil && image.Height != nil {
+					node.SetAttributeString("width", []byte(strconv.Itoa(*image.Width)))
+					node.SetAttributeString("height", []byte(strconv.Itoa(*image.Height)))
+				}
+			}
+		case *ast.Text:
+			plain.Write(node.Segment.Value(src))
+			plain.WriteByte(' ')
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				segment := lines.At(i)
+				plain.Write(segment.Value(src))
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, err
+	}
+
+	result.HTML = markdownPolicy.Sanitize(buf.String())
+	result.ReadingTime = readingTime(plain.String())
+	return result, nil
+}
+
+// MarkdownImageDestinations 提取 Markdown 中引用的图片地址
+func MarkdownImageDestinations(source string) []string {
+	src := []byte(source)
+	doc := markdown.Parser().Parse(text.NewReader(src))
+
+	dests := make([]string, 0)
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if image, ok := n.(*ast.Image); ok && entering {
+			dests = append(dests, string(image.Destination))
+		}
+		return ast.WalkContinue, nil
+	})
+	return dests
+}
+
+// nodeText 提取节点下的纯文本
+func nodeText(n ast.Node, src []byte) string {
+	var b strings.Builder
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := child.(type) {
+		case *ast.Text:
+			b.Write(node.Segment.Value(src))
+		case *ast.String:
+			b.Write(node.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(b.String())
+}
+
+// readingTime 估算阅读时长，中日韩文字按字计数，其他语言按词计数，不足 1 分钟按 1 分钟计，空文本为 0
+func readingTime(s string) int {
+	var cjk, words int
+	inWord := false
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+			unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				words++
+				inWord = true
+			}
+		default:
+			inWord = false
+		}
+	}
+
+	if cjk == 0 && words == 0 {
+		return 0
+	}
+	minutes := float64(cjk)/cjkCharsPerMinute + float64(words)/wordsPerMinute
+	return int(math.Max(1, math.Ceil(minutes)))
+}
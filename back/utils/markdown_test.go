@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownTOC(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []TOCItem
+	}{
+		{
+			name:   "no headings",
+			source: "plain text",
+			want:   []TOCItem{},
+		},
+		{
+			name:   "levels kept",
+			source: "# Intro\n\n## Sound *Stage*\n",
+			want:   []TOCItem{{Level: 1, ID: "intro", Text: "Intro"}, {Level: 2, ID: "sound-stage", Text: "Sound Stage"}},
+		},
+		{
+			name:   "duplicate titles get numbered ids",
+			source: "## Bass\n\n## Bass\n\n## Bass\n",
+			want:   []TOCItem{{Level: 2, ID: "bass", Text: "Bass"}, {Level: 2, ID: "bass-2", Text: "Bass"}, {Level: 2, ID: "bass-3", Text: "Bass"}},
+		},
+		{
+			name:   "unsluggable title falls back",
+			source: "## ???\n",
+			want:   []TOCItem{{Level: 2, ID: "section", Text: "???"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderMarkdown(tt.source, nil)
+			if err != nil {
+				t.Fatalf("RenderMarkdown() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.TOC, tt.want) {
+				t.Errorf("TOC = %+v, want %+v", got.TOC, tt.want)
+			}
+			for _, item := range tt.want {
+				if !strings.Contains(got.HTML, `id="`+item.ID+`"`) {
+					t.Errorf("HTML %q missing anchor %q", got.HTML, item.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownSanitize(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "script removed",
+			source:  "hello <script>alert(1)</script>",
+			want:    []string{"hello"},
+			notWant: []string{"<script", "alert(1)"},
+		},
+		{
+			name:    "event handler removed",
+			source:  `<img src="a.png" onerror="alert(1)">`,
+			notWant: []string{"onerror"},
+		},
+		{
+			name:    "javascript link removed",
+			source:  "[x](javascript:alert(1))",
+			notWant: []string{"javascript:"},
+		},
+		{
+			name:   "code language class kept",
+			source: "```go\nfmt.Println()\n```",
+			want:   []string{`class="language-go"`},
+		},
+		{
+			name:   "task list checkbox kept",
+			source: "- [x] done",
+			want:   []string{`type="checkbox"`, "checked"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderMarkdown(tt.source, nil)
+			if err != nil {
+				t.Fatalf("RenderMarkdown() error = %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(got.HTML, s) {
+					t.Errorf("HTML %q missing %q", got.HTML, s)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(got.HTML, s) {
+					t.Errorf("HTML %q contains %q", got.HTML, s)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownImages(t *testing.T) {
+	width, height := 640, 480
+	resolve := func(dest string) *MarkdownImage {
+		switch dest {
+		case "file-1":
+			return &MarkdownImage{URL: "/uploads/a.png", Width: &width, Height: &height}
+		case "file-2":
+			return &MarkdownImage{URL: "/uploads/b.png"}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:   "resolved with size",
+			source: "![a](file-1)",
+			want:   []string{`src="/uploads/a.png"`, `width="640"`, `height="480"`},
+		},
+		{
+			name:    "resolved without size",
+			source:  "![b](file-2)",
+			want:    []string{`src="/uploads/b.png"`},
+			notWant: []string{"width="},
+		},
+		{
+			name:   "unresolved kept",
+			source: "![c](https://example.com/c.png)",
+			want:   []string{`src="https://example.com/c.png"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderMarkdown(tt.source, resolve)
+			if err != nil {
+				t.Fatalf("RenderMarkdown() error = %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(got.HTML, s) {
+					t.Errorf("HTML %q missing %q", got.HTML, s)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(got.HTML, s) {
+					t.Errorf("HTML %q contains %q", got.HTML, s)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkdownImageDestinations(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{name: "none", source: "text [link](a.png)", want: []string{}},
+		{name: "in order", source: "![a](a.png)\n\n- ![b](b.png)", want: []string{"a.png", "b.png"}},
+		{name: "inside code ignored", source: "`![a](a.png)`", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MarkdownImageDestinations(tt.source); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MarkdownImageDestinations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadingTime(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{name: "empty", text: "", want: 0},
+		{name: "punctuation only", text: "... !!", want: 0},
+		{name: "short text rounds up to one", text: "hello world", want: 1},
+		{name: "words", text: strings.Repeat("word ", 201), want: 2},
+		{name: "cjk chars", text: strings.Repeat("音", 401), want: 2},
+		{name: "mixed", text: strings.Repeat("音", 200) + strings.Repeat(" word", 100), want: 1},
+		{name: "mixed over a minute", text: strings.Repeat("音", 300) + strings.Repeat(" word", 100), want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readingTime(tt.text); got != tt.want {
+				t.Errorf("readingTime() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
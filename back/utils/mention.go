@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 单条内容最多解析的提及数
+const maxMentions = 10
+
+// mentionPattern 匹配 @用户名，@ 前不能是英文字母或数字，避免误匹配邮箱地址
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.])@([\p{L}\p{N}_.\-]{1,50})`)
+
+// ParseMentions 解析内容中 @ 提及的用户名，按出现顺序去重
+func ParseMentions(content string) []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		name := strings.TrimRight(match[1], ".-")
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+		if len(names) == maxMentions {
+			break
+		}
+	}
+	return names
+}
@@ -0,0 +1,18 @@
+package utils
+
+import "math"
+
+// wilsonZ 95% 置信度对应的 z 值
+const wilsonZ = 1.96
+
+// WilsonScore 计算好评率的 Wilson 置信区间下界，样本越少得分越保守
+func WilsonScore(positive, negative int) float64 {
+	n := float64(positive + negative)
+	if n == 0 {
+		return 0
+	}
+
+	p := float64(positive) / n
+	z2 := wilsonZ * wilsonZ
+	return (p + z2/(2*n) - wilsonZ*math.Sqrt((p*(1-p)+z2/(4*n))/n)) / (1 + z2/n)
+}
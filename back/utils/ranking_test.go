@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWilsonScore(t *testing.T) {
+	tests := []struct {
+		name               string
+		positive, negative int
+		want               float64
+	}{
+		{name: "no votes", want: 0},
+		{name: "single positive", positive: 1, want: 0.206543},
+		{name: "single negative", negative: 1, want: 0},
+		{name: "even split", positive: 5, negative: 5, want: 0.236590},
+		{name: "small sample all positive", positive: 10, want: 0.722460},
+		{name: "large sample all positive", positive: 100, want: 0.963005},
+		{name: "small sample mostly positive", positive: 9, negative: 1, want: 0.595844},
+		{name: "large sample mostly positive", positive: 90, negative: 10, want: 0.825633},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WilsonScore(tt.positive, tt.negative); math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("WilsonScore(%d, %d) = %f, want %f", tt.positive, tt.negative, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWilsonScoreOrdering(t *testing.T) {
+	tests := []struct {
+		name          string
+		higher, lower [2]int
+	}{
+		{name: "more evidence ranks higher at same ratio", higher: [2]int{90, 10}, lower: [2]int{9, 1}},
+		{name: "better ratio ranks higher at same size", higher: [2]int{8, 2}, lower: [2]int{6, 4}},
+		{name: "many votes beat a single perfect vote", higher: [2]int{40, 10}, lower: [2]int{1, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			high := WilsonScore(tt.higher[0], tt.higher[1])
+			low := WilsonScore(tt.lower[0], tt.lower[1])
+			if high <= low {
+				t.Errorf("WilsonScore%v = %f, want greater than WilsonScore%v = %f", tt.higher, high, tt.lower, low)
+			}
+		})
+	}
+}
@@ -175,3 +175,13 @@ func parseInt(s string) (int, error) {
 	_, err := fmt.Sscanf(s, "%d", &result)
 	return result, err
 }
+
+// MovedPermanently 资源已迁移响应，Location 指向新地址
+func MovedPermanently(c *gin.Context, location string, data interface{}) {
+	c.Header("Location", location)
+	c.JSON(http.StatusMovedPermanently, Response{
+		Code:    301,
+		Message: "moved permanently",
+		Data:    data,
+	})
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"strconv"
+
+	"github.com/gosimple/slug"
+)
+
+// UniqueSlug 根据名称生成 slug，已被占用时追加数字后缀，直到 taken 返回 false
+func UniqueSlug(name, fallback string, taken func(string) (bool, error)) (string, error) {
+	base := slug.Make(name)
+	if base == "" {
+		base = fallback
+	}
+
+	candidate := base
+	for counter := 2; ; counter++ {
+		used, err := taken(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !used {
+			return candidate, nil
+		}
+		candidate = base + "-" + strconv.Itoa(counter)
+	}
+}
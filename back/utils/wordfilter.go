@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"unicode"
+)
+
+// WordFilter 基于字典树的敏感词过滤器
+// 匹配前会统一大小写和全角半角，并忽略空格和标点，避免用"敏 感*词"之类的写法绕过
+type WordFilter struct {
+	root *wordNode
+	size int
+}
+
+type wordNode struct {
+	children map[rune]*wordNode
+	word     string // 非空表示此处为一个词的结尾
+}
+
+// NewWordFilter 创建敏感词过滤器
+func NewWordFilter(words []string) *WordFilter {
+	f := &WordFilter{root: &wordNode{}}
+	for _, word := range words {
+		f.Add(word)
+	}
+	return f
+}
+
+// Add 添加敏感词
+func (f *WordFilter) Add(word string) {
+	runes := normalizeWord(word)
+	if len(runes) == 0 {
+		return
+	}
+
+	node := f.root
+	for _, r := range runes {
+		if node.children == nil {
+			node.children = make(map[rune]*wordNode)
+		}
+		next, ok := node.children[r]
+		if !ok {
+			next = &wordNode{}
+			node.children[r] = next
+		}
+		node = next
+	}
+	if node.word == "" {
+		f.size++
+	}
+	node.word = word
+}
+
+// Len 敏感词数量
+func (f *WordFilter) Len() int {
+	return f.size
+}
+
+// Find 查找文本中出现的敏感词，按首次出现顺序去重返回
+func (f *WordFilter) Find(text string) []string {
+	found := make([]string, 0)
+	if f == nil || f.size == 0 {
+		return found
+	}
+
+	runes := normalizeWord(text)
+	seen := make(map[string]bool)
+	for i := range runes {
+		node := f.root
+		for j := i; j < len(runes); j++ {
+			next, ok := node.children[runes[j]]
+			if !ok {
+				break
+			}
+			node = next
+			if node.word != "" && !seen[node.word] {
+				seen[node.word] = true
+				found = append(found, node.word)
+			}
+		}
+	}
+	return found
+}
+
+// normalizeWord 转为小写半角字符并去掉空白、标点和符号
+func normalizeWord(s string) []rune {
+	runes := make([]rune, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '　':
+			continue
+		case r >= '！' && r <= '～':
+			// 全角字符转半角
+			r -= 0xFEE0
+		}
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			continue
+		}
+		runes = append(runes, unicode.ToLower(r))
+	}
+	return runes
+}
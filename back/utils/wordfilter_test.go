@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWordFilterFind(t *testing.T) {
+	filter := NewWordFilter([]string{"敏感词", "敏感", "Spam", "ab", "abc", "  ", "!!"})
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "no match", text: "正常的评论内容", want: []string{}},
+		{name: "empty text", text: "", want: []string{}},
+		{name: "overlapping words", text: "这是敏感词", want: []string{"敏感", "敏感词"}},
+		{name: "case insensitive", text: "buy SPAM now", want: []string{"Spam"}},
+		{name: "full width letters", text: "ＳＰＡＭ", want: []string{"Spam"}},
+		{name: "spaces and punctuation ignored", text: "敏 感*词", want: []string{"敏感", "敏感词"}},
+		{name: "full width space ignored", text: "敏　感", want: []string{"敏感"}},
+		{name: "deduplicated in first occurrence order", text: "abc spam ab spam", want: []string{"ab", "abc", "Spam"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter.Find(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWordFilterLen(t *testing.T) {
+	tests := []struct {
+		name  string
+		words []string
+		want  int
+	}{
+		{name: "empty", want: 0},
+		{name: "blank and punctuation skipped", words: []string{"", " ", "!?"}, want: 0},
+		{name: "normalized duplicates counted once", words: []string{"spam", "SPAM", "s p a m"}, want: 1},
+		{name: "prefix words counted separately", words: []string{"ab", "abc"}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewWordFilter(tt.words).Len(); got != tt.want {
+				t.Errorf("Len() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWordFilterEmpty(t *testing.T) {
+	var filter *WordFilter
+	if got := filter.Find("敏感词"); len(got) != 0 {
+		t.Errorf("nil filter Find() = %v, want empty", got)
+	}
+	if got := NewWordFilter(nil).Find("敏感词"); len(got) != 0 {
+		t.Errorf("empty filter Find() = %v, want empty", got)
+	}
+}